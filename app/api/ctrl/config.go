@@ -1,6 +1,7 @@
 package ctrl
 
 import (
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/notif/email"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/subs"
@@ -9,6 +10,7 @@ import (
 // ConfigAggregate struct holds all necessary app configuration parameters.
 type ConfigAggregate struct {
 	Api          Config
+	Currency     currency.Config
 	Rate         rate.Config
 	Subscription subs.Config
 	Email        email.Config
@@ -20,4 +22,9 @@ type Config struct {
 	Path    string
 	Version string
 	Origin  string
+	// URL is the public base URL the links sent to the subscribers point at.
+	URL string
+	// AdminToken is the bearer token the admin and subscription management routes are authorized with,
+	// the routes are not served when it is "-".
+	AdminToken string
 }
@@ -6,9 +6,13 @@ The handlers then interact with the domain logic to process the request and gene
 package ctrl
 
 import (
+	"context"
 	"net/http"
 	"os"
+	"sync"
 
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/subs"
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
@@ -20,8 +24,15 @@ type App struct {
 	log *logger.Logger
 	web *web.Web
 	bus *event.Bus
+
+	jobs []Job
+	stop context.CancelFunc
+	wg   sync.WaitGroup
 }
 
+// Job is a function run in background until the application is closed.
+type Job func(ctx context.Context)
+
 // Route is a function that defines an application route.
 type Route func(*App) error
 
@@ -35,24 +46,41 @@ func New(cfg ConfigAggregate, sig chan os.Signal, log *logger.Logger) (*App, err
 		web.WithRecover(log),
 	}
 
-	api := App{
+	// The links signed for the notifications are verified by the subscriptions, so they share the signer.
+	signer, err := subs.NewSigner(cfg.Subscription.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// The currency codes are validated against the registry of ISO 4217 currencies and configured crypto assets.
+	crypto, err := currency.ParseCrypto(cfg.Currency.Crypto...)
+	if err != nil {
+		return nil, err
+	}
+
+	reg := currency.NewRegistry(append(currency.ISO4217(), crypto...)...)
+
+	api := &App{
 		sig: sig,
 		log: log,
 		web: web.New(sig, mws...),
 		bus: event.NewBus(log),
 	}
 
-	err := api.Routes(
-		WithRate(cfg),
-		WithSubscription(cfg),
-		WithNotification(cfg),
+	err = api.Routes(
+		WithCurrency(cfg, reg),
+		WithRate(cfg, reg),
+		WithSubscription(cfg, reg, signer),
+		WithNotification(cfg, signer),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &api, nil
+	api.start()
+
+	return api, nil
 }
 
 // Handler returns the web handler.
@@ -60,6 +88,35 @@ func (a *App) Handler() http.Handler {
 	return a.web
 }
 
+// Go schedules the job to be run in background once all routes are applied.
+func (a *App) Go(job Job) {
+	a.jobs = append(a.jobs, job)
+}
+
+// start runs the scheduled jobs, they are started last, so all event listeners are subscribed by then.
+func (a *App) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stop = cancel
+
+	for _, job := range a.jobs {
+		job := job
+
+		a.wg.Add(1)
+
+		go func() {
+			defer a.wg.Done()
+			job(ctx)
+		}()
+	}
+}
+
+// Close ends the long-lived streams served by the application and waits for the background jobs to stop.
+func (a *App) Close() {
+	a.web.Close()
+	a.stop()
+	a.wg.Wait()
+}
+
 // Routes applies all application routes.
 func (a *App) Routes(routes ...Route) error {
 	for i := range routes {
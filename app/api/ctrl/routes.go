@@ -1,62 +1,279 @@
 package ctrl
 
 import (
+	"errors"
 	"net/http"
 	"path"
+	"strings"
+	"time"
 
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/notif"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/notif/email"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/notif/tmpl"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate/curxrt"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/subs"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/filestore"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
 )
 
+var ErrNoProviders = errors.New("no exchange rate providers available")
+
 const (
-	pathRate       = "/rate"
-	pathSubscribe  = "/subscribe"
-	pathSendEmails = "/sendEmails"
+	pathRate           = "/rate"
+	pathRates          = "/rates"
+	pathRateConsensus  = "/rate/consensus"
+	pathRateHistory    = "/rate/history"
+	pathRateCandles    = "/rate/candles"
+	pathRateStream     = "/rate/stream"
+	pathRateSocket     = "/rate/ws"
+	pathConvert        = "/convert"
+	pathCurrencies     = "/currencies"
+	pathAdminBreakers  = "/admin/breakers"
+	pathAdminRanking   = "/admin/ranking"
+	pathAdminProviders = "/admin/providers"
+	pathSubscribe      = "/subscribe"
+	pathUnsubscribe    = "/unsubscribe"
+	pathConfirm        = "/confirm"
+	pathSubscriptions  = "/subscriptions"
+	pathSubscription   = "/subscriptions/:id"
+	pathSendEmails     = "/sendEmails"
 )
 
+// WithCurrency set-ups routes for listing supported currencies.
+func WithCurrency(cfg ConfigAggregate, reg *currency.Registry) Route {
+	return func(app *App) error {
+		grp := path.Join(cfg.Api.Path, cfg.Api.Version)
+		h := currency.NewHandler(reg)
+
+		app.web.Handle(http.MethodGet, grp, pathCurrencies, h.Currencies)
+
+		return nil
+	}
+}
+
 // WithRate set-ups routes for handling HTTP requests related to currency exchange rates.
-func WithRate(cfg ConfigAggregate) Route {
+func WithRate(cfg ConfigAggregate, reg *currency.Registry) Route {
 	return func(app *App) error {
 		grp := path.Join(cfg.Api.Path, cfg.Api.Version)
 		clt := new(http.Client)
-		provs := []rate.ExchangeRateProvider{
-			curxrt.NewProvider[curxrt.ExchangeRateHost](curxrt.Config(cfg.Rate.Provider.ExchangeRateHost), clt),
-			curxrt.NewProvider[curxrt.AlphaVantage](curxrt.Config(cfg.Rate.Provider.AlphaVantage), clt),
-			curxrt.NewProvider[curxrt.CoinYep](curxrt.Config(cfg.Rate.Provider.CoinYep), clt),
-			curxrt.NewProvider[curxrt.CoinApi](curxrt.Config(cfg.Rate.Provider.CoinApi), clt),
-			curxrt.NewProvider[curxrt.Ninjas](curxrt.Config(cfg.Rate.Provider.Ninjas), clt),
+
+		quotas, err := curxrt.NewQuotas(filestore.NewAppendLog[curxrt.Usage](cfg.Rate.RepoData), cfg.Rate.Poll.Reserve)
+		if err != nil {
+			return err
+		}
+
+		rtr := curxrt.NewRetrier(curxrt.RetryPolicy(cfg.Rate.Retry))
+		defs, err := curxrt.LoadDefinitions(cfg.Rate.Providers)
+		if err != nil {
+			return err
+		}
+
+		var (
+			provs []rate.ExchangeRateProvider
+			names []string
+		)
+
+		for _, def := range curxrt.Arrange(defs, cfg.Rate.Order...) {
+			switch {
+			case !def.IsEnabled():
+				app.log.Infow("provider skipped", "provider", def.Name, "reason", "disabled")
+			case !def.HasCredentials():
+				app.log.Warnw("provider skipped", "provider", def.Name, "reason", "missing credentials")
+			default:
+				provs = append(provs, curxrt.NewDefinedProvider(def, clt, quotas, rtr))
+				names = append(names, def.Name)
+			}
+		}
+
+		if len(provs) == 0 {
+			return ErrNoProviders
+		}
+
+		app.log.Infow("providers chained", "providers", names)
+
+		mon := rate.NewMonitor(app.bus)
+
+		if auth, ok := adminAuth(cfg.Api); ok {
+			mh := rate.NewMonitorHandler(mon)
+
+			app.web.Handle(http.MethodGet, grp, pathAdminProviders, mh.Providers, auth)
+		}
+
+		sup := rate.NewSupport(app.bus, cfg.Rate.Support)
+		brk := rate.NewBreakers(app.bus, cfg.Rate.Breaker)
+
+		val := rate.NewValidator(app.bus, cfg.Rate.Validation)
+
+		for i := range provs {
+			provs[i] = sup.Wrap(brk.Wrap(val.Wrap(provs[i])))
+		}
+
+		if auth, ok := adminAuth(cfg.Api); ok {
+			bh := rate.NewBreakerHandler(brk)
+
+			app.web.Handle(http.MethodGet, grp, pathAdminBreakers, bh.Breakers, auth)
+		}
+
+		ranker, err := rate.NewRanker(cfg.Rate.Ranking.Algorithm)
+		if err != nil {
+			return err
+		}
+
+		ranking := rate.NewRanking(app.bus, ranker, cfg.Rate.Ranking)
+		rh := rate.NewRankingHandler(ranking)
+
+		app.web.Handle(http.MethodGet, grp, pathAdminRanking, rh.Ranking)
+
+		var svc rate.ExchangeRateService
+
+		switch cfg.Rate.Mode {
+		case rate.ModeConsensus:
+			cons := rate.NewConsensusService(app.bus, cfg.Rate.Consensus, provs...)
+			ch := rate.NewConsensusHandler(cons, reg)
+			app.web.Handle(http.MethodGet, grp, pathRateConsensus, ch.Consensus)
+
+			svc = cons
+
+		default:
+			chain := rate.NewService(app.bus, provs...)
+			chain.UseRanking(ranking)
+
+			svc = chain
+		}
+
+		if len(cfg.Rate.Pivots) > 0 {
+			svc = rate.NewTriangulator(svc, cfg.Rate.Pivots...)
+		}
+
+		if cfg.Rate.Cache.TTL > 0 {
+			svc = rate.NewCache(app.bus, svc, cfg.Rate.Cache)
 		}
-		svc := rate.NewService(app.bus, provs...)
-		h := rate.NewHandler(svc)
+
+		app.bus.Subscribe(event.New(rate.EventSource, rate.EventKindRequested, nil), rate.RespondExchangeRate(svc))
+
+		h := rate.NewHandler(svc, reg)
 
 		app.web.Handle(http.MethodGet, grp, pathRate, h.Rate)
 
+		poll, err := rate.NewPoller(app.bus, svc, cfg.Rate.Poll, rate.SubscribedPairs(app.bus))
+		if err != nil {
+			return err
+		}
+
+		app.Go(poll.Run)
+
+		bat := rate.NewBatcher(svc, cfg.Rate.Batch)
+		bth := rate.NewBatchHandler(bat, reg)
+
+		app.web.Handle(http.MethodGet, grp, pathRates, bth.Rates)
+		app.web.Handle(http.MethodPost, grp, pathRates, bth.Rates)
+
+		conv := rate.NewConverter(svc, reg)
+		cvh := rate.NewConvertHandler(conv, reg)
+
+		app.web.Handle(http.MethodGet, grp, pathConvert, cvh.Convert)
+
+		store := filestore.NewAppendLog[rate.ExchangeRate](cfg.Rate.RepoData)
+
+		hist, err := rate.NewHistory(app.bus, store, cfg.Rate.History)
+		if err != nil {
+			return err
+		}
+
+		app.Go(hist.Run)
+
+		hh := rate.NewHistoryHandler(hist, reg)
+
+		app.web.Handle(http.MethodGet, grp, pathRateHistory, hh.History)
+
+		feed := rate.NewFeed(app.bus)
+		sh := rate.NewStreamHandler(feed, hist, reg, cfg.Rate.Stream)
+
+		app.web.Handle(http.MethodGet, grp, pathRateStream, sh.Stream)
+
+		wsh := rate.NewSocketHandler(feed, reg, cfg.Rate.Stream, cfg.Api.Origin)
+
+		app.web.Handle(http.MethodGet, grp, pathRateSocket, wsh.Socket)
+
+		intervals := make([]time.Duration, len(cfg.Rate.CandleIntervals))
+		for i := range cfg.Rate.CandleIntervals {
+			if intervals[i], err = rate.ParseInterval(cfg.Rate.CandleIntervals[i]); err != nil {
+				return err
+			}
+		}
+
+		cndls, err := rate.NewCandles(app.bus, store, cfg.Rate.History, intervals...)
+		if err != nil {
+			return err
+		}
+
+		app.Go(cndls.Run)
+
+		ch := rate.NewCandleHandler(cndls, reg)
+
+		app.web.Handle(http.MethodGet, grp, pathRateCandles, ch.Candles)
+
 		return nil
 	}
 }
 
 // WithSubscription set-ups routes related to subscription functionality.
-func WithSubscription(cfg ConfigAggregate) Route {
+func WithSubscription(cfg ConfigAggregate, reg *currency.Registry, signer *subs.Signer) Route {
 	return func(app *App) error {
 		grp := path.Join(cfg.Api.Path, cfg.Api.Version)
 		conn := filestore.New[subs.Subscription](cfg.Subscription.RepoData)
 		repo := subs.NewRepo(conn)
-		svc := subs.NewService(app.bus, repo)
-		h := subs.NewHandler(svc)
+		svc := subs.NewService(app.bus, repo, reg, cfg.Subscription.Confirm)
+		h := subs.NewHandler(svc, signer, reg)
+
+		alerts := filestore.NewAppendLog[subs.AlertSnapshot](cfg.Subscription.RepoData)
+
+		alerter, err := subs.NewAlerter(app.bus, repo, alerts, cfg.Subscription.Alert)
+		if err != nil {
+			return err
+		}
+
+		app.Go(alerter.Run)
+
+		app.Go(subs.NewPurger(repo, cfg.Subscription.Confirm).Run)
+		app.Go(subs.NewDigester(app.bus, repo, cfg.Subscription.Digest).Run)
 
 		app.web.Handle(http.MethodPost, grp, pathSubscribe, h.Subscribe)
+		app.web.Handle(http.MethodGet, grp, pathConfirm, h.Confirm)
+		app.web.Handle(http.MethodDelete, grp, pathSubscribe, h.Unsubscribe)
+		app.web.Handle(http.MethodGet, grp, pathUnsubscribe, h.UnsubscribeLink)
+		app.web.Handle(http.MethodPost, grp, pathUnsubscribe, h.UnsubscribeLink)
+
+		// The management routes expose the subscribers, so they are served to the admin only.
+		auth, ok := adminAuth(cfg.Api)
+		if !ok {
+			return nil
+		}
+
+		app.web.Handle(http.MethodGet, grp, pathSubscriptions, h.ListSubscriptions, auth)
+		app.web.Handle(http.MethodPost, grp, pathSubscriptions, h.Subscribe, auth)
+		app.web.Handle(http.MethodGet, grp, pathSubscription, h.GetSubscription, auth)
+		app.web.Handle(http.MethodPatch, grp, pathSubscription, h.ChangeSubscription, auth)
+		app.web.Handle(http.MethodDelete, grp, pathSubscription, h.DeleteSubscription, auth)
 
 		return nil
 	}
 }
 
+// adminAuth returns the middleware authorizing the admin routes, false if no admin token is configured to serve them.
+func adminAuth(cfg Config) (web.Middleware, bool) {
+	if cfg.AdminToken == "-" {
+		return nil, false
+	}
+
+	return web.WithBearerAuth(cfg.AdminToken), true
+}
+
 // WithNotification set-ups routes related to notification functionality.
-func WithNotification(cfg ConfigAggregate) Route {
+func WithNotification(cfg ConfigAggregate, signer *subs.Signer) Route {
 	return func(app *App) error {
 		grp := path.Join(cfg.Api.Path, cfg.Api.Version)
 
@@ -68,6 +285,13 @@ func WithNotification(cfg ConfigAggregate) Route {
 		mail := email.NewService(t, cfg.Email)
 		cont := notif.NewExchangeRateContent(t)
 		svc := notif.NewService(app.bus, mail, cont)
+		base := strings.TrimSuffix(cfg.Api.URL, "/")
+
+		svc.UseLinker(subs.NewLinks(signer, subs.LinksConfig{
+			UnsubscribeURL: base + path.Join(grp, pathUnsubscribe),
+			ConfirmURL:     base + path.Join(grp, pathConfirm),
+			ConfirmWithin:  cfg.Subscription.Confirm.Window,
+		}))
 		h := notif.NewHandler(svc)
 
 		app.web.Handle(http.MethodPost, grp, pathSendEmails, h.SendEmails)
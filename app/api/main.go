@@ -9,8 +9,11 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// The timezones of the subscribers are resolved without the zoneinfo of the host.
+	_ "time/tzdata"
 
 	"github.com/GenesisEducationKyiv/main-project-delveper/app/api/ctrl"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/notif/email"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/subs"
@@ -28,10 +31,12 @@ type config struct {
 		Offset    string   `default:"oldest"`
 	}
 	Api struct {
-		Name    string `default:"rate"`
-		Path    string `default:"/api"`
-		Version string `default:"v1"`
-		Origin  string `default:"*"`
+		Name       string `default:"rate"`
+		Path       string `default:"/api"`
+		Version    string `default:"v1"`
+		Origin     string `default:"*"`
+		URL        string
+		AdminToken string `default:"-"`
 	}
 	Web struct {
 		Host            string        `default:"0.0.0.0:9999"`
@@ -43,38 +48,85 @@ type config struct {
 	Repo struct {
 		Data string `default:"./data"`
 	}
+	Currency struct {
+		Crypto []string `default:"BTC,ETH,USDT,USDC,BNB,SOL,XRP,ADA,DOGE,DOT,LTC,BCH,TRX,TON,XLM"`
+	}
 	Rate struct {
-		Provider struct {
-			ExchangeRateHost struct {
-				Name     string `default:"ExchangeRateHost"`
-				Endpoint string `default:"https://api.exchangerate.host/latest"`
-				Header   string `default:"-"`
-				Key      string `default:"-"`
-			}
-			Ninjas struct {
-				Name     string `default:"Ninjas"`
-				Endpoint string `default:"https://api.api-ninjas.com/v1/exchangerate"`
-				Header   string `default:"X-Api-Key"`
-				Key      string
-			}
-			AlphaVantage struct {
-				Name     string `default:"AlphaVantage"`
-				Endpoint string `default:"https://www.alphavantage.co/query?function=CURRENCY_EXCHANGE_RATE"`
-				Header   string `default:"apikey"`
-				Key      string
-			}
-			CoinApi struct {
-				Name     string `default:"CoinApi"`
-				Endpoint string `default:"https://rest.coinapi.io/v1/exchangerate"`
-				Header   string `default:"X-CoinAPI-Key"`
-				Key      string
-			}
-			CoinYep struct {
-				Name     string `default:"CoinYep"`
-				Endpoint string `default:"https://coinyep.com/api/v1/"`
-				Header   string `default:"-"`
-				Key      string `default:"-"`
-			}
+		Mode      string `default:"chain"`
+		Consensus struct {
+			Strategy  string  `default:"median"`
+			Quorum    int     `default:"2"`
+			Tolerance float64 `default:"0.05"`
+			Trim      float64 `default:"0.2"`
+		}
+		Cache struct {
+			TTL      time.Duration `default:"1m"`
+			Stale    time.Duration `default:"1m"`
+			MaxItems int           `default:"1024"`
+		}
+		Support struct {
+			Threshold int           `default:"3"`
+			Recheck   time.Duration `default:"1h"`
+		}
+		Breaker struct {
+			Threshold int           `default:"5"`
+			Cooldown  time.Duration `default:"30s"`
+		}
+		Retry struct {
+			Attempts      int           `default:"3"`
+			Backoff       time.Duration `default:"100ms"`
+			MaxBackoff    time.Duration `default:"2s"`
+			RetryOn       []string      `default:"server,network"`
+			HedgeQuantile float64       `default:"0.95"`
+		}
+		Validation struct {
+			MaxDeviation    float64       `default:"0.2"`
+			DeviationWindow time.Duration `default:"1h"`
+			MaxAge          time.Duration `default:"48h"`
+		}
+		Batch struct {
+			Workers  int `default:"8"`
+			MaxPairs int `default:"50"`
+		}
+		Stream struct {
+			Heartbeat time.Duration `default:"15s"`
+			Buffer    int           `default:"16"`
+		}
+		Poll struct {
+			Interval time.Duration `default:"0"`
+			Pairs    []string      `default:"BTC/UAH"`
+			Reserve  float64       `default:"0.2"`
+		}
+		Ranking struct {
+			Algorithm string `default:"latency"`
+			Window    int    `default:"50"`
+		}
+		History struct {
+			Retention time.Duration `default:"720h"`
+			Interval  time.Duration `default:"1h"`
+		}
+		Pivots          []string `default:"USD,USDT,BTC"`
+		CandleIntervals []string `default:"1m,1h,1d"`
+		Providers       string   `default:"-"`
+		Order           []string `default:"-"`
+	}
+	Subscription struct {
+		Token struct {
+			Secret string
+			TTL    time.Duration `default:"720h"`
+		}
+		Confirm struct {
+			Window time.Duration `default:"24h"`
+			Purge  time.Duration `default:"1h"`
+			Resend time.Duration `default:"1m"`
+		}
+		Alert struct {
+			Hysteresis float64       `default:"0.01"`
+			Cooldown   time.Duration `default:"1h"`
+			Interval   time.Duration `default:"1m"`
+		}
+		Digest struct {
+			Interval time.Duration `default:"1m"`
 		}
 	}
 	Email struct {
@@ -111,17 +163,35 @@ func run(log *logger.Logger, cfg *config) error {
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 
 	app, err := ctrl.New(ctrl.ConfigAggregate{
-		Api: ctrl.Config(cfg.Api),
+		Api:      ctrl.Config(cfg.Api),
+		Currency: currency.Config(cfg.Currency),
 		Rate: rate.Config{
-			Provider: struct{ ExchangeRateHost, Ninjas, AlphaVantage, CoinApi, CoinYep rate.ProviderConfig }{
-				ExchangeRateHost: rate.ProviderConfig(cfg.Rate.Provider.ExchangeRateHost),
-				Ninjas:           rate.ProviderConfig(cfg.Rate.Provider.Ninjas),
-				AlphaVantage:     rate.ProviderConfig(cfg.Rate.Provider.AlphaVantage),
-				CoinApi:          rate.ProviderConfig(cfg.Rate.Provider.CoinApi),
-				CoinYep:          rate.ProviderConfig(cfg.Rate.Provider.CoinYep)},
+			Mode:            cfg.Rate.Mode,
+			Consensus:       rate.ConsensusConfig(cfg.Rate.Consensus),
+			Cache:           rate.CacheConfig(cfg.Rate.Cache),
+			Support:         rate.SupportConfig(cfg.Rate.Support),
+			Breaker:         rate.BreakerConfig(cfg.Rate.Breaker),
+			Retry:           rate.RetryConfig(cfg.Rate.Retry),
+			Ranking:         rate.RankingConfig(cfg.Rate.Ranking),
+			Validation:      rate.ValidationConfig(cfg.Rate.Validation),
+			Batch:           rate.BatchConfig(cfg.Rate.Batch),
+			Stream:          rate.StreamConfig(cfg.Rate.Stream),
+			Poll:            rate.PollConfig(cfg.Rate.Poll),
+			History:         rate.HistoryConfig(cfg.Rate.History),
+			Pivots:          cfg.Rate.Pivots,
+			RepoData:        cfg.Repo.Data,
+			CandleIntervals: cfg.Rate.CandleIntervals,
+			Providers:       cfg.Rate.Providers,
+			Order:           cfg.Rate.Order,
+		},
+		Subscription: subs.Config{
+			RepoData: cfg.Repo.Data,
+			Alert:    subs.AlertConfig(cfg.Subscription.Alert),
+			Token:    subs.TokenConfig(cfg.Subscription.Token),
+			Confirm:  subs.ConfirmConfig(cfg.Subscription.Confirm),
+			Digest:   subs.DigestConfig(cfg.Subscription.Digest),
 		},
-		Subscription: subs.Config{RepoData: cfg.Repo.Data},
-		Email:        email.Config(cfg.Email),
+		Email: email.Config(cfg.Email),
 	}, shutdown, log)
 
 	if err != nil {
@@ -137,6 +207,9 @@ func run(log *logger.Logger, cfg *config) error {
 		ErrorLog:     log.ToStandard(),
 	}
 
+	// Long-lived streams are ended first, so the shutdown does not wait for them.
+	srv.RegisterOnShutdown(app.Close)
+
 	errSrv := make(chan error, 1)
 	go func() {
 		log.Infow("startup", "status", "api router started", "host", srv.Addr)
@@ -154,6 +227,9 @@ func run(log *logger.Logger, cfg *config) error {
 		ctx, cancel := context.WithTimeout(context.Background(), cfg.Web.ShutdownTimeout)
 		defer cancel()
 
+		// The background jobs like the rates poller are waited for after the requests are served.
+		defer app.Close()
+
 		if err := srv.Shutdown(ctx); err != nil {
 			srv.Close()
 			return fmt.Errorf("shuting down gracefully: %w", err)
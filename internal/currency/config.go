@@ -0,0 +1,7 @@
+package currency
+
+// Config holds the currency registry configuration.
+type Config struct {
+	// Crypto lists supported crypto assets in the form of "CODE[:DECIMALS[:NAME]]".
+	Crypto []string
+}
@@ -0,0 +1,156 @@
+/*
+Package currency provides the registry of supported currencies.
+It knows ISO 4217 fiat currencies along with their minor units and a configurable list of crypto assets.
+The registry is built once at startup and passed to the services validating currency codes.
+*/
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	KindFiat   Kind = "fiat"
+	KindCrypto Kind = "crypto"
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrInvalidCurrency = errors.New("invalid currency definition")
+)
+
+// Kind represents a kind of currency.
+type Kind string
+
+// Currency represents a currency known to the service.
+type Currency struct {
+	Code string
+	Name string
+	// Decimals is the number of digits after the decimal separator, the minor unit for fiat currencies.
+	Decimals int
+	Kind     Kind
+}
+
+// Registry is a concurrency-safe set of currencies keyed by code.
+type Registry struct {
+	mu    sync.RWMutex
+	items map[string]Currency
+}
+
+// NewRegistry creates a new Registry instance with the given currencies.
+func NewRegistry(cc ...Currency) *Registry {
+	r := Registry{items: make(map[string]Currency, len(cc))}
+	r.Register(cc...)
+
+	return &r
+}
+
+// Register adds currencies to the registry replacing the ones with the same code.
+func (r *Registry) Register(cc ...Currency) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range cc {
+		c.Code = strings.ToUpper(c.Code)
+		r.items[c.Code] = c
+	}
+}
+
+// Lookup returns the currency by its code.
+func (r *Registry) Lookup(code string) (Currency, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.items[strings.ToUpper(code)]
+
+	return c, ok
+}
+
+// Validate returns ErrUnknownCurrency if the code is not registered.
+func (r *Registry) Validate(code string) error {
+	if _, ok := r.Lookup(code); !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+
+	return nil
+}
+
+// List returns registered currencies of the given kinds ordered by kind and code.
+// All currencies are returned when no kind is given.
+func (r *Registry) List(kinds ...Kind) []Currency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]Currency, 0, len(r.items))
+
+	for _, c := range r.items {
+		if len(kinds) == 0 || hasKind(kinds, c.Kind) {
+			list = append(list, c)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind == KindFiat
+		}
+
+		return list[i].Code < list[j].Code
+	})
+
+	return list
+}
+
+func hasKind(kinds []Kind, kind Kind) bool {
+	for i := range kinds {
+		if kinds[i] == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseCrypto parses crypto asset definitions in the form of "CODE[:DECIMALS[:NAME]]".
+// Decimals and name of well-known assets are taken from Crypto when omitted.
+func ParseCrypto(defs ...string) ([]Currency, error) {
+	const defaultDecimals = 8
+
+	known := NewRegistry(Crypto()...)
+	list := make([]Currency, 0, len(defs))
+
+	for _, def := range defs {
+		parts := strings.SplitN(strings.TrimSpace(def), ":", 3)
+
+		code := strings.ToUpper(parts[0])
+		if code == "" {
+			return nil, fmt.Errorf("%w: %q: missing code", ErrInvalidCurrency, def)
+		}
+
+		c, ok := known.Lookup(code)
+		if !ok {
+			c = Currency{Code: code, Name: code, Decimals: defaultDecimals, Kind: KindCrypto}
+		}
+
+		if len(parts) > 1 {
+			dec, err := strconv.Atoi(parts[1])
+			if err != nil || dec < 0 {
+				return nil, fmt.Errorf("%w: %q: invalid decimals", ErrInvalidCurrency, def)
+			}
+
+			c.Decimals = dec
+		}
+
+		if len(parts) > 2 && parts[2] != "" {
+			c.Name = parts[2]
+		}
+
+		list = append(list, c)
+	}
+
+	return list, nil
+}
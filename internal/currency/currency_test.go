@@ -0,0 +1,92 @@
+package currency_test
+
+import (
+	"testing"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryValidate(t *testing.T) {
+	reg := currency.NewRegistry(append(currency.ISO4217(), currency.Currency{Code: "btc", Decimals: 8, Kind: currency.KindCrypto})...)
+
+	tests := map[string]struct {
+		code    string
+		wantErr error
+	}{
+		"fiat":      {code: "UAH"},
+		"lowercase": {code: "usd"},
+		"crypto":    {code: "BTC"},
+		"unknown":   {code: "FOO", wantErr: currency.ErrUnknownCurrency},
+		"empty":     {code: "", wantErr: currency.ErrUnknownCurrency},
+		"missing":   {code: "ETH", wantErr: currency.ErrUnknownCurrency},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, reg.Validate(tc.code), tc.wantErr)
+		})
+	}
+}
+
+func TestRegistryList(t *testing.T) {
+	reg := currency.NewRegistry(
+		currency.Currency{Code: "USDT", Kind: currency.KindCrypto},
+		currency.Currency{Code: "USD", Kind: currency.KindFiat},
+		currency.Currency{Code: "BTC", Kind: currency.KindCrypto},
+		currency.Currency{Code: "EUR", Kind: currency.KindFiat},
+	)
+
+	codes := func(cc []currency.Currency) []string {
+		list := make([]string, len(cc))
+		for i := range cc {
+			list[i] = cc[i].Code
+		}
+
+		return list
+	}
+
+	require.Equal(t, []string{"EUR", "USD", "BTC", "USDT"}, codes(reg.List()))
+	require.Equal(t, []string{"BTC", "USDT"}, codes(reg.List(currency.KindCrypto)))
+}
+
+func TestParseCrypto(t *testing.T) {
+	tests := map[string]struct {
+		defs    []string
+		want    []currency.Currency
+		wantErr error
+	}{
+		"known": {
+			defs: []string{"btc"},
+			want: []currency.Currency{{Code: "BTC", Name: "Bitcoin", Decimals: 8, Kind: currency.KindCrypto}},
+		},
+
+		"override": {
+			defs: []string{"ETH:8"},
+			want: []currency.Currency{{Code: "ETH", Name: "Ether", Decimals: 8, Kind: currency.KindCrypto}},
+		},
+
+		"custom": {
+			defs: []string{"PEPE:18:Pepe"},
+			want: []currency.Currency{{Code: "PEPE", Name: "Pepe", Decimals: 18, Kind: currency.KindCrypto}},
+		},
+
+		"invalid_decimals": {
+			defs:    []string{"BTC:x"},
+			wantErr: currency.ErrInvalidCurrency,
+		},
+
+		"missing_code": {
+			defs:    []string{":8"},
+			wantErr: currency.ErrInvalidCurrency,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := currency.ParseCrypto(tc.defs...)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+)
+
+var ErrInvalidKind = errors.New("invalid currency kind")
+
+// Lister interface to list supported currencies.
+type Lister interface {
+	List(kinds ...Kind) []Currency
+}
+
+// Response is a response for supported currencies.
+type Response struct {
+	Currencies []CurrencyResponse `json:"currencies"`
+}
+
+// CurrencyResponse is a single supported currency.
+type CurrencyResponse struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Decimals int    `json:"decimals"`
+	Kind     string `json:"kind"`
+}
+
+func NewResponse(cc []Currency) *Response {
+	resp := make([]CurrencyResponse, len(cc))
+	for i, c := range cc {
+		resp[i] = CurrencyResponse{Code: c.Code, Name: c.Name, Decimals: c.Decimals, Kind: string(c.Kind)}
+	}
+
+	return &Response{Currencies: resp}
+}
+
+// Handler structure for handling currency requests.
+type Handler struct {
+	reg Lister
+}
+
+// NewHandler creates a new Handler instance.
+func NewHandler(reg Lister) Handler {
+	return Handler{reg: reg}
+}
+
+// Currencies handles the HTTP request for the list of supported currencies.
+// The list can be narrowed down with the kind query parameter.
+func (h *Handler) Currencies(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	var kinds []Kind
+
+	switch kind := Kind(web.FromQuery(req, "kind")); kind {
+	case "":
+	case KindFiat, KindCrypto:
+		kinds = append(kinds, kind)
+	default:
+		return web.NewRequestError(ErrInvalidKind, http.StatusBadRequest)
+	}
+
+	return web.Respond(ctx, rw, NewResponse(h.reg.List(kinds...)), http.StatusOK)
+}
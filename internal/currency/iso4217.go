@@ -0,0 +1,187 @@
+package currency
+
+// ISO4217 returns active ISO 4217 fiat currencies with their minor units.
+// Funds and precious metals codes are not included.
+func ISO4217() []Currency {
+	return []Currency{
+		{Code: "AED", Name: "UAE Dirham", Decimals: 2, Kind: KindFiat},
+		{Code: "AFN", Name: "Afghani", Decimals: 2, Kind: KindFiat},
+		{Code: "ALL", Name: "Lek", Decimals: 2, Kind: KindFiat},
+		{Code: "AMD", Name: "Armenian Dram", Decimals: 2, Kind: KindFiat},
+		{Code: "ANG", Name: "Netherlands Antillean Guilder", Decimals: 2, Kind: KindFiat},
+		{Code: "AOA", Name: "Kwanza", Decimals: 2, Kind: KindFiat},
+		{Code: "ARS", Name: "Argentine Peso", Decimals: 2, Kind: KindFiat},
+		{Code: "AUD", Name: "Australian Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "AWG", Name: "Aruban Florin", Decimals: 2, Kind: KindFiat},
+		{Code: "AZN", Name: "Azerbaijan Manat", Decimals: 2, Kind: KindFiat},
+		{Code: "BAM", Name: "Convertible Mark", Decimals: 2, Kind: KindFiat},
+		{Code: "BBD", Name: "Barbados Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "BDT", Name: "Taka", Decimals: 2, Kind: KindFiat},
+		{Code: "BGN", Name: "Bulgarian Lev", Decimals: 2, Kind: KindFiat},
+		{Code: "BHD", Name: "Bahraini Dinar", Decimals: 3, Kind: KindFiat},
+		{Code: "BIF", Name: "Burundi Franc", Decimals: 0, Kind: KindFiat},
+		{Code: "BMD", Name: "Bermudian Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "BND", Name: "Brunei Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "BOB", Name: "Boliviano", Decimals: 2, Kind: KindFiat},
+		{Code: "BRL", Name: "Brazilian Real", Decimals: 2, Kind: KindFiat},
+		{Code: "BSD", Name: "Bahamian Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "BTN", Name: "Ngultrum", Decimals: 2, Kind: KindFiat},
+		{Code: "BWP", Name: "Pula", Decimals: 2, Kind: KindFiat},
+		{Code: "BYN", Name: "Belarusian Ruble", Decimals: 2, Kind: KindFiat},
+		{Code: "BZD", Name: "Belize Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "CAD", Name: "Canadian Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "CDF", Name: "Congolese Franc", Decimals: 2, Kind: KindFiat},
+		{Code: "CHF", Name: "Swiss Franc", Decimals: 2, Kind: KindFiat},
+		{Code: "CLF", Name: "Unidad de Fomento", Decimals: 4, Kind: KindFiat},
+		{Code: "CLP", Name: "Chilean Peso", Decimals: 0, Kind: KindFiat},
+		{Code: "CNY", Name: "Yuan Renminbi", Decimals: 2, Kind: KindFiat},
+		{Code: "COP", Name: "Colombian Peso", Decimals: 2, Kind: KindFiat},
+		{Code: "CRC", Name: "Costa Rican Colon", Decimals: 2, Kind: KindFiat},
+		{Code: "CUP", Name: "Cuban Peso", Decimals: 2, Kind: KindFiat},
+		{Code: "CVE", Name: "Cabo Verde Escudo", Decimals: 2, Kind: KindFiat},
+		{Code: "CZK", Name: "Czech Koruna", Decimals: 2, Kind: KindFiat},
+		{Code: "DJF", Name: "Djibouti Franc", Decimals: 0, Kind: KindFiat},
+		{Code: "DKK", Name: "Danish Krone", Decimals: 2, Kind: KindFiat},
+		{Code: "DOP", Name: "Dominican Peso", Decimals: 2, Kind: KindFiat},
+		{Code: "DZD", Name: "Algerian Dinar", Decimals: 2, Kind: KindFiat},
+		{Code: "EGP", Name: "Egyptian Pound", Decimals: 2, Kind: KindFiat},
+		{Code: "ERN", Name: "Nakfa", Decimals: 2, Kind: KindFiat},
+		{Code: "ETB", Name: "Ethiopian Birr", Decimals: 2, Kind: KindFiat},
+		{Code: "EUR", Name: "Euro", Decimals: 2, Kind: KindFiat},
+		{Code: "FJD", Name: "Fiji Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "FKP", Name: "Falkland Islands Pound", Decimals: 2, Kind: KindFiat},
+		{Code: "GBP", Name: "Pound Sterling", Decimals: 2, Kind: KindFiat},
+		{Code: "GEL", Name: "Lari", Decimals: 2, Kind: KindFiat},
+		{Code: "GHS", Name: "Ghana Cedi", Decimals: 2, Kind: KindFiat},
+		{Code: "GIP", Name: "Gibraltar Pound", Decimals: 2, Kind: KindFiat},
+		{Code: "GMD", Name: "Dalasi", Decimals: 2, Kind: KindFiat},
+		{Code: "GNF", Name: "Guinean Franc", Decimals: 0, Kind: KindFiat},
+		{Code: "GTQ", Name: "Quetzal", Decimals: 2, Kind: KindFiat},
+		{Code: "GYD", Name: "Guyana Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "HKD", Name: "Hong Kong Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "HNL", Name: "Lempira", Decimals: 2, Kind: KindFiat},
+		{Code: "HTG", Name: "Gourde", Decimals: 2, Kind: KindFiat},
+		{Code: "HUF", Name: "Forint", Decimals: 2, Kind: KindFiat},
+		{Code: "IDR", Name: "Rupiah", Decimals: 2, Kind: KindFiat},
+		{Code: "ILS", Name: "New Israeli Sheqel", Decimals: 2, Kind: KindFiat},
+		{Code: "INR", Name: "Indian Rupee", Decimals: 2, Kind: KindFiat},
+		{Code: "IQD", Name: "Iraqi Dinar", Decimals: 3, Kind: KindFiat},
+		{Code: "IRR", Name: "Iranian Rial", Decimals: 2, Kind: KindFiat},
+		{Code: "ISK", Name: "Iceland Krona", Decimals: 0, Kind: KindFiat},
+		{Code: "JMD", Name: "Jamaican Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "JOD", Name: "Jordanian Dinar", Decimals: 3, Kind: KindFiat},
+		{Code: "JPY", Name: "Yen", Decimals: 0, Kind: KindFiat},
+		{Code: "KES", Name: "Kenyan Shilling", Decimals: 2, Kind: KindFiat},
+		{Code: "KGS", Name: "Som", Decimals: 2, Kind: KindFiat},
+		{Code: "KHR", Name: "Riel", Decimals: 2, Kind: KindFiat},
+		{Code: "KMF", Name: "Comorian Franc", Decimals: 0, Kind: KindFiat},
+		{Code: "KPW", Name: "North Korean Won", Decimals: 2, Kind: KindFiat},
+		{Code: "KRW", Name: "Won", Decimals: 0, Kind: KindFiat},
+		{Code: "KWD", Name: "Kuwaiti Dinar", Decimals: 3, Kind: KindFiat},
+		{Code: "KYD", Name: "Cayman Islands Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "KZT", Name: "Tenge", Decimals: 2, Kind: KindFiat},
+		{Code: "LAK", Name: "Lao Kip", Decimals: 2, Kind: KindFiat},
+		{Code: "LBP", Name: "Lebanese Pound", Decimals: 2, Kind: KindFiat},
+		{Code: "LKR", Name: "Sri Lanka Rupee", Decimals: 2, Kind: KindFiat},
+		{Code: "LRD", Name: "Liberian Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "LSL", Name: "Loti", Decimals: 2, Kind: KindFiat},
+		{Code: "LYD", Name: "Libyan Dinar", Decimals: 3, Kind: KindFiat},
+		{Code: "MAD", Name: "Moroccan Dirham", Decimals: 2, Kind: KindFiat},
+		{Code: "MDL", Name: "Moldovan Leu", Decimals: 2, Kind: KindFiat},
+		{Code: "MGA", Name: "Malagasy Ariary", Decimals: 2, Kind: KindFiat},
+		{Code: "MKD", Name: "Denar", Decimals: 2, Kind: KindFiat},
+		{Code: "MMK", Name: "Kyat", Decimals: 2, Kind: KindFiat},
+		{Code: "MNT", Name: "Tugrik", Decimals: 2, Kind: KindFiat},
+		{Code: "MOP", Name: "Pataca", Decimals: 2, Kind: KindFiat},
+		{Code: "MRU", Name: "Ouguiya", Decimals: 2, Kind: KindFiat},
+		{Code: "MUR", Name: "Mauritius Rupee", Decimals: 2, Kind: KindFiat},
+		{Code: "MVR", Name: "Rufiyaa", Decimals: 2, Kind: KindFiat},
+		{Code: "MWK", Name: "Malawi Kwacha", Decimals: 2, Kind: KindFiat},
+		{Code: "MXN", Name: "Mexican Peso", Decimals: 2, Kind: KindFiat},
+		{Code: "MYR", Name: "Malaysian Ringgit", Decimals: 2, Kind: KindFiat},
+		{Code: "MZN", Name: "Mozambique Metical", Decimals: 2, Kind: KindFiat},
+		{Code: "NAD", Name: "Namibia Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "NGN", Name: "Naira", Decimals: 2, Kind: KindFiat},
+		{Code: "NIO", Name: "Cordoba Oro", Decimals: 2, Kind: KindFiat},
+		{Code: "NOK", Name: "Norwegian Krone", Decimals: 2, Kind: KindFiat},
+		{Code: "NPR", Name: "Nepalese Rupee", Decimals: 2, Kind: KindFiat},
+		{Code: "NZD", Name: "New Zealand Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "OMR", Name: "Rial Omani", Decimals: 3, Kind: KindFiat},
+		{Code: "PAB", Name: "Balboa", Decimals: 2, Kind: KindFiat},
+		{Code: "PEN", Name: "Sol", Decimals: 2, Kind: KindFiat},
+		{Code: "PGK", Name: "Kina", Decimals: 2, Kind: KindFiat},
+		{Code: "PHP", Name: "Philippine Peso", Decimals: 2, Kind: KindFiat},
+		{Code: "PKR", Name: "Pakistan Rupee", Decimals: 2, Kind: KindFiat},
+		{Code: "PLN", Name: "Zloty", Decimals: 2, Kind: KindFiat},
+		{Code: "PYG", Name: "Guarani", Decimals: 0, Kind: KindFiat},
+		{Code: "QAR", Name: "Qatari Rial", Decimals: 2, Kind: KindFiat},
+		{Code: "RON", Name: "Romanian Leu", Decimals: 2, Kind: KindFiat},
+		{Code: "RSD", Name: "Serbian Dinar", Decimals: 2, Kind: KindFiat},
+		{Code: "RUB", Name: "Russian Ruble", Decimals: 2, Kind: KindFiat},
+		{Code: "RWF", Name: "Rwanda Franc", Decimals: 0, Kind: KindFiat},
+		{Code: "SAR", Name: "Saudi Riyal", Decimals: 2, Kind: KindFiat},
+		{Code: "SBD", Name: "Solomon Islands Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "SCR", Name: "Seychelles Rupee", Decimals: 2, Kind: KindFiat},
+		{Code: "SDG", Name: "Sudanese Pound", Decimals: 2, Kind: KindFiat},
+		{Code: "SEK", Name: "Swedish Krona", Decimals: 2, Kind: KindFiat},
+		{Code: "SGD", Name: "Singapore Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "SHP", Name: "Saint Helena Pound", Decimals: 2, Kind: KindFiat},
+		{Code: "SLE", Name: "Leone", Decimals: 2, Kind: KindFiat},
+		{Code: "SOS", Name: "Somali Shilling", Decimals: 2, Kind: KindFiat},
+		{Code: "SRD", Name: "Surinam Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "SSP", Name: "South Sudanese Pound", Decimals: 2, Kind: KindFiat},
+		{Code: "STN", Name: "Dobra", Decimals: 2, Kind: KindFiat},
+		{Code: "SVC", Name: "El Salvador Colon", Decimals: 2, Kind: KindFiat},
+		{Code: "SYP", Name: "Syrian Pound", Decimals: 2, Kind: KindFiat},
+		{Code: "SZL", Name: "Lilangeni", Decimals: 2, Kind: KindFiat},
+		{Code: "THB", Name: "Baht", Decimals: 2, Kind: KindFiat},
+		{Code: "TJS", Name: "Somoni", Decimals: 2, Kind: KindFiat},
+		{Code: "TMT", Name: "Turkmenistan New Manat", Decimals: 2, Kind: KindFiat},
+		{Code: "TND", Name: "Tunisian Dinar", Decimals: 3, Kind: KindFiat},
+		{Code: "TOP", Name: "Pa'anga", Decimals: 2, Kind: KindFiat},
+		{Code: "TRY", Name: "Turkish Lira", Decimals: 2, Kind: KindFiat},
+		{Code: "TTD", Name: "Trinidad and Tobago Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "TWD", Name: "New Taiwan Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "TZS", Name: "Tanzanian Shilling", Decimals: 2, Kind: KindFiat},
+		{Code: "UAH", Name: "Hryvnia", Decimals: 2, Kind: KindFiat},
+		{Code: "UGX", Name: "Uganda Shilling", Decimals: 0, Kind: KindFiat},
+		{Code: "USD", Name: "US Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "UYU", Name: "Peso Uruguayo", Decimals: 2, Kind: KindFiat},
+		{Code: "UYW", Name: "Unidad Previsional", Decimals: 4, Kind: KindFiat},
+		{Code: "UZS", Name: "Uzbekistan Sum", Decimals: 2, Kind: KindFiat},
+		{Code: "VED", Name: "Bolivar Soberano", Decimals: 2, Kind: KindFiat},
+		{Code: "VES", Name: "Bolivar Soberano", Decimals: 2, Kind: KindFiat},
+		{Code: "VND", Name: "Dong", Decimals: 0, Kind: KindFiat},
+		{Code: "VUV", Name: "Vatu", Decimals: 0, Kind: KindFiat},
+		{Code: "WST", Name: "Tala", Decimals: 2, Kind: KindFiat},
+		{Code: "XAF", Name: "CFA Franc BEAC", Decimals: 0, Kind: KindFiat},
+		{Code: "XCD", Name: "East Caribbean Dollar", Decimals: 2, Kind: KindFiat},
+		{Code: "XOF", Name: "CFA Franc BCEAO", Decimals: 0, Kind: KindFiat},
+		{Code: "XPF", Name: "CFP Franc", Decimals: 0, Kind: KindFiat},
+		{Code: "YER", Name: "Yemeni Rial", Decimals: 2, Kind: KindFiat},
+		{Code: "ZAR", Name: "Rand", Decimals: 2, Kind: KindFiat},
+		{Code: "ZMW", Name: "Zambian Kwacha", Decimals: 2, Kind: KindFiat},
+		{Code: "ZWL", Name: "Zimbabwe Dollar", Decimals: 2, Kind: KindFiat},
+	}
+}
+
+// Crypto returns well-known crypto assets with the decimals of their smallest unit.
+func Crypto() []Currency {
+	return []Currency{
+		{Code: "BTC", Name: "Bitcoin", Decimals: 8, Kind: KindCrypto},
+		{Code: "ETH", Name: "Ether", Decimals: 18, Kind: KindCrypto},
+		{Code: "USDT", Name: "Tether", Decimals: 6, Kind: KindCrypto},
+		{Code: "USDC", Name: "USD Coin", Decimals: 6, Kind: KindCrypto},
+		{Code: "BNB", Name: "BNB", Decimals: 18, Kind: KindCrypto},
+		{Code: "SOL", Name: "Solana", Decimals: 9, Kind: KindCrypto},
+		{Code: "XRP", Name: "XRP", Decimals: 6, Kind: KindCrypto},
+		{Code: "ADA", Name: "Cardano", Decimals: 6, Kind: KindCrypto},
+		{Code: "DOGE", Name: "Dogecoin", Decimals: 8, Kind: KindCrypto},
+		{Code: "DOT", Name: "Polkadot", Decimals: 10, Kind: KindCrypto},
+		{Code: "LTC", Name: "Litecoin", Decimals: 8, Kind: KindCrypto},
+		{Code: "BCH", Name: "Bitcoin Cash", Decimals: 8, Kind: KindCrypto},
+		{Code: "TRX", Name: "TRON", Decimals: 6, Kind: KindCrypto},
+		{Code: "TON", Name: "Toncoin", Decimals: 9, Kind: KindCrypto},
+		{Code: "XLM", Name: "Stellar", Decimals: 7, Kind: KindCrypto},
+	}
+}
@@ -33,19 +33,30 @@ func NewService(tmpl *template.Template, cfg Config) *Service {
 
 // Send responsible for sending an email message.
 func (svc *Service) Send(ctx context.Context, msg *notif.Message) error {
-	var buf bytes.Buffer
-
-	msg.From = svc.cfg.UserName
-
-	if err := svc.tmpl.ExecuteTemplate(&buf, "email", msg); err != nil {
-		return fmt.Errorf("executing email template: %v", err)
+	data, err := svc.Compose(msg)
+	if err != nil {
+		return err
 	}
 
 	addr := net.JoinHostPort(svc.cfg.Host, svc.cfg.Port)
 
-	if err := smtp.SendMail(addr, svc.auth, msg.From, msg.To, buf.Bytes()); err != nil {
+	if err := smtp.SendMail(addr, svc.auth, msg.From, msg.To, data); err != nil {
 		return fmt.Errorf("sending email: %w", err)
 	}
 
 	return nil
 }
+
+// Compose renders the message sent from the configured user into the raw email data.
+func (svc *Service) Compose(msg *notif.Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	msg.From = svc.cfg.UserName
+
+	if err := svc.tmpl.ExecuteTemplate(&buf, "email", msg); err != nil {
+		return nil, fmt.Errorf("executing email template: %v", err)
+	}
+
+	// The message must start with the headers, a leading empty line would turn them into the body.
+	return bytes.TrimLeft(buf.Bytes(), "\r\n"), nil
+}
@@ -0,0 +1,64 @@
+package email_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/notif"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/notif/email"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/notif/tmpl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceCompose(t *testing.T) {
+	tests := map[string]struct {
+		msg      *notif.Message
+		wantHdrs []string
+	}{
+		"plain": {
+			msg:      notif.NewMessage([]string{"jon@example.com", "ann@example.com"}, "BTC/UAH Exchange Rate", "body"),
+			wantHdrs: []string{"From: rate@example.com", "To: jon@example.com, ann@example.com", "Subject: BTC/UAH Exchange Rate"},
+		},
+		"unsubscribe_headers": {
+			msg: &notif.Message{
+				To:      []string{"jon@example.com"},
+				Subject: "BTC/UAH Exchange Rate",
+				Body:    "body",
+				Headers: map[string]string{
+					"List-Unsubscribe":      "<http://localhost/unsubscribe>",
+					"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+				},
+			},
+			wantHdrs: []string{
+				"From: rate@example.com",
+				"List-Unsubscribe: <http://localhost/unsubscribe>",
+				"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+			},
+		},
+	}
+
+	tpl, err := tmpl.Load()
+	require.NoError(t, err)
+
+	svc := email.NewService(tpl, email.Config{Host: "localhost", Port: "25", UserName: "rate@example.com"})
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			data, err := svc.Compose(tc.msg)
+			require.NoError(t, err)
+
+			got := strings.ReplaceAll(string(data), "\r\n", "\n")
+			require.True(t, strings.HasPrefix(got, "From: "), "leading new lines are not trimmed: %q", got)
+
+			hdrs, body, ok := strings.Cut(got, "\n\n")
+			require.True(t, ok)
+			require.Contains(t, body, tc.msg.Body)
+
+			for _, want := range tc.wantHdrs {
+				require.Contains(t, strings.Split(hdrs, "\n"), want)
+			}
+		})
+	}
+}
@@ -11,8 +11,17 @@ import (
 const (
 	EventSource        = "notif"
 	EventKindRequested = "requested"
+	// EventKindAlerted is the event of the subscription alert triggered.
+	EventKindAlerted = "alerted"
+	// EventKindConfirmationRequested is the request for the confirmation message of a new subscription.
+	EventKindConfirmationRequested = "confirmation_requested"
+	// EventKindDigestDue is the event of the digest due to the subscribers preferring it over the instant delivery.
+	EventKindDigestDue = "digest_due"
 )
 
+// ErrMissingLinker is an error indicating that the links for the messages cannot be created.
+var ErrMissingLinker = errors.New("missing linker")
+
 var ErrInvalidEvent = errors.New("invalid event")
 
 // CurrencyPairEvent is an event for fetching a currency pair.
@@ -32,9 +41,134 @@ type SubscribersEvent interface {
 	Subscribers() []string
 }
 
+// BroadcastEvent is an event requesting the exchange rate data for the broadcast to the subscribers.
+type BroadcastEvent interface {
+	CurrencyPairEvent
+	Broadcast() bool
+}
+
+// AlertEvent is an event of the subscription alert triggered.
+type AlertEvent interface {
+	CurrencyPairEvent
+	ExchangeRateEvent
+	SubscribersEvent
+	Condition() string
+}
+
+// NotifyAlert handles an event of the triggered alert sending the message to its subscribers.
+func (svc *Service) NotifyAlert(ctx context.Context, e event.Event) error {
+	req, ok := e.Payload.(AlertEvent)
+	if !ok {
+		return fmt.Errorf("%w: unexpected payload: %T", ErrInvalidEvent, e.Payload)
+	}
+
+	topic := Topic{Base: req.BaseCurrency(), Quote: req.QuoteCurrency()}
+
+	err := svc.send(ctx, topic, req.Subscribers(), func(to []string, link string) (*Message, error) {
+		data := NewAlertData(topic, req.ExchangeRate(), req.Condition(), to)
+		data.UnsubscribeLink = link
+
+		return svc.mc.CreateAlertMessage(data)
+	})
+	if err != nil {
+		return fmt.Errorf("notifying alert: %w", err)
+	}
+
+	return nil
+}
+
+// DigestEvent is an event of the digest due to the subscribers of a currency pair.
+type DigestEvent interface {
+	CurrencyPairEvent
+	SubscribersEvent
+}
+
+// SendDigest handles an event of the due digest sending the current exchange rate to its subscribers.
+func (svc *Service) SendDigest(ctx context.Context, e event.Event) error {
+	req, ok := e.Payload.(DigestEvent)
+	if !ok {
+		return fmt.Errorf("%w: unexpected payload: %T", ErrInvalidEvent, e.Payload)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	topic := Topic{Base: req.BaseCurrency(), Quote: req.QuoteCurrency()}
+
+	data, err := svc.RequestExchangeRateData(ctx, topic)
+	if err != nil {
+		return fmt.Errorf("sending digest: %w", err)
+	}
+
+	err = svc.send(ctx, topic, req.Subscribers(), func(to []string, link string) (*Message, error) {
+		data := *data
+		data.Subscribers, data.UnsubscribeLink = to, link
+
+		return svc.mc.CreateMessage(&data)
+	})
+	if err != nil {
+		return fmt.Errorf("sending digest: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmationEvent is an event requesting the confirmation of a new subscription.
+type ConfirmationEvent interface {
+	CurrencyPairEvent
+	SubscribersEvent
+}
+
+// SendConfirmation handles an event of the new subscription sending the confirmation link to its subscriber.
+func (svc *Service) SendConfirmation(ctx context.Context, e event.Event) error {
+	req, ok := e.Payload.(ConfirmationEvent)
+	if !ok {
+		return fmt.Errorf("%w: unexpected payload: %T", ErrInvalidEvent, e.Payload)
+	}
+
+	if svc.link == nil {
+		return fmt.Errorf("sending confirmation: %w", ErrMissingLinker)
+	}
+
+	topic := Topic{Base: req.BaseCurrency(), Quote: req.QuoteCurrency()}
+
+	var errs []error
+
+	for _, subs := range req.Subscribers() {
+		if err := svc.sendConfirmation(ctx, topic, subs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("sending confirmation: %w", err)
+	}
+
+	return nil
+}
+
+func (svc *Service) sendConfirmation(ctx context.Context, topic Topic, subs string) error {
+	link, err := svc.link.ConfirmLink(subs, topic.Base, topic.Quote)
+	if err != nil {
+		return err
+	}
+
+	msg, err := svc.mc.CreateConfirmationMessage(NewConfirmationData(topic, subs, link))
+	if err != nil {
+		return err
+	}
+
+	return svc.sndr.Send(ctx, msg)
+}
+
 // RequestExchangeRateData triggers fetching exchange rate data.
 func (svc *Service) RequestExchangeRateData(ctx context.Context, pair Topic) (*ExchangeRateData, error) {
-	e := event.New(EventSource, EventKindRequested, pair)
+	return svc.requestData(ctx, pair, pair)
+}
+
+// requestData triggers fetching exchange rate data of the pair with the payload requesting it.
+func (svc *Service) requestData(ctx context.Context, pair Topic, req CurrencyPairEvent) (*ExchangeRateData, error) {
+	e := event.New(EventSource, EventKindRequested, req)
 	if err := svc.bus.Publish(ctx, e); err != nil {
 		return nil, fmt.Errorf("publishing sending email event: %w", err)
 	}
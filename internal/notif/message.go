@@ -3,6 +3,7 @@ package notif
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"text/template"
 )
 
@@ -15,19 +16,32 @@ func NewExchangeRateContent(tmpl *template.Template) *ExchangeRateContent {
 
 // CreateMessage creates message content using prepared templates.
 func (c *ExchangeRateContent) CreateMessage(data *ExchangeRateData) (*Message, error) {
+	return c.create(data.Subscribers, "subject", "body", data)
+}
+
+// CreateAlertMessage creates triggered alert message content using prepared templates.
+func (c *ExchangeRateContent) CreateAlertMessage(data *AlertData) (*Message, error) {
+	return c.create(data.Subscribers, "alert_subject", "alert_body", data)
+}
+
+// CreateConfirmationMessage creates subscription confirmation message content using prepared templates.
+func (c *ExchangeRateContent) CreateConfirmationMessage(data *ConfirmationData) (*Message, error) {
+	return c.create(data.Subscribers, "confirm_subject", "confirm_body", data)
+}
+
+func (c *ExchangeRateContent) create(to []string, subject, body string, data any) (*Message, error) {
 	var buf bytes.Buffer
-	if err := c.ExecuteTemplate(&buf, "subject", data); err != nil {
+	if err := c.ExecuteTemplate(&buf, subject, data); err != nil {
 		return nil, fmt.Errorf("executing subject template: %w", err)
 	}
 
-	subj := buf.String()
+	// The subject is a header line, so it must not end the header block with the template new lines.
+	subj := strings.TrimSpace(buf.String())
 	buf.Reset()
 
-	if err := c.ExecuteTemplate(&buf, "body", data); err != nil {
+	if err := c.ExecuteTemplate(&buf, body, data); err != nil {
 		return nil, fmt.Errorf("executing body template: %w", err)
 	}
 
-	body := buf.String()
-
-	return NewMessage(data.Subscribers, subj, body), nil
+	return NewMessage(to, subj, buf.String()), nil
 }
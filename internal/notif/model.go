@@ -8,6 +8,8 @@ type Message struct {
 	To      []string
 	Subject string
 	Body    string
+	// Headers are the additional headers like List-Unsubscribe.
+	Headers map[string]string
 }
 
 // Topic represents a value object of a topic for subscription.
@@ -30,11 +32,35 @@ func (t Topic) QuoteCurrency() string {
 	return t.Quote
 }
 
+// Broadcast represents the topic the exchange rate data is requested for to be broadcast to its subscribers,
+// it tells the ones held back from the broadcast apart from the other requests.
+type Broadcast struct {
+	Topic
+}
+
+// Broadcast implements BroadcastEvent.
+func (Broadcast) Broadcast() bool {
+	return true
+}
+
 // ExchangeRateData represents exchange rate data for sending emails.
 type ExchangeRateData struct {
 	Pair         Topic
 	ExchangeRate float64
 	Subscribers  []string
+	// UnsubscribeLink is the link the subscriber unsubscribes with, empty when the message goes to many.
+	UnsubscribeLink string
+}
+
+// AlertData represents triggered alert data for sending emails.
+type AlertData struct {
+	Pair         Topic
+	ExchangeRate float64
+	// Condition is the rule the alert was triggered by, like "above 1500000".
+	Condition   string
+	Subscribers []string
+	// UnsubscribeLink is the link the subscriber unsubscribes with, empty when the message goes to many.
+	UnsubscribeLink string
 }
 
 func NewMessage(to []string, subject string, body string) *Message {
@@ -52,3 +78,27 @@ func NewExchangeRateData(pair Topic, xrt float64, subss []string) *ExchangeRateD
 		Subscribers:  subss,
 	}
 }
+
+func NewAlertData(pair Topic, xrt float64, cond string, subss []string) *AlertData {
+	return &AlertData{
+		Pair:         pair,
+		ExchangeRate: xrt,
+		Condition:    cond,
+		Subscribers:  subss,
+	}
+}
+
+// ConfirmationData represents subscription confirmation data for sending emails.
+type ConfirmationData struct {
+	Pair        Topic
+	Subscribers []string
+	ConfirmLink string
+}
+
+func NewConfirmationData(pair Topic, subs string, link string) *ConfirmationData {
+	return &ConfirmationData{
+		Pair:        pair,
+		Subscribers: []string{subs},
+		ConfirmLink: link,
+	}
+}
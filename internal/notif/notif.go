@@ -6,39 +6,105 @@ package notif
 
 import (
 	"context"
+	"errors"
 
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
 )
 
 type MessageCreator interface {
 	CreateMessage(*ExchangeRateData) (*Message, error)
+	CreateAlertMessage(*AlertData) (*Message, error)
+	CreateConfirmationMessage(*ConfirmationData) (*Message, error)
 }
 
 // Sender is an interface for sending messages.
+//
+//go:generate moq -out=../../test/mock/sender.go -pkg=mock . Sender
 type Sender interface {
 	Send(context.Context, *Message) (err error)
 }
 
+// Linker is an interface for creating the links embedded in the messages.
+//
+//go:generate moq -out=../../test/mock/linker.go -pkg=mock . Linker
+type Linker interface {
+	UnsubscribeLink(address, base, quote string) (string, error)
+	ConfirmLink(address, base, quote string) (string, error)
+}
+
 type Service struct {
 	bus  *event.Bus
 	sndr Sender
 	mc   MessageCreator
+	link Linker
 }
 
 func NewService(bus *event.Bus, sndr Sender, mc MessageCreator) *Service {
-	return &Service{bus: bus, sndr: sndr, mc: mc}
+	svc := Service{bus: bus, sndr: sndr, mc: mc}
+
+	svc.bus.Subscribe(event.New(EventSource, EventKindAlerted, nil), svc.NotifyAlert)
+	svc.bus.Subscribe(event.New(EventSource, EventKindConfirmationRequested, nil), svc.SendConfirmation)
+	svc.bus.Subscribe(event.New(EventSource, EventKindDigestDue, nil), svc.SendDigest)
+
+	return &svc
 }
 
+// UseLinker makes every subscriber receive the message of their own carrying their unsubscribe link.
+func (svc *Service) UseLinker(l Linker) {
+	svc.link = l
+}
+
+// SendEmails sends the exchange rate of the topic to its subscribers receiving it instantly,
+// the ones preferring the digest get it once it is due.
 func (svc *Service) SendEmails(ctx context.Context, topic Topic) error {
-	data, err := svc.RequestExchangeRateData(ctx, topic)
+	data, err := svc.requestData(ctx, topic, Broadcast{Topic: topic})
 	if err != nil {
 		return err
 	}
 
-	msg, err := svc.mc.CreateMessage(data)
-	if err != nil {
-		return err
+	return svc.send(ctx, topic, data.Subscribers, func(to []string, link string) (*Message, error) {
+		data := *data
+		data.Subscribers, data.UnsubscribeLink = to, link
+
+		return svc.mc.CreateMessage(&data)
+	})
+}
+
+// send sends the message created for the subscribers, either one to all of them or,
+// if Linker is set, one to every subscriber along with the List-Unsubscribe headers.
+func (svc *Service) send(ctx context.Context, topic Topic, subss []string, create func(to []string, link string) (*Message, error)) error {
+	if svc.link == nil {
+		msg, err := create(subss, "")
+		if err != nil {
+			return err
+		}
+
+		return svc.sndr.Send(ctx, msg)
+	}
+
+	var errs []error
+
+	for _, subs := range subss {
+		link, err := svc.link.UnsubscribeLink(subs, topic.Base, topic.Quote)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		msg, err := create([]string{subs}, link)
+		if err != nil {
+			return err
+		}
+
+		msg.Headers = map[string]string{
+			"List-Unsubscribe":      "<" + link + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+
+		if err := svc.sndr.Send(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	return svc.sndr.Send(ctx, msg)
+	return errors.Join(errs...)
 }
@@ -0,0 +1,293 @@
+package notif_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/notif"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/notif/tmpl"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var errSend = errors.New("send failed")
+
+type alertEvent struct {
+	notif.Topic
+	subss []string
+}
+
+func (e alertEvent) ExchangeRate() float64 { return 1500000 }
+func (e alertEvent) Subscribers() []string { return e.subss }
+func (e alertEvent) Condition() string     { return "above 1500000" }
+
+type subscribersEvent struct {
+	notif.Topic
+	subss []string
+}
+
+func (e subscribersEvent) Subscribers() []string { return e.subss }
+
+type exchangeRate float64
+
+func (x exchangeRate) ExchangeRate() float64 { return float64(x) }
+
+func newLinker(failFor string) *mock.LinkerMock {
+	link := func(purpose string) func(address, base, quote string) (string, error) {
+		return func(address, base, quote string) (string, error) {
+			if address == failFor {
+				return "", errSend
+			}
+
+			return "http://localhost/" + purpose + "?email=" + address, nil
+		}
+	}
+
+	return &mock.LinkerMock{UnsubscribeLinkFunc: link("unsubscribe"), ConfirmLinkFunc: link("confirm")}
+}
+
+func newSender(failFor string) *mock.SenderMock {
+	return &mock.SenderMock{SendFunc: func(_ context.Context, msg *notif.Message) error {
+		for _, to := range msg.To {
+			if to == failFor {
+				return errSend
+			}
+		}
+
+		return nil
+	}}
+}
+
+func newService(t *testing.T, sndr notif.Sender, link notif.Linker) (*notif.Service, *event.Bus) {
+	t.Helper()
+
+	tpl, err := tmpl.Load()
+	require.NoError(t, err)
+
+	bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+	svc := notif.NewService(bus, sndr, notif.NewExchangeRateContent(tpl))
+
+	if link != nil {
+		svc.UseLinker(link)
+	}
+
+	return svc, bus
+}
+
+func TestServiceNotifyAlert(t *testing.T) {
+	topic := notif.Topic{Base: "BTC", Quote: "UAH"}
+	subss := []string{"jon@example.com", "ann@example.com"}
+
+	tests := map[string]struct {
+		linker   bool
+		failFor  string
+		linkFail string
+		wantTo   [][]string
+		wantErr  error
+	}{
+		"one_message_without_linker": {
+			wantTo: [][]string{subss},
+		},
+		"message_per_recipient": {
+			linker: true,
+			wantTo: [][]string{{"jon@example.com"}, {"ann@example.com"}},
+		},
+		"send_errors_joined": {
+			linker:  true,
+			failFor: "jon@example.com",
+			wantTo:  [][]string{{"jon@example.com"}, {"ann@example.com"}},
+			wantErr: errSend,
+		},
+		"link_errors_joined": {
+			linker:   true,
+			linkFail: "jon@example.com",
+			wantTo:   [][]string{{"ann@example.com"}},
+			wantErr:  errSend,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			sndr := newSender(tc.failFor)
+
+			var link notif.Linker
+			if tc.linker {
+				link = newLinker(tc.linkFail)
+			}
+
+			svc, _ := newService(t, sndr, link)
+
+			err := svc.NotifyAlert(context.Background(), event.New(notif.EventSource, notif.EventKindAlerted, alertEvent{topic, subss}))
+			require.ErrorIs(t, err, tc.wantErr)
+
+			calls := sndr.SendCalls()
+			require.Len(t, calls, len(tc.wantTo))
+
+			for i, call := range calls {
+				require.Equal(t, tc.wantTo[i], call.Msg.To)
+				require.Contains(t, call.Msg.Body, "above 1500000")
+
+				if !tc.linker {
+					require.Empty(t, call.Msg.Headers)
+					continue
+				}
+
+				link := "http://localhost/unsubscribe?email=" + call.Msg.To[0]
+				require.Equal(t, map[string]string{
+					"List-Unsubscribe":      "<" + link + ">",
+					"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+				}, call.Msg.Headers)
+				require.Contains(t, call.Msg.Body, link)
+			}
+		})
+	}
+}
+
+func TestServiceSendConfirmation(t *testing.T) {
+	topic := notif.Topic{Base: "BTC", Quote: "UAH"}
+	subss := []string{"jon@example.com", "ann@example.com"}
+
+	tests := map[string]struct {
+		linker  bool
+		failFor string
+		wantTo  []string
+		wantErr error
+	}{
+		"missing_linker": {
+			wantErr: notif.ErrMissingLinker,
+		},
+		"message_per_recipient": {
+			linker: true,
+			wantTo: subss,
+		},
+		"send_errors_joined": {
+			linker:  true,
+			failFor: "ann@example.com",
+			wantTo:  subss,
+			wantErr: errSend,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			sndr := newSender(tc.failFor)
+
+			var link notif.Linker
+			if tc.linker {
+				link = newLinker("")
+			}
+
+			svc, _ := newService(t, sndr, link)
+
+			e := event.New(notif.EventSource, notif.EventKindConfirmationRequested, subscribersEvent{topic, subss})
+			err := svc.SendConfirmation(context.Background(), e)
+			require.ErrorIs(t, err, tc.wantErr)
+
+			calls := sndr.SendCalls()
+			require.Len(t, calls, len(tc.wantTo))
+
+			for i, call := range calls {
+				require.Equal(t, []string{tc.wantTo[i]}, call.Msg.To)
+				require.Contains(t, call.Msg.Body, "http://localhost/confirm?email="+tc.wantTo[i])
+				require.Empty(t, call.Msg.Headers)
+			}
+		})
+	}
+}
+
+func TestServiceSendDigest(t *testing.T) {
+	topic := notif.Topic{Base: "BTC", Quote: "UAH"}
+
+	tests := map[string]struct {
+		subss   []string
+		failFor string
+		wantErr error
+	}{
+		"message_per_recipient": {
+			subss: []string{"jon@example.com", "ann@example.com"},
+		},
+		"send_errors_joined": {
+			subss:   []string{"jon@example.com", "ann@example.com"},
+			failFor: "jon@example.com",
+			wantErr: errSend,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			sndr := newSender(tc.failFor)
+			svc, bus := newService(t, sndr, newLinker(""))
+
+			bus.Subscribe(event.New(notif.EventSource, notif.EventKindRequested, nil), func(_ context.Context, e event.Event) error {
+				e.Response <- event.New(notif.EventSource, "responded", exchangeRate(1500000))
+				e.Response <- event.New(notif.EventSource, "responded", subscribersEvent{topic, []string{}})
+
+				return nil
+			})
+
+			err := svc.SendDigest(context.Background(), event.New(notif.EventSource, notif.EventKindDigestDue, subscribersEvent{topic, tc.subss}))
+			require.ErrorIs(t, err, tc.wantErr)
+
+			calls := sndr.SendCalls()
+			require.Len(t, calls, len(tc.subss))
+
+			for i, call := range calls {
+				require.Equal(t, []string{tc.subss[i]}, call.Msg.To)
+				require.Contains(t, call.Msg.Body, "1.5e+06")
+				require.Equal(t, "<http://localhost/unsubscribe?email="+tc.subss[i]+">", call.Msg.Headers["List-Unsubscribe"])
+			}
+		})
+	}
+}
+
+func TestServiceRequestBroadcast(t *testing.T) {
+	topic := notif.Topic{Base: "BTC", Quote: "UAH"}
+
+	tests := map[string]struct {
+		call          func(svc *notif.Service) error
+		wantBroadcast bool
+	}{
+		"send_emails_broadcast": {
+			call:          func(svc *notif.Service) error { return svc.SendEmails(context.Background(), topic) },
+			wantBroadcast: true,
+		},
+		"send_digest_not_broadcast": {
+			call: func(svc *notif.Service) error {
+				e := event.New(notif.EventSource, notif.EventKindDigestDue, subscribersEvent{topic, []string{"jon@example.com"}})
+				return svc.SendDigest(context.Background(), e)
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			svc, bus := newService(t, newSender(""), newLinker(""))
+
+			var broadcast bool
+
+			bus.Subscribe(event.New(notif.EventSource, notif.EventKindRequested, nil), func(_ context.Context, e event.Event) error {
+				b, ok := e.Payload.(notif.BroadcastEvent)
+				broadcast = ok && b.Broadcast()
+
+				e.Response <- event.New(notif.EventSource, "responded", exchangeRate(1500000))
+				e.Response <- event.New(notif.EventSource, "responded", subscribersEvent{topic, []string{}})
+
+				return nil
+			})
+
+			require.NoError(t, tc.call(svc))
+			require.Equal(t, tc.wantBroadcast, broadcast)
+		})
+	}
+}
@@ -12,9 +12,13 @@ var (
 )
 
 const (
-	TemplateEmail        = "email"
-	TemplateEmailSubject = "subject"
-	TemplateEmailBody    = "body"
+	TemplateEmail          = "email"
+	TemplateEmailSubject   = "subject"
+	TemplateEmailBody      = "body"
+	TemplateAlertSubject   = "alert_subject"
+	TemplateAlertBody      = "alert_body"
+	TemplateConfirmSubject = "confirm_subject"
+	TemplateConfirmBody    = "confirm_body"
 )
 
 // Load parses templates in the current directory.
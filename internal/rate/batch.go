@@ -0,0 +1,76 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrTooManyPairs = errors.New("too many currency pairs")
+
+// BatchConfig defines how many currency pairs are resolved at once.
+type BatchConfig struct {
+	// Workers is the number of pairs resolved concurrently.
+	Workers int
+	// MaxPairs is the largest number of pairs in one batch.
+	MaxPairs int
+}
+
+// PairResult represents the outcome of getting the exchange rate for a single pair of the batch.
+type PairResult struct {
+	Pair         CurrencyPair
+	ExchangeRate *ExchangeRate
+	Err          error
+}
+
+// Batcher resolves many currency pairs concurrently with a bounded pool of workers.
+// Concurrent requests reach the providers together, so the ones supporting multi-symbol queries may merge them.
+type Batcher struct {
+	svc ExchangeRateService
+	cfg BatchConfig
+}
+
+// NewBatcher creates a new Batcher instance.
+func NewBatcher(svc ExchangeRateService, cfg BatchConfig) *Batcher {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+
+	return &Batcher{svc: svc, cfg: cfg}
+}
+
+// GetExchangeRates gets the exchange rates for the pairs, results are in the order of the pairs.
+// Failure for a single pair is reported in its result, the error is returned for the batch as a whole only.
+func (b *Batcher) GetExchangeRates(ctx context.Context, pairs ...CurrencyPair) ([]PairResult, error) {
+	if b.cfg.MaxPairs > 0 && len(pairs) > b.cfg.MaxPairs {
+		return nil, fmt.Errorf("%w: %d, max %d", ErrTooManyPairs, len(pairs), b.cfg.MaxPairs)
+	}
+
+	results := make([]PairResult, len(pairs))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < b.cfg.Workers && w < len(pairs); w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				xrt, err := b.svc.GetExchangeRate(ctx, pairs[i])
+				results[i] = PairResult{Pair: pairs[i], ExchangeRate: xrt, Err: err}
+			}
+		}()
+	}
+
+	for i := range pairs {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return results, nil
+}
@@ -0,0 +1,137 @@
+package rate_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatcherGetExchangeRates(t *testing.T) {
+	errDown := errors.New("down")
+
+	tests := map[string]struct {
+		cfg       rate.BatchConfig
+		pairs     []rate.CurrencyPair
+		wantRates []float64
+		wantErrs  []error
+		wantErr   error
+	}{
+		"all_resolved_in_order": {
+			cfg: rate.BatchConfig{Workers: 2},
+			pairs: []rate.CurrencyPair{
+				rate.NewCurrencyPair("BTC", "UAH"), rate.NewCurrencyPair("ETH", "USD"), rate.NewCurrencyPair("EUR", "USD"),
+			},
+			wantRates: []float64{3, 3, 3},
+			wantErrs:  []error{nil, nil, nil},
+		},
+		"per_pair_error": {
+			cfg:       rate.BatchConfig{Workers: 4},
+			pairs:     []rate.CurrencyPair{rate.NewCurrencyPair("BTC", "UAH"), rate.NewCurrencyPair("XXX", "USD")},
+			wantRates: []float64{3, 0},
+			wantErrs:  []error{nil, errDown},
+		},
+		"too_many_pairs": {
+			cfg:     rate.BatchConfig{Workers: 1, MaxPairs: 1},
+			pairs:   []rate.CurrencyPair{rate.NewCurrencyPair("BTC", "UAH"), rate.NewCurrencyPair("ETH", "USD")},
+			wantErr: rate.ErrTooManyPairs,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var running, peak int32
+
+			svc := &mock.ExchangeRateServiceMock{
+				GetExchangeRateFunc: func(_ context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+					n := atomic.AddInt32(&running, 1)
+					defer atomic.AddInt32(&running, -1)
+
+					for {
+						p := atomic.LoadInt32(&peak)
+						if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+							break
+						}
+					}
+
+					time.Sleep(10 * time.Millisecond)
+
+					if pair.Base == "XXX" {
+						return nil, errDown
+					}
+
+					return &rate.ExchangeRate{Pair: pair, Value: float64(len(pair.Base))}, nil
+				},
+			}
+
+			results, err := rate.NewBatcher(svc, tc.cfg).GetExchangeRates(context.Background(), tc.pairs...)
+			require.ErrorIs(t, err, tc.wantErr)
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			require.Len(t, results, len(tc.pairs))
+			require.LessOrEqual(t, int(peak), tc.cfg.Workers)
+
+			for i, res := range results {
+				require.Equal(t, tc.pairs[i], res.Pair)
+				require.ErrorIs(t, res.Err, tc.wantErrs[i])
+
+				if tc.wantErrs[i] == nil {
+					require.Equal(t, tc.wantRates[i], res.ExchangeRate.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCurrencyPair(t *testing.T) {
+	tests := map[string]struct {
+		val     string
+		want    rate.CurrencyPair
+		wantErr error
+	}{
+		"valid":         {val: "btc/uah", want: rate.NewCurrencyPair("BTC", "UAH")},
+		"spaces":        {val: " ETH / USD ", want: rate.NewCurrencyPair("ETH", "USD")},
+		"missing_slash": {val: "BTCUAH", wantErr: rate.ErrInvalidCurrency},
+		"missing_quote": {val: "BTC/", wantErr: rate.ErrInvalidCurrency},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := rate.ParseCurrencyPair(tc.val)
+			require.ErrorIs(t, err, tc.wantErr)
+
+			if tc.wantErr == nil {
+				require.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCurrencyPairValidateWith(t *testing.T) {
+	reg := currency.NewRegistry(append(currency.ISO4217(), currency.Crypto()...)...)
+
+	tests := map[string]struct {
+		pair    rate.CurrencyPair
+		wantErr error
+	}{
+		"known":         {pair: rate.NewCurrencyPair("BTC", "UAH")},
+		"unknown_base":  {pair: rate.NewCurrencyPair("ZZZ", "UAH"), wantErr: currency.ErrUnknownCurrency},
+		"unknown_quote": {pair: rate.NewCurrencyPair("BTC", "ZZZ"), wantErr: rate.ErrInvalidCurrency},
+		"missing_quote": {pair: rate.NewCurrencyPair("BTC", ""), wantErr: rate.ErrInvalidCurrency},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, tc.pair.ValidateWith(reg), tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+)
+
+const (
+	EventKindBreakerChanged = "breaker_changed"
+
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState represents a state of the provider circuit breaker.
+type BreakerState string
+
+// BreakerConfig defines when the provider circuit breaker opens and how long it stays open.
+type BreakerConfig struct {
+	// Threshold is the number of consecutive failures after which the breaker opens.
+	Threshold int
+	// Cooldown is the duration the breaker stays open before letting a probe call through.
+	Cooldown time.Duration
+}
+
+// BreakerStatus represents the current status of the provider circuit breaker.
+type BreakerStatus struct {
+	Provider string
+	State    BreakerState
+	Failures int
+	// OpenedAt is the time the breaker has been opened last, zero if never.
+	OpenedAt time.Time
+	// LastError is the category of the last failure counted by the breaker, see ErrorCategory.
+	LastError string
+}
+
+// BreakerChange represents the data of a breaker state change event.
+type BreakerChange struct {
+	Provider string
+	From, To BreakerState
+	Failures int
+	Err      error
+}
+
+// Breakers keeps a circuit breaker for every wrapped provider, so unhealthy providers are skipped immediately.
+// A breaker opens after the configured number of consecutive failures,
+// lets a single probe call through after the cooldown and closes on its success.
+type Breakers struct {
+	bus *event.Bus
+	cfg BreakerConfig
+
+	mu    sync.Mutex
+	items map[string]*breaker
+}
+
+type breaker struct {
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	lastErr  error
+	probing  bool
+}
+
+// NewBreakers creates a new Breakers instance.
+func NewBreakers(bus *event.Bus, cfg BreakerConfig) *Breakers {
+	if cfg.Threshold < 1 {
+		cfg.Threshold = 1
+	}
+
+	return &Breakers{
+		bus:   bus,
+		cfg:   cfg,
+		items: make(map[string]*breaker),
+	}
+}
+
+// Wrap returns the provider guarded by its circuit breaker.
+func (b *Breakers) Wrap(prov ExchangeRateProvider) ExchangeRateProvider {
+	b.mu.Lock()
+	if _, ok := b.items[prov.String()]; !ok {
+		b.items[prov.String()] = &breaker{state: BreakerClosed}
+	}
+	b.mu.Unlock()
+
+	return &breakerProvider{ExchangeRateProvider: prov, brk: b}
+}
+
+// Status returns the status of all breakers ordered by provider name.
+func (b *Breakers) Status() []BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	list := make([]BreakerStatus, 0, len(b.items))
+
+	for name, brk := range b.items {
+		st := BreakerStatus{
+			Provider: name,
+			State:    brk.state,
+			Failures: brk.failures,
+			OpenedAt: brk.openedAt,
+		}
+
+		if brk.lastErr != nil {
+			st.LastError = ErrorCategory(brk.lastErr)
+		}
+
+		list = append(list, st)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Provider < list[j].Provider })
+
+	return list
+}
+
+// allow reports whether the call to the provider is allowed, moving the open breaker to half-open after the cooldown.
+func (b *Breakers) allow(name string) (bool, *BreakerChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	brk := b.items[name]
+
+	switch brk.state {
+	case BreakerOpen:
+		if time.Since(brk.openedAt) < b.cfg.Cooldown {
+			return false, nil
+		}
+
+		brk.probing = true
+
+		return true, brk.move(name, BreakerHalfOpen)
+
+	case BreakerHalfOpen:
+		if brk.probing {
+			return false, nil
+		}
+
+		brk.probing = true
+
+		return true, nil
+
+	default:
+		return true, nil
+	}
+}
+
+// record records the outcome of the provider call.
+func (b *Breakers) record(name string, err error) *BreakerChange {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	brk := b.items[name]
+	brk.probing = false
+
+	if err == nil {
+		brk.failures = 0
+		if brk.state != BreakerClosed {
+			return brk.move(name, BreakerClosed)
+		}
+
+		return nil
+	}
+
+	if !isProviderFault(err) {
+		return nil
+	}
+
+	brk.failures++
+	brk.lastErr = err
+
+	if brk.state == BreakerHalfOpen || (brk.state == BreakerClosed && brk.failures >= b.cfg.Threshold) {
+		brk.openedAt = time.Now()
+		return brk.move(name, BreakerOpen)
+	}
+
+	return nil
+}
+
+// move changes the state of the breaker returning the change to be published.
+func (brk *breaker) move(name string, to BreakerState) *BreakerChange {
+	chg := BreakerChange{Provider: name, From: brk.state, To: to, Failures: brk.failures, Err: brk.lastErr}
+	brk.state = to
+
+	return &chg
+}
+
+func (b *Breakers) publish(ctx context.Context, chg *BreakerChange) error {
+	if chg == nil {
+		return nil
+	}
+
+	return b.bus.Publish(ctx, event.New(EventSource, EventKindBreakerChanged, *chg))
+}
+
+type breakerProvider struct {
+	ExchangeRateProvider
+	brk *Breakers
+}
+
+func (p *breakerProvider) GetExchangeRate(ctx context.Context, pair CurrencyPair) (*ExchangeRate, error) {
+	ok, chg := p.brk.allow(p.String())
+	if err := p.brk.publish(ctx, chg); err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, p)
+	}
+
+	xrt, err := p.ExchangeRateProvider.GetExchangeRate(ctx, pair)
+	if errpub := p.brk.publish(ctx, p.brk.record(p.String(), err)); errpub != nil {
+		return nil, errors.Join(err, errpub)
+	}
+
+	return xrt, err
+}
+
+// isProviderFault reports whether the error tells about the provider health rather than the request.
+func isProviderFault(err error) bool {
+	// A deviating quote is a disagreement with the other providers rather than a failure of the provider,
+	// the unknown symbol is a well-formed answer as well.
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrRateDeviation) || errors.Is(err, ErrUnknownSymbol) || isSkipped(err) {
+		return false
+	}
+
+	reqErr, ok := web.IsError[*web.RequestError](err)
+
+	return !ok || reqErr.StatusCode != http.StatusBadRequest
+}
+
+// ErrorCategory returns the category of the provider failure like "status_503" or "timeout" safe to be exposed,
+// unlike the error text which may carry the request URL along with the API key.
+func ErrorCategory(err error) string {
+	var (
+		reqErr *web.RequestError
+		netErr net.Error
+	)
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrQuotaExhausted):
+		return "quota_exhausted"
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case isUnsupported(err):
+		return "unsupported_pair"
+	case errors.Is(err, ErrRejectedQuote):
+		return "rejected_quote"
+	case errors.Is(err, ErrProviderUnavailable):
+		return "unavailable"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &reqErr):
+		return fmt.Sprintf("status_%d", reqErr.StatusCode)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &netErr):
+		return "network"
+	}
+
+	return "provider_error"
+}
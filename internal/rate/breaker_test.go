@@ -0,0 +1,166 @@
+package rate_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreakersWrap(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+
+	log := logger.New(logger.WithConsoleCore(logger.LevelDebug))
+	bus := event.NewBus(log)
+	pair := rate.NewCurrencyPair("BTC", "UAH")
+
+	changes := make(chan rate.BreakerChange, 10)
+	bus.Subscribe(event.New(rate.EventSource, rate.EventKindBreakerChanged, nil), func(ctx context.Context, e event.Event) error {
+		changes <- e.Payload.(rate.BreakerChange)
+		return nil
+	})
+
+	var (
+		calls int
+		fail  = true
+	)
+
+	prov := &mock.ExchangeRateProviderMock{
+		GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+			calls++
+			if fail {
+				return nil, errors.New("mock error")
+			}
+
+			return rate.NewExchangeRate(1, pair), nil
+		},
+		StringFunc: func() string { return "TestProvider" },
+	}
+
+	brk := rate.NewBreakers(bus, rate.BreakerConfig{Threshold: 2, Cooldown: cooldown})
+	wrapped := brk.Wrap(prov)
+
+	// wantChanges waits for the state changes, listeners are run concurrently so the order is not guaranteed.
+	wantChanges := func(want ...[2]rate.BreakerState) {
+		t.Helper()
+
+		got := make([][2]rate.BreakerState, 0, len(want))
+
+		for range want {
+			select {
+			case chg := <-changes:
+				require.Equal(t, prov.String(), chg.Provider)
+				got = append(got, [2]rate.BreakerState{chg.From, chg.To})
+			case <-time.After(time.Second):
+				t.Fatalf("missing breaker changes, want: %v, got: %v", want, got)
+			}
+		}
+
+		require.ElementsMatch(t, want, got)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := wrapped.GetExchangeRate(context.Background(), pair)
+		require.Error(t, err)
+	}
+
+	require.Equal(t, 2, calls)
+	require.Equal(t, rate.BreakerOpen, brk.Status()[0].State)
+	wantChanges([2]rate.BreakerState{rate.BreakerClosed, rate.BreakerOpen})
+
+	_, err := wrapped.GetExchangeRate(context.Background(), pair)
+	require.ErrorIs(t, err, rate.ErrCircuitOpen)
+
+	time.Sleep(cooldown)
+
+	_, err = wrapped.GetExchangeRate(context.Background(), pair)
+	require.Error(t, err)
+	require.Equal(t, 3, calls)
+	wantChanges(
+		[2]rate.BreakerState{rate.BreakerOpen, rate.BreakerHalfOpen},
+		[2]rate.BreakerState{rate.BreakerHalfOpen, rate.BreakerOpen},
+	)
+
+	time.Sleep(cooldown)
+
+	fail = false
+
+	xrt, err := wrapped.GetExchangeRate(context.Background(), pair)
+	require.NoError(t, err)
+	require.Equal(t, 1.0, xrt.Value)
+	wantChanges(
+		[2]rate.BreakerState{rate.BreakerOpen, rate.BreakerHalfOpen},
+		[2]rate.BreakerState{rate.BreakerHalfOpen, rate.BreakerClosed},
+	)
+
+	st := brk.Status()
+	require.Len(t, st, 1)
+	require.Equal(t, rate.BreakerClosed, st[0].State)
+	require.Zero(t, st[0].Failures)
+}
+
+func TestBreakersWrapDeviation(t *testing.T) {
+	var calls int
+
+	prov := &mock.ExchangeRateProviderMock{
+		GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+			calls++
+			return nil, fmt.Errorf("%w: %w", rate.ErrRejectedQuote, rate.ErrRateDeviation)
+		},
+		StringFunc: func() string { return "TestProvider" },
+	}
+
+	brk := rate.NewBreakers(event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug))), rate.BreakerConfig{Threshold: 1})
+	wrapped := brk.Wrap(prov)
+
+	for i := 0; i < 3; i++ {
+		_, err := wrapped.GetExchangeRate(context.Background(), rate.NewCurrencyPair("BTC", "UAH"))
+		require.ErrorIs(t, err, rate.ErrRateDeviation)
+	}
+
+	require.Equal(t, 3, calls)
+	require.Equal(t, rate.BreakerClosed, brk.Status()[0].State)
+	require.Zero(t, brk.Status()[0].Failures)
+}
+
+func TestErrorCategory(t *testing.T) {
+	leaky := &url.Error{
+		Op:  "Get",
+		URL: "https://www.alphavantage.co/query?apikey=secret",
+		Err: &net.OpError{Op: "dial", Err: errors.New("refused")},
+	}
+
+	tests := map[string]struct {
+		err  error
+		want string
+	}{
+		"none":        {err: nil, want: ""},
+		"quota":       {err: fmt.Errorf("calling: %w", rate.ErrQuotaExhausted), want: "quota_exhausted"},
+		"open":        {err: rate.ErrCircuitOpen, want: "circuit_open"},
+		"unsupported": {err: rate.ErrUnsupportedPair, want: "unsupported_pair"},
+		"rejected":    {err: fmt.Errorf("%w: %w", rate.ErrRejectedQuote, rate.ErrRateDeviation), want: "rejected_quote"},
+		"status":      {err: web.ErrFromStatusCode(http.StatusServiceUnavailable), want: "status_503"},
+		"timeout":     {err: fmt.Errorf("fetching: %w", context.DeadlineExceeded), want: "timeout"},
+		"network":     {err: leaky, want: "network"},
+		"other":       {err: errors.New("apikey=secret"), want: "provider_error"},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, rate.ErrorCategory(tc.err))
+		})
+	}
+}
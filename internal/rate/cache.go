@@ -0,0 +1,191 @@
+package rate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+const (
+	EventKindCacheHit  = "cache_hit"
+	EventKindCacheMiss = "cache_missed"
+)
+
+// CacheConfig defines the caching behaviour.
+type CacheConfig struct {
+	// TTL is the duration the cached exchange rate is considered fresh.
+	TTL time.Duration
+	// Stale is the duration after TTL the cached exchange rate
+	// is still served while being revalidated in background.
+	Stale time.Duration
+	// MaxItems is the most pairs cached, the one fetched the longest ago is evicted beyond it.
+	// Zero stands for defaultCacheItems.
+	MaxItems int
+}
+
+// defaultCacheItems bounds the cache when no MaxItems is configured.
+const defaultCacheItems = 1024
+
+// CacheResponse represents the data of a cache hit or miss event.
+type CacheResponse struct {
+	Pair  CurrencyPair
+	Age   time.Duration
+	Stale bool
+}
+
+// Cache is a caching ExchangeRateService decorator keyed by CurrencyPair.
+// Concurrent requests for the same pair are coalesced into a single upstream call.
+type Cache struct {
+	bus *event.Bus
+	svc ExchangeRateService
+	cfg CacheConfig
+
+	mu      sync.Mutex
+	items   map[CurrencyPair]*ExchangeRate
+	flights map[CurrencyPair]*flight
+}
+
+// flight represents an in-flight upstream call shared by the coalesced requests.
+type flight struct {
+	done chan struct{}
+	xrt  *ExchangeRate
+	err  error
+}
+
+// NewCache wraps ExchangeRateService with in-memory cache.
+func NewCache(bus *event.Bus, svc ExchangeRateService, cfg CacheConfig) *Cache {
+	if cfg.MaxItems <= 0 {
+		cfg.MaxItems = defaultCacheItems
+	}
+
+	return &Cache{
+		bus:     bus,
+		svc:     svc,
+		cfg:     cfg,
+		items:   make(map[CurrencyPair]*ExchangeRate),
+		flights: make(map[CurrencyPair]*flight),
+	}
+}
+
+// GetExchangeRate returns the cached exchange rate if it is fresh enough,
+// otherwise it fetches it from the underlying service.
+// A stale exchange rate is returned immediately while being refreshed in background.
+func (c *Cache) GetExchangeRate(ctx context.Context, pair CurrencyPair) (*ExchangeRate, error) {
+	if err := pair.Validate(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	xrt, ok := c.items[pair]
+	c.mu.Unlock()
+
+	if ok {
+		age := time.Since(xrt.FetchedAt)
+
+		switch {
+		case age < c.cfg.TTL:
+			c.publish(ctx, EventKindCacheHit, CacheResponse{Pair: pair, Age: age})
+			return xrt.clone(), nil
+
+		case age < c.cfg.TTL+c.cfg.Stale:
+			c.publish(ctx, EventKindCacheHit, CacheResponse{Pair: pair, Age: age, Stale: true})
+			c.fetch(ctx, pair)
+
+			return xrt.clone(), nil
+		}
+	}
+
+	c.publish(ctx, EventKindCacheMiss, CacheResponse{Pair: pair})
+
+	return c.wait(ctx, c.fetch(ctx, pair))
+}
+
+// Refresh fetches the exchange rate from the underlying service regardless of the cached one and caches it.
+func (c *Cache) Refresh(ctx context.Context, pair CurrencyPair) (*ExchangeRate, error) {
+	if err := pair.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.wait(ctx, c.fetch(ctx, pair))
+}
+
+// wait returns the result of the in-flight call unless the context is done first.
+func (c *Cache) wait(ctx context.Context, f *flight) (*ExchangeRate, error) {
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for exchange rate: %w", ctx.Err())
+
+	case <-f.done:
+		if f.err != nil {
+			return nil, f.err
+		}
+
+		return f.xrt.clone(), nil
+	}
+}
+
+// fetch starts a new upstream call for the pair unless there is one in-flight already.
+// The call is detached from the request context, so it is not interrupted by the caller leaving,
+// it is a background one still if the request is.
+func (c *Cache) fetch(ctx context.Context, pair CurrencyPair) *flight {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f, ok := c.flights[pair]; ok {
+		return f
+	}
+
+	f := &flight{done: make(chan struct{})}
+	c.flights[pair] = f
+
+	go func() {
+		defer close(f.done)
+
+		ctx, cancel := context.WithTimeout(detach(ctx), defaultTimeout)
+		defer cancel()
+
+		f.xrt, f.err = c.svc.GetExchangeRate(ctx, pair)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.flights, pair)
+
+		if f.err == nil {
+			if f.xrt.FetchedAt.IsZero() {
+				f.xrt.FetchedAt = time.Now()
+			}
+
+			c.items[pair] = f.xrt
+			c.evict()
+		}
+	}()
+
+	return f
+}
+
+// evict drops the exchange rates fetched the longest ago while there are more of them than MaxItems.
+// It has to be called with the mutex held.
+func (c *Cache) evict() {
+	for len(c.items) > c.cfg.MaxItems {
+		var (
+			oldest CurrencyPair
+			at     time.Time
+		)
+
+		for pair, xrt := range c.items {
+			if at.IsZero() || xrt.FetchedAt.Before(at) {
+				oldest, at = pair, xrt.FetchedAt
+			}
+		}
+
+		delete(c.items, oldest)
+	}
+}
+
+func (c *Cache) publish(ctx context.Context, kind event.Kind, resp CacheResponse) {
+	_ = c.bus.Publish(ctx, event.New(EventSource, kind, resp))
+}
@@ -0,0 +1,164 @@
+package rate_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheGetExchangeRate(t *testing.T) {
+	log := logger.New(logger.WithConsoleCore(logger.LevelDebug))
+	bus := event.NewBus(log)
+	pair := rate.NewCurrencyPair("BTC", "UAH")
+
+	t.Run("coalesce_concurrent_requests", func(t *testing.T) {
+		var calls int32
+
+		svc := &mock.ExchangeRateServiceMock{
+			GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(50 * time.Millisecond)
+
+				return rate.NewExchangeRate(1.2, pair), nil
+			},
+		}
+
+		cache := rate.NewCache(bus, svc, rate.CacheConfig{TTL: time.Minute})
+
+		const reqNum = 100
+
+		var wg sync.WaitGroup
+
+		wg.Add(reqNum)
+
+		for i := 0; i < reqNum; i++ {
+			go func() {
+				defer wg.Done()
+
+				xrt, err := cache.GetExchangeRate(context.Background(), pair)
+				require.NoError(t, err)
+				require.Equal(t, 1.2, xrt.Value)
+			}()
+		}
+
+		wg.Wait()
+
+		require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("refetch_expired", func(t *testing.T) {
+		var calls int32
+
+		svc := &mock.ExchangeRateServiceMock{
+			GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+				return rate.NewExchangeRate(float64(atomic.AddInt32(&calls, 1)), pair), nil
+			},
+		}
+
+		cache := rate.NewCache(bus, svc, rate.CacheConfig{TTL: 10 * time.Millisecond})
+
+		xrt, err := cache.GetExchangeRate(context.Background(), pair)
+		require.NoError(t, err)
+		require.Equal(t, 1.0, xrt.Value)
+
+		time.Sleep(20 * time.Millisecond)
+
+		xrt, err = cache.GetExchangeRate(context.Background(), pair)
+		require.NoError(t, err)
+		require.Equal(t, 2.0, xrt.Value)
+	})
+
+	t.Run("serve_stale_while_revalidate", func(t *testing.T) {
+		var calls int32
+
+		svc := &mock.ExchangeRateServiceMock{
+			GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+				return rate.NewExchangeRate(float64(atomic.AddInt32(&calls, 1)), pair), nil
+			},
+		}
+
+		cache := rate.NewCache(bus, svc, rate.CacheConfig{TTL: 10 * time.Millisecond, Stale: time.Minute})
+
+		_, err := cache.GetExchangeRate(context.Background(), pair)
+		require.NoError(t, err)
+
+		time.Sleep(20 * time.Millisecond)
+
+		xrt, err := cache.GetExchangeRate(context.Background(), pair)
+		require.NoError(t, err)
+		require.Equal(t, 1.0, xrt.Value)
+
+		require.Eventually(t, func() bool {
+			xrt, err := cache.GetExchangeRate(context.Background(), pair)
+			return err == nil && xrt.Value == 2.0
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("evict_beyond_max_items", func(t *testing.T) {
+		var calls int32
+
+		svc := &mock.ExchangeRateServiceMock{
+			GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+				return rate.NewExchangeRate(float64(atomic.AddInt32(&calls, 1)), pair), nil
+			},
+		}
+
+		cache := rate.NewCache(bus, svc, rate.CacheConfig{TTL: time.Minute, MaxItems: 1})
+
+		for _, p := range []rate.CurrencyPair{pair, rate.NewCurrencyPair("ETH", "UAH"), pair} {
+			_, err := cache.GetExchangeRate(context.Background(), p)
+			require.NoError(t, err)
+		}
+
+		require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("keep_background_marker", func(t *testing.T) {
+		bg := make(chan bool, 2)
+
+		svc := &mock.ExchangeRateServiceMock{
+			GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+				bg <- rate.IsBackground(ctx)
+				return rate.NewExchangeRate(1.2, pair), nil
+			},
+		}
+
+		cache := rate.NewCache(bus, svc, rate.CacheConfig{TTL: time.Minute})
+
+		_, err := cache.Refresh(rate.WithBackground(context.Background()), pair)
+		require.NoError(t, err)
+		require.True(t, <-bg)
+
+		_, err = cache.Refresh(context.Background(), pair)
+		require.NoError(t, err)
+		require.False(t, <-bg)
+	})
+
+	t.Run("do_not_cache_errors", func(t *testing.T) {
+		var calls int32
+
+		svc := &mock.ExchangeRateServiceMock{
+			GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+				atomic.AddInt32(&calls, 1)
+				return nil, rate.ErrProviderUnavailable
+			},
+		}
+
+		cache := rate.NewCache(bus, svc, rate.CacheConfig{TTL: time.Minute})
+
+		for i := 0; i < 2; i++ {
+			_, err := cache.GetExchangeRate(context.Background(), pair)
+			require.ErrorIs(t, err, rate.ErrProviderUnavailable)
+		}
+
+		require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	})
+}
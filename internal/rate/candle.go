@@ -0,0 +1,235 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+var ErrUnsupportedInterval = errors.New("unsupported interval")
+
+// Candle represents open/high/low/close exchange rates of a pair within an interval.
+type Candle struct {
+	Pair     CurrencyPair
+	Start    time.Time
+	Interval time.Duration
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	Count    int
+
+	openAt, closeAt time.Time
+}
+
+// Candles aggregates fetched exchange rates into candles incrementally.
+type Candles struct {
+	cfg       HistoryConfig
+	intervals map[time.Duration]bool
+
+	mu     sync.RWMutex
+	series map[candleKey][]Candle
+}
+
+type candleKey struct {
+	pair     CurrencyPair
+	interval time.Duration
+}
+
+// NewCandles aggregates the stored exchange rates within the retention into candles
+// of the given intervals and subscribes Candles to the fetched exchange rates.
+func NewCandles(bus *event.Bus, store HistoryStorer, cfg HistoryConfig, intervals ...time.Duration) (*Candles, error) {
+	xrts, err := store.FetchAll()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("loading candles: %w", err)
+	}
+
+	c := Candles{
+		cfg:       cfg,
+		intervals: make(map[time.Duration]bool, len(intervals)),
+		series:    make(map[candleKey][]Candle),
+	}
+
+	for _, d := range intervals {
+		if d <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedInterval, d)
+		}
+
+		c.intervals[d] = true
+	}
+
+	now := time.Now()
+
+	for i := range xrts {
+		if !cfg.expired(xrts[i].FetchedAt, now) {
+			c.add(xrts[i])
+		}
+	}
+
+	bus.Subscribe(event.New(EventSource, EventKindFetched, nil), c.AggregateExchangeRate)
+
+	return &c, nil
+}
+
+// AggregateExchangeRate is an event listener that updates candles with every fetched exchange rate.
+func (c *Candles) AggregateExchangeRate(ctx context.Context, e event.Event) error {
+	resp, ok := e.Payload.(ProviderResponse)
+	if !ok {
+		return fmt.Errorf("%w: unexpected payload, expected ProviderResponse: %T", ErrInvalidEvent, e.Payload)
+	}
+
+	if resp.ExchangeRate == nil {
+		return fmt.Errorf("%w: missing exchange rate from %s", ErrInvalidEvent, resp.Provider)
+	}
+
+	c.add(*resp.ExchangeRate)
+
+	return nil
+}
+
+// Range returns the candles of the pair and interval started within [from, to) in chronological order.
+func (c *Candles) Range(_ context.Context, pair CurrencyPair, interval time.Duration, from, to time.Time) ([]Candle, error) {
+	if err := pair.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !c.intervals[interval] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedInterval, interval)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	series := c.series[candleKey{pair: pair, interval: interval}]
+
+	lo := sort.Search(len(series), func(i int) bool { return !series[i].Start.Before(from) })
+	hi := sort.Search(len(series), func(i int) bool { return !series[i].Start.Before(to) })
+
+	return append([]Candle(nil), series[lo:hi]...), nil
+}
+
+// Run drops the expired candles every interval until the context is done.
+func (c *Candles) Run(ctx context.Context) {
+	if c.cfg.Retention <= 0 || c.cfg.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.Compact(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Compact drops the candles ended beyond the retention at the time.
+func (c *Candles) Compact(at time.Time) {
+	if c.cfg.Retention <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, series := range c.series {
+		i := sort.Search(len(series), func(i int) bool {
+			return !c.cfg.expired(series[i].Start.Add(series[i].Interval), at)
+		})
+
+		switch {
+		case i == len(series):
+			delete(c.series, key)
+		case i > 0:
+			// The series is copied, so the expired candles are released.
+			c.series[key] = append([]Candle(nil), series[i:]...)
+		}
+	}
+}
+
+// add updates the candles of every interval with the exchange rate.
+// Exchange rates arriving out of order update open and close only if they are earlier or later respectively.
+func (c *Candles) add(xrt ExchangeRate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for interval := range c.intervals {
+		key := candleKey{pair: xrt.Pair, interval: interval}
+		start := xrt.FetchedAt.Truncate(interval)
+		series := c.series[key]
+
+		i := sort.Search(len(series), func(i int) bool { return !series[i].Start.Before(start) })
+		if i == len(series) || !series[i].Start.Equal(start) {
+			series = append(series, Candle{})
+			copy(series[i+1:], series[i:])
+			series[i] = Candle{
+				Pair:     xrt.Pair,
+				Start:    start,
+				Interval: interval,
+				Open:     xrt.Value,
+				High:     xrt.Value,
+				Low:      xrt.Value,
+				Close:    xrt.Value,
+				openAt:   xrt.FetchedAt,
+				closeAt:  xrt.FetchedAt,
+			}
+		}
+
+		series[i].update(xrt)
+		c.series[key] = series
+	}
+}
+
+func (cd *Candle) update(xrt ExchangeRate) {
+	cd.Count++
+
+	if xrt.Value > cd.High {
+		cd.High = xrt.Value
+	}
+
+	if xrt.Value < cd.Low {
+		cd.Low = xrt.Value
+	}
+
+	if xrt.FetchedAt.Before(cd.openAt) {
+		cd.Open, cd.openAt = xrt.Value, xrt.FetchedAt
+	}
+
+	if !xrt.FetchedAt.Before(cd.closeAt) {
+		cd.Close, cd.closeAt = xrt.Value, xrt.FetchedAt
+	}
+}
+
+// ParseInterval parses a duration string additionally accepting the "d" unit for days, e.g. "1d".
+func ParseInterval(val string) (time.Duration, error) {
+	const day = 24 * time.Hour
+
+	if days, ok := strings.CutSuffix(val, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrUnsupportedInterval, val)
+		}
+
+		return time.Duration(n) * day, nil
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrUnsupportedInterval, val)
+	}
+
+	return d, nil
+}
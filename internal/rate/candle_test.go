@@ -0,0 +1,128 @@
+package rate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/filestore"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandles(t *testing.T) {
+	log := logger.New(logger.WithConsoleCore(logger.LevelDebug))
+	bus := event.NewBus(log)
+	pair := rate.NewCurrencyPair("BTC", "UAH")
+	start := time.Date(2023, 7, 10, 12, 0, 0, 0, time.UTC)
+
+	xrt := func(val float64, after time.Duration) rate.ExchangeRate {
+		return rate.ExchangeRate{Value: val, Pair: pair, FetchedAt: start.Add(after)}
+	}
+
+	tests := map[string]struct {
+		stored   []rate.ExchangeRate
+		fetched  []rate.ExchangeRate
+		interval time.Duration
+		want     []rate.Candle
+		wantErr  error
+	}{
+		"single_candle_out_of_order": {
+			fetched:  []rate.ExchangeRate{xrt(2, 10*time.Second), xrt(5, 20*time.Second), xrt(1, 5*time.Second), xrt(3, 50*time.Second)},
+			interval: time.Minute,
+			want: []rate.Candle{
+				{Pair: pair, Start: start, Interval: time.Minute, Open: 1, High: 5, Low: 1, Close: 3, Count: 4},
+			},
+		},
+
+		"stored_and_fetched": {
+			stored:   []rate.ExchangeRate{xrt(1, 0), xrt(2, time.Hour)},
+			fetched:  []rate.ExchangeRate{xrt(3, time.Hour+time.Minute)},
+			interval: time.Hour,
+			want: []rate.Candle{
+				{Pair: pair, Start: start, Interval: time.Hour, Open: 1, High: 1, Low: 1, Close: 1, Count: 1},
+				{Pair: pair, Start: start.Add(time.Hour), Interval: time.Hour, Open: 2, High: 3, Low: 2, Close: 3, Count: 2},
+			},
+		},
+
+		"unsupported_interval": {
+			interval: 5 * time.Minute,
+			wantErr:  rate.ErrUnsupportedInterval,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := filestore.NewAppendLog[rate.ExchangeRate](t.TempDir())
+			if tc.stored != nil {
+				require.NoError(t, store.Append(tc.stored...))
+			}
+
+			cndls, err := rate.NewCandles(bus, store, rate.HistoryConfig{}, time.Minute, time.Hour)
+			require.NoError(t, err)
+
+			for i := range tc.fetched {
+				e := event.New(rate.EventSource, rate.EventKindFetched, rate.ProviderResponse{ExchangeRate: &tc.fetched[i]})
+				require.NoError(t, cndls.AggregateExchangeRate(context.Background(), e))
+			}
+
+			got, err := cndls.Range(context.Background(), pair, tc.interval, start, start.Add(24*time.Hour))
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Len(t, got, len(tc.want))
+
+			for i := range tc.want {
+				require.Equal(t, tc.want[i].Start, got[i].Start)
+				require.Equal(t, [4]float64{tc.want[i].Open, tc.want[i].High, tc.want[i].Low, tc.want[i].Close},
+					[4]float64{got[i].Open, got[i].High, got[i].Low, got[i].Close})
+				require.Equal(t, tc.want[i].Count, got[i].Count)
+			}
+		})
+	}
+}
+
+func TestCandlesCompact(t *testing.T) {
+	bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+	pair := rate.NewCurrencyPair("BTC", "UAH")
+	start := time.Date(2023, 7, 10, 12, 0, 0, 0, time.UTC)
+
+	store := filestore.NewAppendLog[rate.ExchangeRate](t.TempDir())
+
+	cndls, err := rate.NewCandles(bus, store, rate.HistoryConfig{Retention: 90 * time.Minute}, time.Hour)
+	require.NoError(t, err)
+
+	for i, after := range []time.Duration{0, time.Hour, 2 * time.Hour} {
+		xrt := rate.ExchangeRate{Value: float64(i + 1), Pair: pair, FetchedAt: start.Add(after)}
+		e := event.New(rate.EventSource, rate.EventKindFetched, rate.ProviderResponse{ExchangeRate: &xrt})
+		require.NoError(t, cndls.AggregateExchangeRate(context.Background(), e))
+	}
+
+	cndls.Compact(start.Add(3 * time.Hour))
+
+	got, err := cndls.Range(context.Background(), pair, time.Hour, start, start.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, start.Add(time.Hour), got[0].Start)
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := map[string]struct {
+		val     string
+		want    time.Duration
+		wantErr error
+	}{
+		"minute":  {val: "1m", want: time.Minute},
+		"hour":    {val: "1h", want: time.Hour},
+		"day":     {val: "1d", want: 24 * time.Hour},
+		"invalid": {val: "1w", wantErr: rate.ErrUnsupportedInterval},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := rate.ParseInterval(tc.val)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
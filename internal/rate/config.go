@@ -1,14 +1,51 @@
 package rate
 
+import "time"
+
+const (
+	ModeChain     = "chain"
+	ModeConsensus = "consensus"
+)
+
 type Config struct {
-	Provider struct {
-		ExchangeRateHost, Ninjas, AlphaVantage, CoinApi, CoinYep ProviderConfig
-	}
+	// Mode defines how providers are aggregated: ModeChain or ModeConsensus.
+	Mode       string
+	Consensus  ConsensusConfig
+	Cache      CacheConfig
+	Support    SupportConfig
+	Breaker    BreakerConfig
+	Retry      RetryConfig
+	Ranking    RankingConfig
+	Validation ValidationConfig
+	Batch      BatchConfig
+	Stream     StreamConfig
+	Poll       PollConfig
+	History    HistoryConfig
+	// Pivots lists the currencies cross rates are computed through, in order of preference.
+	Pivots []string
+	// RepoData is the directory the history of rates is stored in.
+	RepoData string
+	// Providers is the path to the provider definitions file, the built-in definitions are used when empty or "-".
+	Providers string
+	// Order lists the names of the providers to be chained first, overriding their priorities.
+	Order []string
+	// CandleIntervals lists the candle intervals like "1m", "1h" or "1d".
+	CandleIntervals []string
+}
+
+// StreamConfig defines how the exchange rate updates are pushed to the clients.
+type StreamConfig struct {
+	// Heartbeat is the interval the idle stream is kept alive with.
+	Heartbeat time.Duration
+	// Buffer is the number of updates queued for a slow client before they are dropped.
+	Buffer int
 }
 
-type ProviderConfig struct {
-	Name     string
-	Endpoint string
-	Header   string
-	Key      string
+// RetryConfig defines how the failed calls to the providers are retried and hedged.
+type RetryConfig struct {
+	Attempts      int
+	Backoff       time.Duration
+	MaxBackoff    time.Duration
+	RetryOn       []string
+	HedgeQuantile float64
 }
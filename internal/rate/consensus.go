@@ -0,0 +1,306 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+const (
+	StrategyMedian      = "median"
+	StrategyTrimmedMean = "mean"
+	StrategyAgree       = "agree"
+)
+
+var (
+	ErrNoConsensus     = errors.New("no consensus reached")
+	ErrUnknownStrategy = errors.New("unknown consensus strategy")
+)
+
+// ConsensusConfig defines how the ConsensusService aggregates provider quotes.
+type ConsensusConfig struct {
+	// Strategy is one of StrategyMedian, StrategyTrimmedMean or StrategyAgree.
+	Strategy string
+	// Quorum is the minimum number of accepted quotes required to reach consensus.
+	Quorum int
+	// Tolerance is the maximum relative deviation from the median for a quote to be accepted.
+	// Zero disables outlier detection.
+	Tolerance float64
+	// Trim is the fraction of quotes dropped from each end by StrategyTrimmedMean.
+	Trim float64
+}
+
+// Consensus represents the exchange rate agreed by providers.
+type Consensus struct {
+	ExchangeRate *ExchangeRate
+	Contributors []string
+	Outliers     []string
+	Failed       []string
+}
+
+// ConsensusService queries all providers concurrently and aggregates their quotes into a consensus value.
+type ConsensusService struct {
+	bus   *event.Bus
+	cfg   ConsensusConfig
+	provs []ExchangeRateProvider
+}
+
+// quote holds the outcome of a single provider call.
+type quote struct {
+	prov string
+	xrt  *ExchangeRate
+	err  error
+	lat  time.Duration
+}
+
+// NewConsensusService constructs a new ConsensusService instance.
+func NewConsensusService(bus *event.Bus, cfg ConsensusConfig, provs ...ExchangeRateProvider) *ConsensusService {
+	if cfg.Quorum < 1 {
+		cfg.Quorum = 1
+	}
+
+	svc := ConsensusService{
+		bus:   bus,
+		cfg:   cfg,
+		provs: provs,
+	}
+
+	svc.bus.Subscribe(event.New(EventSource, EventKindFetched, nil), svc.LogExchangeRate)
+
+	return &svc
+}
+
+// LogExchangeRate is an event listener designed for log the exchange rate fetching.
+func (svc *ConsensusService) LogExchangeRate(ctx context.Context, e event.Event) error {
+	return logExchangeRate(ctx, e)
+}
+
+// GetExchangeRate returns the consensus exchange rate for a pair of currencies.
+func (svc *ConsensusService) GetExchangeRate(ctx context.Context, pair CurrencyPair) (*ExchangeRate, error) {
+	cons, err := svc.GetConsensus(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	return cons.ExchangeRate, nil
+}
+
+// GetConsensus queries all providers concurrently and aggregates their quotes according to the configured strategy.
+// Every provider outcome is published as a fetched or failed event.
+func (svc *ConsensusService) GetConsensus(ctx context.Context, pair CurrencyPair) (*Consensus, error) {
+	if err := pair.Validate(); err != nil {
+		return nil, err
+	}
+
+	if svc.cfg.Strategy != StrategyMedian && svc.cfg.Strategy != StrategyTrimmedMean && svc.cfg.Strategy != StrategyAgree {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, svc.cfg.Strategy)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	quotes := make(chan quote, len(svc.provs))
+
+	for i := range svc.provs {
+		go func(prov ExchangeRateProvider) {
+			start := time.Now()
+			xrt, err := prov.GetExchangeRate(ctx, pair)
+			quotes <- quote{prov: prov.String(), xrt: xrt, err: err, lat: time.Since(start)}
+		}(svc.provs[i])
+	}
+
+	var (
+		cons        Consensus
+		good        []quote
+		errs        []error
+		exhausted   int
+		unsupported int
+	)
+
+	for range svc.provs {
+		q := <-quotes
+		svc.publish(ctx, pair, q)
+
+		if q.err != nil {
+			cons.Failed = append(cons.Failed, q.prov)
+			errs = append(errs, fmt.Errorf("%s: %w", q.prov, q.err))
+
+			if errors.Is(q.err, ErrQuotaExhausted) {
+				exhausted++
+			}
+
+			if isUnsupported(q.err) {
+				unsupported++
+			}
+
+			continue
+		}
+
+		good = append(good, q)
+
+		if svc.cfg.Strategy == StrategyAgree {
+			if agreed := svc.agree(good); agreed != nil {
+				return svc.consensus(&cons, pair, agreed, good), nil
+			}
+		}
+	}
+
+	if exhausted == len(svc.provs) {
+		errs = append(errs, ErrAllQuotasExhausted)
+	}
+
+	if unsupported == len(svc.provs) {
+		errs = append(errs, ErrAllUnsupported)
+	}
+
+	if svc.cfg.Strategy == StrategyAgree {
+		return nil, fmt.Errorf("%w: %d quotes do not agree: %w",
+			ErrNoConsensus, len(good), errors.Join(append(errs, ErrProviderUnavailable)...))
+	}
+
+	accepted := svc.reject(good)
+	if len(accepted) < svc.cfg.Quorum {
+		return nil, fmt.Errorf("%w: %d of %d quotes accepted: %w",
+			ErrNoConsensus, len(accepted), svc.cfg.Quorum, errors.Join(append(errs, ErrProviderUnavailable)...))
+	}
+
+	return svc.consensus(&cons, pair, accepted, good), nil
+}
+
+// publish publishes the provider outcome on the bus.
+func (svc *ConsensusService) publish(ctx context.Context, pair CurrencyPair, q quote) {
+	e := event.New(EventSource, EventKindFetched, ProviderResponse{Provider: q.prov, ExchangeRate: q.xrt, Latency: q.lat})
+	if q.err != nil {
+		e = event.New(EventSource, EventKindFailed, ProviderErrorResponse{Provider: q.prov, Pair: pair, Err: q.err, Latency: q.lat})
+	}
+
+	_ = svc.bus.Publish(ctx, e)
+}
+
+// consensus fills the Consensus with accepted quotes and treats the rest of successful quotes as outliers.
+func (svc *ConsensusService) consensus(cons *Consensus, pair CurrencyPair, accepted, good []quote) *Consensus {
+	in := make(map[string]bool, len(accepted))
+	for _, q := range accepted {
+		in[q.prov] = true
+		cons.Contributors = append(cons.Contributors, q.prov)
+	}
+
+	for _, q := range good {
+		if !in[q.prov] {
+			cons.Outliers = append(cons.Outliers, q.prov)
+		}
+	}
+
+	val := median(values(accepted))
+	if svc.cfg.Strategy == StrategyTrimmedMean {
+		val = trimmedMean(values(accepted), svc.cfg.Trim)
+	}
+
+	cons.ExchangeRate = NewExchangeRate(val, pair)
+
+	return cons
+}
+
+// reject discards the quotes deviating from the median more than the configured tolerance.
+func (svc *ConsensusService) reject(good []quote) []quote {
+	if svc.cfg.Tolerance <= 0 || len(good) == 0 {
+		return good
+	}
+
+	mid := median(values(good))
+	accepted := make([]quote, 0, len(good))
+
+	for _, q := range good {
+		if deviation(q.xrt.Value, mid) <= svc.cfg.Tolerance {
+			accepted = append(accepted, q)
+		}
+	}
+
+	return accepted
+}
+
+// agree returns the first group of quorum quotes lying within tolerance of each other, or nil if there is none.
+func (svc *ConsensusService) agree(good []quote) []quote {
+	if len(good) < svc.cfg.Quorum {
+		return nil
+	}
+
+	for _, anchor := range good {
+		group := make([]quote, 0, svc.cfg.Quorum)
+
+		for _, q := range good {
+			if deviation(q.xrt.Value, anchor.xrt.Value) <= svc.cfg.Tolerance {
+				group = append(group, q)
+			}
+		}
+
+		if len(group) >= svc.cfg.Quorum {
+			return group
+		}
+	}
+
+	return nil
+}
+
+func values(qs []quote) []float64 {
+	vals := make([]float64, len(qs))
+	for i := range qs {
+		vals[i] = qs[i].xrt.Value
+	}
+
+	return vals
+}
+
+// deviation returns the relative deviation of val from ref.
+func deviation(val, ref float64) float64 {
+	if ref == 0 {
+		return math.Inf(1)
+	}
+
+	return math.Abs(val-ref) / math.Abs(ref)
+}
+
+func median(vals []float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return sorted[mid]
+}
+
+// trimmedMean returns the mean of values after dropping the trim fraction from both ends.
+func trimmedMean(vals []float64, trim float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+
+	n := int(float64(len(sorted)) * trim)
+	if 2*n >= len(sorted) {
+		n = (len(sorted) - 1) / 2
+	}
+
+	sorted = sorted[n : len(sorted)-n]
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+
+	return sum / float64(len(sorted))
+}
@@ -0,0 +1,128 @@
+package rate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsensusServiceGetConsensus(t *testing.T) {
+	log := logger.New(logger.WithConsoleCore(logger.LevelDebug))
+	bus := event.NewBus(log)
+
+	provider := func(name string, val float64, err error) rate.ExchangeRateProvider {
+		return &mock.ExchangeRateProviderMock{
+			GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+				if err != nil {
+					return nil, err
+				}
+
+				return rate.NewExchangeRate(val, pair), nil
+			},
+			StringFunc: func() string { return name },
+		}
+	}
+
+	tests := map[string]struct {
+		cfg              rate.ConsensusConfig
+		provs            []rate.ExchangeRateProvider
+		wantRate         float64
+		wantContributors []string
+		wantOutliers     []string
+		wantFailed       []string
+		wantErr          error
+	}{
+		"median_with_outlier": {
+			cfg: rate.ConsensusConfig{Strategy: rate.StrategyMedian, Quorum: 2, Tolerance: 0.05},
+			provs: []rate.ExchangeRateProvider{
+				provider("A", 100, nil),
+				provider("B", 102, nil),
+				provider("C", 500, nil),
+			},
+			wantRate:         101,
+			wantContributors: []string{"A", "B"},
+			wantOutliers:     []string{"C"},
+		},
+
+		"trimmed_mean": {
+			cfg: rate.ConsensusConfig{Strategy: rate.StrategyTrimmedMean, Quorum: 1, Trim: 0.2},
+			provs: []rate.ExchangeRateProvider{
+				provider("A", 90, nil),
+				provider("B", 100, nil),
+				provider("C", 101, nil),
+				provider("D", 102, nil),
+				provider("E", 200, nil),
+			},
+			wantRate:         101,
+			wantContributors: []string{"A", "B", "C", "D", "E"},
+		},
+
+		"first_agree": {
+			cfg: rate.ConsensusConfig{Strategy: rate.StrategyAgree, Quorum: 2, Tolerance: 0.01},
+			provs: []rate.ExchangeRateProvider{
+				provider("A", 100, nil),
+				provider("B", 100, nil),
+			},
+			wantRate:         100,
+			wantContributors: []string{"A", "B"},
+		},
+
+		"failed_provider": {
+			cfg: rate.ConsensusConfig{Strategy: rate.StrategyMedian, Quorum: 1},
+			provs: []rate.ExchangeRateProvider{
+				provider("A", 100, nil),
+				provider("B", 0, errors.New("mock error")),
+			},
+			wantRate:         100,
+			wantContributors: []string{"A"},
+			wantFailed:       []string{"B"},
+		},
+
+		"quorum_not_reached": {
+			cfg: rate.ConsensusConfig{Strategy: rate.StrategyMedian, Quorum: 2},
+			provs: []rate.ExchangeRateProvider{
+				provider("A", 100, nil),
+				provider("B", 0, errors.New("mock error")),
+			},
+			wantErr: rate.ErrNoConsensus,
+		},
+
+		"no_agreement": {
+			cfg: rate.ConsensusConfig{Strategy: rate.StrategyAgree, Quorum: 2, Tolerance: 0.01},
+			provs: []rate.ExchangeRateProvider{
+				provider("A", 100, nil),
+				provider("B", 200, nil),
+			},
+			wantErr: rate.ErrNoConsensus,
+		},
+
+		"unknown_strategy": {
+			cfg:     rate.ConsensusConfig{Strategy: "mode"},
+			wantErr: rate.ErrUnknownStrategy,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := rate.NewConsensusService(bus, tc.cfg, tc.provs...)
+
+			cons, err := svc.GetConsensus(context.Background(), rate.NewCurrencyPair("BTC", "UAH"))
+			require.ErrorIs(t, err, tc.wantErr)
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			require.InDelta(t, tc.wantRate, cons.ExchangeRate.Value, 1e-9)
+			require.ElementsMatch(t, tc.wantContributors, cons.Contributors)
+			require.ElementsMatch(t, tc.wantOutliers, cons.Outliers)
+			require.ElementsMatch(t, tc.wantFailed, cons.Failed)
+		})
+	}
+}
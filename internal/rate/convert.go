@@ -0,0 +1,164 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
+)
+
+const (
+	RoundHalfUp   Rounding = "half_up"
+	RoundHalfEven Rounding = "half_even"
+	RoundDown     Rounding = "down"
+	RoundUp       Rounding = "up"
+	RoundFloor    Rounding = "floor"
+	RoundCeil     Rounding = "ceil"
+)
+
+// decimal matches the plain decimal notation, so big.Rat does not accept fractions, exponents or other bases.
+var decimal = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+
+var (
+	ErrInvalidAmount   = errors.New("invalid amount")
+	ErrInvalidRounding = errors.New("invalid rounding mode")
+)
+
+// Rounding represents a rounding mode of the converted amount.
+type Rounding string
+
+// Conversion represents the result of converting an amount from the base to the quote currency.
+type Conversion struct {
+	Amount    *big.Rat
+	Result    *big.Rat
+	Precision int
+	Rounding  Rounding
+	Rate      *ExchangeRate
+}
+
+// Converter converts amounts between currencies using exact decimal arithmetic.
+type Converter struct {
+	svc ExchangeRateService
+	reg *currency.Registry
+}
+
+// NewConverter creates a new Converter instance.
+func NewConverter(svc ExchangeRateService, reg *currency.Registry) *Converter {
+	return &Converter{svc: svc, reg: reg}
+}
+
+// Convert converts the amount of the base currency to the quote currency
+// rounding the result to the precision of the quote currency.
+func (c *Converter) Convert(ctx context.Context, pair CurrencyPair, amount *big.Rat, mode Rounding) (*Conversion, error) {
+	if err := mode.Validate(); err != nil {
+		return nil, err
+	}
+
+	xrt, err := c.svc.GetExchangeRate(ctx, pair)
+	if err != nil {
+		return nil, fmt.Errorf("converting amount: %w", err)
+	}
+
+	val, ok := new(big.Rat).SetString(strconv.FormatFloat(xrt.Value, 'f', -1, 64))
+	if !ok {
+		return nil, fmt.Errorf("converting amount: %w: %v", ErrInvalidAmount, xrt.Value)
+	}
+
+	prec := c.Precision(pair.Quote)
+	res := mode.Round(new(big.Rat).Mul(amount, val), prec)
+
+	return &Conversion{
+		Amount:    amount,
+		Result:    res,
+		Precision: prec,
+		Rounding:  mode,
+		Rate:      xrt,
+	}, nil
+}
+
+// ParseAmount parses a positive decimal amount exactly.
+func ParseAmount(val string) (*big.Rat, error) {
+	if !decimal.MatchString(val) {
+		return nil, fmt.Errorf("%w: %q is not a decimal", ErrInvalidAmount, val)
+	}
+
+	amount, ok := new(big.Rat).SetString(val)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, val)
+	}
+
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q is not positive", ErrInvalidAmount, val)
+	}
+
+	return amount, nil
+}
+
+// Precision returns the number of decimal places of the currency known to the currency registry,
+// falling back to two decimal places for unknown ones.
+func (c *Converter) Precision(code string) int {
+	const fiat = 2
+
+	if cur, ok := c.reg.Lookup(code); ok {
+		return cur.Decimals
+	}
+
+	return fiat
+}
+
+// Validate validates a Rounding mode.
+func (r Rounding) Validate() error {
+	switch r {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp, RoundFloor, RoundCeil:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidRounding, r)
+	}
+}
+
+// Round rounds x to prec decimal places.
+//
+//nolint:cyclop
+func (r Rounding) Round(x *big.Rat, prec int) *big.Rat {
+	const base, half = 10, 2
+
+	scale := new(big.Int).Exp(big.NewInt(base), big.NewInt(int64(prec)), nil)
+
+	scaled := new(big.Rat).Mul(x, new(big.Rat).SetInt(scale))
+	quo, rem := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+
+	if rem.Sign() != 0 {
+		// cmp compares the remainder against the half of the denominator.
+		cmp := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(half)).Cmp(scaled.Denom())
+		neg := scaled.Sign() < 0
+
+		var away bool
+
+		switch r {
+		case RoundUp:
+			away = true
+		case RoundDown:
+			away = false
+		case RoundCeil:
+			away = !neg
+		case RoundFloor:
+			away = neg
+		case RoundHalfUp:
+			away = cmp >= 0
+		case RoundHalfEven:
+			away = cmp > 0 || (cmp == 0 && quo.Bit(0) == 1)
+		}
+
+		if away && neg {
+			quo.Sub(quo, big.NewInt(1))
+		} else if away {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	return new(big.Rat).SetFrac(quo, scale)
+}
@@ -0,0 +1,162 @@
+package rate_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundingRound(t *testing.T) {
+	tests := map[string]struct {
+		val  string
+		prec int
+		want map[rate.Rounding]string
+	}{
+		"half": {
+			val:  "2.345",
+			prec: 2,
+			want: map[rate.Rounding]string{
+				rate.RoundHalfUp:   "2.35",
+				rate.RoundHalfEven: "2.34",
+				rate.RoundDown:     "2.34",
+				rate.RoundUp:       "2.35",
+				rate.RoundFloor:    "2.34",
+				rate.RoundCeil:     "2.35",
+			},
+		},
+
+		"negative_half": {
+			val:  "-2.345",
+			prec: 2,
+			want: map[rate.Rounding]string{
+				rate.RoundHalfUp:   "-2.35",
+				rate.RoundHalfEven: "-2.34",
+				rate.RoundDown:     "-2.34",
+				rate.RoundUp:       "-2.35",
+				rate.RoundFloor:    "-2.35",
+				rate.RoundCeil:     "-2.34",
+			},
+		},
+
+		"zero_precision": {
+			val:  "2.5",
+			prec: 0,
+			want: map[rate.Rounding]string{
+				rate.RoundHalfUp:   "3",
+				rate.RoundHalfEven: "2",
+				rate.RoundDown:     "2",
+				rate.RoundUp:       "3",
+			},
+		},
+
+		"exact": {
+			val:  "1.1",
+			prec: 2,
+			want: map[rate.Rounding]string{
+				rate.RoundHalfUp: "1.10",
+				rate.RoundUp:     "1.10",
+				rate.RoundDown:   "1.10",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			x, ok := new(big.Rat).SetString(tc.val)
+			require.True(t, ok)
+
+			for mode, want := range tc.want {
+				require.Equal(t, want, mode.Round(x, tc.prec).FloatString(tc.prec), mode)
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := map[string]struct {
+		val     string
+		wantErr error
+	}{
+		"decimal":  {val: "100.25"},
+		"integer":  {val: "3"},
+		"zero":     {val: "0", wantErr: rate.ErrInvalidAmount},
+		"negative": {val: "-1", wantErr: rate.ErrInvalidAmount},
+		"fraction": {val: "1/3", wantErr: rate.ErrInvalidAmount},
+		"exponent": {val: "1e3", wantErr: rate.ErrInvalidAmount},
+		"hex":      {val: "0x1F", wantErr: rate.ErrInvalidAmount},
+		"binary":   {val: "0b101", wantErr: rate.ErrInvalidAmount},
+		"octal":    {val: "0o17", wantErr: rate.ErrInvalidAmount},
+		"hex_exp":  {val: "0x1p3", wantErr: rate.ErrInvalidAmount},
+		"no_digit": {val: "1.", wantErr: rate.ErrInvalidAmount},
+		"spaces":   {val: " 1", wantErr: rate.ErrInvalidAmount},
+		"garbage":  {val: "abc", wantErr: rate.ErrInvalidAmount},
+		"empty":    {val: "", wantErr: rate.ErrInvalidAmount},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := rate.ParseAmount(tc.val)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestConverterConvert(t *testing.T) {
+	svc := &mock.ExchangeRateServiceMock{
+		GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+			return rate.NewExchangeRate(36.5686, pair), nil
+		},
+	}
+
+	tests := map[string]struct {
+		pair    rate.CurrencyPair
+		amount  string
+		mode    rate.Rounding
+		want    string
+		wantErr error
+	}{
+		"fiat": {
+			pair:   rate.NewCurrencyPair("USD", "UAH"),
+			amount: "10.5",
+			mode:   rate.RoundHalfUp,
+			want:   "383.97",
+		},
+
+		"no_minor_units": {
+			pair:   rate.NewCurrencyPair("USD", "JPY"),
+			amount: "10.5",
+			mode:   rate.RoundDown,
+			want:   "383",
+		},
+
+		"invalid_rounding": {
+			pair:    rate.NewCurrencyPair("USD", "UAH"),
+			amount:  "1",
+			mode:    "sideways",
+			wantErr: rate.ErrInvalidRounding,
+		},
+	}
+
+	reg := currency.NewRegistry(append(currency.ISO4217(), currency.Crypto()...)...)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			amount, err := rate.ParseAmount(tc.amount)
+			require.NoError(t, err)
+
+			conv, err := rate.NewConverter(svc, reg).Convert(context.Background(), tc.pair, amount, tc.mode)
+			require.ErrorIs(t, err, tc.wantErr)
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			require.Equal(t, tc.want, conv.Result.FloatString(conv.Precision))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package curxrt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+)
+
+var ErrMissingQuote = errors.New("quote missing in batch response")
+
+// BatchFetcher retrieves the exchange rates of several quotes against the same base in one call.
+type BatchFetcher func(ctx context.Context, base string, quotes []string) (map[string]*rate.ExchangeRate, error)
+
+// Coalescer merges the concurrent requests for the same base currency into one call.
+// The first request opens a batch the others join during the window, the batch is sent when the window ends or it is full.
+// The call is cancelled only when all requests waiting for it are gone.
+type Coalescer struct {
+	window time.Duration
+	size   int
+	fetch  BatchFetcher
+
+	mu      sync.Mutex
+	pending map[string]*batch
+}
+
+type batch struct {
+	base    string
+	quotes  []string
+	full    chan struct{}
+	done    chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiting int
+	// foreground is set once a request that is not a background one joins the batch.
+	foreground bool
+	rates      map[string]*rate.ExchangeRate
+	err        error
+}
+
+// NewCoalescer creates a new Coalescer instance, size of zero means the batch size is not limited.
+func NewCoalescer(window time.Duration, size int, fetch BatchFetcher) *Coalescer {
+	return &Coalescer{
+		window:  window,
+		size:    size,
+		fetch:   fetch,
+		pending: make(map[string]*batch),
+	}
+}
+
+// Get retrieves the exchange rate for the pair within a batch of the requests for the same base.
+func (c *Coalescer) Get(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+	b := c.join(ctx, pair)
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		c.leave(b)
+		return nil, ctx.Err()
+	}
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	xrt, ok := b.rates[pair.Quote]
+	if !ok {
+		return nil, fmt.Errorf("%w: %w: %s/%s", rate.ErrUnknownSymbol, ErrMissingQuote, pair.Base, pair.Quote)
+	}
+
+	// Requests for the same pair share the batch, so each of them gets its own copy.
+	cp := *xrt
+
+	return &cp, nil
+}
+
+// join adds the quote to the pending batch of the base, opening a new one when there is none or it is full.
+func (c *Coalescer) join(ctx context.Context, pair rate.CurrencyPair) *batch {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The batch cancelled by its requests is never joined, it is about to be detached.
+	b, ok := c.pending[pair.Base]
+	if !ok || b.ctx.Err() != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		b = &batch{base: pair.Base, full: make(chan struct{}), done: make(chan struct{}), ctx: ctx, cancel: cancel}
+		c.pending[pair.Base] = b
+
+		go c.run(b)
+	}
+
+	b.waiting++
+	b.foreground = b.foreground || !rate.IsBackground(ctx)
+
+	if !contains(b.quotes, pair.Quote) {
+		b.quotes = append(b.quotes, pair.Quote)
+	}
+
+	if c.size > 0 && len(b.quotes) >= c.size {
+		delete(c.pending, pair.Base)
+		close(b.full)
+	}
+
+	return b
+}
+
+// leave gives up waiting for the batch, cancelling its call if nobody else waits.
+func (c *Coalescer) leave(b *batch) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if b.waiting--; b.waiting == 0 {
+		b.cancel()
+	}
+}
+
+// run sends the batch when the window ends or it is full.
+func (c *Coalescer) run(b *batch) {
+	defer b.cancel()
+
+	timer := time.NewTimer(c.window)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		c.mu.Lock()
+		if c.pending[b.base] == b {
+			delete(c.pending, b.base)
+		}
+		c.mu.Unlock()
+
+	case <-b.full:
+	case <-b.ctx.Done():
+		c.mu.Lock()
+		if c.pending[b.base] == b {
+			delete(c.pending, b.base)
+		}
+		c.mu.Unlock()
+	}
+
+	// The batch is detached from pending, so the quotes are not changed anymore.
+	// It is a background call only if every request of it is, so the quota reserve is kept for the others.
+	ctx := b.ctx
+	if !b.foreground {
+		ctx = rate.WithBackground(ctx)
+	}
+
+	b.rates, b.err = c.fetch(ctx, b.base, b.quotes)
+
+	close(b.done)
+}
+
+func contains(list []string, val string) bool {
+	for i := range list {
+		if list[i] == val {
+			return true
+		}
+	}
+
+	return false
+}
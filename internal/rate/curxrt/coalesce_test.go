@@ -0,0 +1,169 @@
+package curxrt_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate/curxrt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalescerGet(t *testing.T) {
+	tests := map[string]struct {
+		size      int
+		pairs     []rate.CurrencyPair
+		wantCalls int32
+		wantErrs  []error
+	}{
+		"same_base_merged": {
+			pairs: []rate.CurrencyPair{
+				rate.NewCurrencyPair("USD", "UAH"), rate.NewCurrencyPair("USD", "EUR"), rate.NewCurrencyPair("USD", "UAH"),
+			},
+			wantCalls: 1,
+			wantErrs:  []error{nil, nil, nil},
+		},
+		"bases_apart": {
+			pairs:     []rate.CurrencyPair{rate.NewCurrencyPair("USD", "UAH"), rate.NewCurrencyPair("EUR", "UAH")},
+			wantCalls: 2,
+			wantErrs:  []error{nil, nil},
+		},
+		"full_batch_split": {
+			size: 2,
+			pairs: []rate.CurrencyPair{
+				rate.NewCurrencyPair("USD", "UAH"), rate.NewCurrencyPair("USD", "EUR"), rate.NewCurrencyPair("USD", "GBP"),
+			},
+			wantCalls: 2,
+			wantErrs:  []error{nil, nil, nil},
+		},
+		"missing_quote": {
+			pairs:     []rate.CurrencyPair{rate.NewCurrencyPair("USD", "UAH"), rate.NewCurrencyPair("USD", "XXX")},
+			wantCalls: 1,
+			wantErrs:  []error{nil, curxrt.ErrMissingQuote},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int32
+
+			fetch := func(_ context.Context, base string, quotes []string) (map[string]*rate.ExchangeRate, error) {
+				atomic.AddInt32(&calls, 1)
+
+				rates := make(map[string]*rate.ExchangeRate)
+				for _, quote := range quotes {
+					if quote != "XXX" {
+						rates[quote] = &rate.ExchangeRate{Value: 1, Pair: rate.NewCurrencyPair(base, quote)}
+					}
+				}
+
+				return rates, nil
+			}
+
+			c := curxrt.NewCoalescer(50*time.Millisecond, tc.size, fetch)
+
+			var wg sync.WaitGroup
+
+			xrts := make([]*rate.ExchangeRate, len(tc.pairs))
+			errs := make([]error, len(tc.pairs))
+
+			for i := range tc.pairs {
+				wg.Add(1)
+
+				go func(i int) {
+					defer wg.Done()
+					xrts[i], errs[i] = c.Get(context.Background(), tc.pairs[i])
+				}(i)
+			}
+
+			wg.Wait()
+
+			require.Equal(t, tc.wantCalls, atomic.LoadInt32(&calls))
+
+			for i := range errs {
+				require.ErrorIs(t, errs[i], tc.wantErrs[i])
+
+				if tc.wantErrs[i] == nil {
+					require.Equal(t, tc.pairs[i], xrts[i].Pair)
+				}
+			}
+		})
+	}
+}
+
+func TestCoalescerCancel(t *testing.T) {
+	var cancelled atomic.Bool
+
+	fetch := func(ctx context.Context, _ string, _ []string) (map[string]*rate.ExchangeRate, error) {
+		<-ctx.Done()
+		cancelled.Store(true)
+
+		return nil, ctx.Err()
+	}
+
+	c := curxrt.NewCoalescer(time.Millisecond, 0, fetch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Get(ctx, rate.NewCurrencyPair("USD", "UAH"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Eventually(t, cancelled.Load, time.Second, time.Millisecond)
+}
+
+func TestCoalescerBackground(t *testing.T) {
+	tests := map[string]struct {
+		ctxs   []context.Context
+		wantBg bool
+	}{
+		"background_only": {
+			ctxs:   []context.Context{rate.WithBackground(context.Background()), rate.WithBackground(context.Background())},
+			wantBg: true,
+		},
+		"joined_by_foreground": {
+			ctxs:   []context.Context{rate.WithBackground(context.Background()), context.Background()},
+			wantBg: false,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			bg := make(chan bool, 1)
+
+			fetch := func(ctx context.Context, base string, quotes []string) (map[string]*rate.ExchangeRate, error) {
+				bg <- rate.IsBackground(ctx)
+
+				xrts := make(map[string]*rate.ExchangeRate, len(quotes))
+				for _, quote := range quotes {
+					xrts[quote] = rate.NewExchangeRate(1.2, rate.NewCurrencyPair(base, quote))
+				}
+
+				return xrts, nil
+			}
+
+			c := curxrt.NewCoalescer(50*time.Millisecond, 0, fetch)
+
+			var wg sync.WaitGroup
+
+			wg.Add(len(tc.ctxs))
+
+			for _, ctx := range tc.ctxs {
+				go func(ctx context.Context) {
+					defer wg.Done()
+
+					_, err := c.Get(ctx, rate.NewCurrencyPair("USD", "UAH"))
+					require.NoError(t, err)
+				}(ctx)
+			}
+
+			wg.Wait()
+
+			require.Equal(t, tc.wantBg, <-bg)
+		})
+	}
+}
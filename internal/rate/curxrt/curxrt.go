@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
@@ -18,6 +19,11 @@ type Config struct {
 	Endpoint string
 	Header   string
 	Key      string
+	// PerMinute and Daily limit the number of calls to the provider, zero means no limit.
+	PerMinute int
+	Daily     int
+	// Timeout limits a single call to the provider including retries, zero means no limit.
+	Timeout time.Duration
 }
 
 // HTTPClient is an interface for making HTTP requests.
@@ -30,9 +36,21 @@ type RequestBuilder interface {
 	BuildRequest(context.Context, rate.CurrencyPair, Config) (*http.Request, error)
 }
 
+// Limiter is an interface for enforcing the call limits of the provider.
+type Limiter interface {
+	Allow(context.Context, Config) error
+}
+
 // ResponseProcessor is an interface for processing HTTP responses from the exchange rate provider.
+// It fills the ExchangeRate with the value and whatever quote details the provider returns.
 type ResponseProcessor interface {
-	ProcessResponse(*http.Response) (float64, error)
+	ProcessResponse(*http.Response) (*rate.ExchangeRate, error)
+}
+
+// BatchRequestResponder is designed to build and process HTTP requests quoting several currencies against the same base.
+type BatchRequestResponder interface {
+	BuildBatchRequest(ctx context.Context, base string, quotes []string, cfg Config) (*http.Request, error)
+	ProcessBatchResponse(resp *http.Response, base string, quotes []string) (map[string]*rate.ExchangeRate, error)
 }
 
 // RequestResponder designed to the build and process HTTP requests of a specific provider.
@@ -47,24 +65,63 @@ type Provider[T any] struct {
 	Config
 	HTTPClient
 	RequestResponder
+	Limiter
+	*Retrier
+	*Coalescer
 }
 
 // NewProvider returns a new instance of specific Provider with injected dependencies implemented by RequestResponder.
-func NewProvider[T RequestResponder](cfg Config, clt HTTPClient) Provider[T] {
-	return Provider[T]{Config: cfg, HTTPClient: clt, RequestResponder: *new(T)}
+// Limiter and Retrier are optional and may be nil.
+func NewProvider[T RequestResponder](cfg Config, clt HTTPClient, lim Limiter, rtr *Retrier) Provider[T] {
+	return Provider[T]{Config: cfg, HTTPClient: clt, RequestResponder: *new(T), Limiter: lim, Retrier: rtr}
 }
 
 // String returns the name of the provider for log.
 func (p Provider[T]) String() string { return p.Name }
 
 // GetExchangeRate retrieves the exchange rate for the specified currency pair.
+// It fails with rate.ErrQuotaExhausted without calling out when the provider limits are reached.
+// Failed calls are retried according to the Retrier policy, every attempt counting against the limits.
+// Concurrent calls are merged by the Coalescer when it is set.
 func (p Provider[T]) GetExchangeRate(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
-	req, err := p.BuildRequest(ctx, pair, p.Config)
+	if p.Coalescer != nil {
+		return p.Coalescer.Get(ctx, pair)
+	}
+
+	ctx, cancel := p.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := p.call(ctx, func(ctx context.Context) (*http.Request, error) {
+		return p.BuildRequest(ctx, pair, p.Config)
+	})
+
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	xrt, err := p.ProcessResponse(resp)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := p.Do(req)
+	return p.stamp(xrt, pair), nil
+}
+
+// getBatch retrieves the exchange rates of several quotes against the same base in one call.
+func (p Provider[T]) getBatch(
+	ctx context.Context, brr BatchRequestResponder, base string, quotes []string,
+) (map[string]*rate.ExchangeRate, error) {
+	ctx, cancel := p.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := p.call(ctx, func(ctx context.Context) (*http.Request, error) {
+		return brr.BuildBatchRequest(ctx, base, quotes, p.Config)
+	})
+
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -73,12 +130,68 @@ func (p Provider[T]) GetExchangeRate(ctx context.Context, pair rate.CurrencyPair
 		return nil, err
 	}
 
-	val, err := p.ProcessResponse(resp)
+	rates, err := brr.ProcessBatchResponse(resp, base, quotes)
 	if err != nil {
 		return nil, err
 	}
 
-	return rate.NewExchangeRate(val, pair), nil
+	for quote, xrt := range rates {
+		p.stamp(xrt, rate.CurrencyPair{Base: base, Quote: quote})
+	}
+
+	return rates, nil
+}
+
+// withTimeout limits the call with the configured timeout, if any.
+func (p Provider[T]) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.Timeout > 0 {
+		return context.WithTimeout(ctx, p.Timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// call makes the request enforcing the limits and retrying according to the Retrier policy.
+func (p Provider[T]) call(ctx context.Context, build func(context.Context) (*http.Request, error)) (*http.Response, error) {
+	var allow func(context.Context) error
+
+	if p.Limiter != nil {
+		allow = func(ctx context.Context) error { return p.Allow(ctx, p.Config) }
+	}
+
+	attempt := func(ctx context.Context) (*http.Response, error) {
+		req, err := build(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return p.HTTPClient.Do(req)
+	}
+
+	if p.Retrier != nil {
+		return p.Retrier.Do(ctx, p.Name, allow, attempt)
+	}
+
+	if allow != nil {
+		if err := allow(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return attempt(ctx)
+}
+
+// stamp fills the quote with the details known to the provider.
+func (p Provider[T]) stamp(xrt *rate.ExchangeRate, pair rate.CurrencyPair) *rate.ExchangeRate {
+	// The pair is kept when the provider reports the quoted symbols, so a mismatch can be detected.
+	if xrt.Pair == (rate.CurrencyPair{}) {
+		xrt.Pair = pair
+	}
+
+	xrt.Provider = p.Name
+	xrt.FetchedAt = time.Now()
+
+	return xrt
 }
 
 // newRequest creates a new HTTP request with the specified context, endpoint, and request options.
@@ -0,0 +1,470 @@
+package curxrt
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+)
+
+// Authentication key placements.
+const (
+	AuthHeader = "header"
+	AuthQuery  = "query"
+)
+
+// TimestampUnix is the timestamp format of seconds since the Unix epoch.
+const TimestampUnix = "unix"
+
+var ErrInvalidDefinition = errors.New("invalid provider definition")
+
+var (
+	//go:embed providers.json
+	FS embed.FS
+)
+
+// Definition declares an exchange rate provider, so it can be added without writing Go code.
+// Endpoint, query and header values may contain {base} and {quote} placeholders replaced with the currency pair,
+// and the extraction paths may contain them as well.
+type Definition struct {
+	Name     string            `json:"name"`
+	Endpoint string            `json:"endpoint"`
+	Query    map[string]string `json:"query,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Auth     Auth              `json:"auth,omitempty"`
+	Extract  Extract           `json:"extract"`
+	// PerMinute and Daily limit the number of calls to the provider, zero means no limit.
+	PerMinute int `json:"per_minute,omitempty"`
+	Daily     int `json:"daily,omitempty"`
+	// Enabled is true when omitted.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Priority orders the providers in the chain, lower first, ties keep the order of definitions.
+	Priority int `json:"priority,omitempty"`
+	// Timeout limits a single call to the provider including retries, like "5s".
+	Timeout Duration `json:"timeout,omitempty"`
+	// Batch is set when the provider quotes several currencies against the same base in one call.
+	Batch *Batch `json:"batch,omitempty"`
+}
+
+// Batch declares how the concurrent requests for the same base are merged into one call.
+type Batch struct {
+	// Separator joins the quotes substituted for {quote}, "," by default.
+	Separator string `json:"separator,omitempty"`
+	// Max is the largest number of quotes in one call, zero means no limit.
+	Max int `json:"max,omitempty"`
+	// Window is how long the call waits for other requests to join it, like "10ms".
+	Window Duration `json:"window,omitempty"`
+}
+
+func (b *Batch) separator() string {
+	if b == nil || b.Separator == "" {
+		return ","
+	}
+
+	return b.Separator
+}
+
+// Duration is a time.Duration given in JSON as a string like "1.5s".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var val string
+	if err := json.Unmarshal(data, &val); err != nil {
+		return fmt.Errorf("decoding duration: %w", err)
+	}
+
+	dur, err := time.ParseDuration(val)
+	if err != nil {
+		return fmt.Errorf("parsing duration: %w", err)
+	}
+
+	*d = Duration(dur)
+
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Auth declares where the API key is placed in the request.
+type Auth struct {
+	// In is either AuthHeader or AuthQuery, empty when the provider needs no key.
+	In   string `json:"in,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Key is the API key, environment variables like ${RATE_PROVIDER_NINJAS_KEY} are expanded.
+	Key string `json:"key,omitempty"`
+}
+
+// Extract declares JSON paths the quote is extracted from the response with.
+// Numbers given as strings are parsed.
+type Extract struct {
+	Rate string `json:"rate"`
+	Bid  string `json:"bid,omitempty"`
+	Ask  string `json:"ask,omitempty"`
+	// Timestamp is parsed with TimestampFormat being a Go time layout or TimestampUnix, time.RFC3339 by default.
+	Timestamp       string `json:"timestamp,omitempty"`
+	TimestampFormat string `json:"timestamp_format,omitempty"`
+	// TimeZone is the path to the IANA time zone name the timestamp is given in, UTC by default.
+	TimeZone string `json:"time_zone,omitempty"`
+	// Base and Quote are the paths to the currency codes the provider has quoted,
+	// so the quote for a wrong pair is detected, the requested pair is assumed when empty.
+	Base  string `json:"base,omitempty"`
+	Quote string `json:"quote,omitempty"`
+}
+
+// pairKey is the context key the currency pair is passed from the request to the response processing with.
+type pairKey struct{}
+
+// LoadDefinitions loads the provider definitions from the JSON file,
+// falling back to the embedded definitions of the built-in providers when the path is empty or "-".
+func LoadDefinitions(pth string) ([]Definition, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if pth == "" || pth == "-" {
+		data, err = FS.ReadFile("providers.json")
+	} else {
+		data, err = os.ReadFile(pth)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading provider definitions: %w", err)
+	}
+
+	return ParseDefinitions(data)
+}
+
+// ParseDefinitions parses and validates the provider definitions.
+func ParseDefinitions(data []byte) ([]Definition, error) {
+	var defs []Definition
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&defs); err != nil {
+		return nil, fmt.Errorf("decoding provider definitions: %w", err)
+	}
+
+	for i := range defs {
+		defs[i].Auth.Key = os.ExpandEnv(defs[i].Auth.Key)
+
+		if err := defs[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return defs, nil
+}
+
+// Validate validates the Definition.
+func (d Definition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("%w: missing name", ErrInvalidDefinition)
+	}
+
+	pair := rate.NewCurrencyPair("BTC", "USD")
+
+	if _, err := url.ParseRequestURI(expand(d.Endpoint, pair)); err != nil {
+		return fmt.Errorf("%w: %s: invalid endpoint %q", ErrInvalidDefinition, d.Name, d.Endpoint)
+	}
+
+	switch d.Auth.In {
+	case "":
+	case AuthHeader, AuthQuery:
+		if d.Auth.Name == "" {
+			return fmt.Errorf("%w: %s: missing auth name", ErrInvalidDefinition, d.Name)
+		}
+	default:
+		return fmt.Errorf("%w: %s: unknown auth placement %q", ErrInvalidDefinition, d.Name, d.Auth.In)
+	}
+
+	// The joined quotes are substituted into the query only, so the batch can not be requested by path.
+	if d.Batch != nil && strings.Contains(d.Endpoint, "{quote}") {
+		return fmt.Errorf("%w: %s: batch with {quote} in endpoint", ErrInvalidDefinition, d.Name)
+	}
+
+	if d.Extract.Rate == "" {
+		return fmt.Errorf("%w: %s: missing rate path", ErrInvalidDefinition, d.Name)
+	}
+
+	paths := []string{d.Extract.Rate, d.Extract.Bid, d.Extract.Ask, d.Extract.Timestamp, d.Extract.TimeZone, d.Extract.Base, d.Extract.Quote}
+
+	for _, expr := range paths {
+		if expr == "" {
+			continue
+		}
+
+		if _, err := ParsePath(expand(expr, pair)); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidDefinition, d.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Config returns the Config of the defined provider.
+func (d Definition) Config() Config {
+	return Config{
+		Name:      d.Name,
+		Endpoint:  d.Endpoint,
+		Header:    d.Auth.Name,
+		Key:       d.Auth.Key,
+		PerMinute: d.PerMinute,
+		Daily:     d.Daily,
+		Timeout:   time.Duration(d.Timeout),
+	}
+}
+
+// IsEnabled reports whether the provider is enabled.
+func (d Definition) IsEnabled() bool {
+	return d.Enabled == nil || *d.Enabled
+}
+
+// HasCredentials reports whether the provider has the API key it needs.
+func (d Definition) HasCredentials() bool {
+	return d.Auth.In == "" || d.Auth.Key != ""
+}
+
+// Arrange returns the definitions in the order the providers are chained in.
+// The named providers go first in the given order, the rest are ordered by priority.
+func Arrange(defs []Definition, order ...string) []Definition {
+	rank := make(map[string]int, len(order))
+	for i := range order {
+		rank[order[i]] = i
+	}
+
+	list := append([]Definition(nil), defs...)
+
+	sort.SliceStable(list, func(i, j int) bool {
+		ri, ni := rank[list[i].Name]
+		rj, nj := rank[list[j].Name]
+
+		switch {
+		case ni && nj:
+			return ri < rj
+		case ni != nj:
+			return ni
+		default:
+			return list[i].Priority < list[j].Priority
+		}
+	})
+
+	return list
+}
+
+// NewDefinedProvider returns a new instance of Provider driven by the Definition.
+// Limiter and Retrier are optional and may be nil.
+// Concurrent requests for the same base are merged into one call when the Definition declares Batch.
+func NewDefinedProvider(def Definition, clt HTTPClient, lim Limiter, rtr *Retrier) Provider[Definition] {
+	prov := Provider[Definition]{Config: def.Config(), HTTPClient: clt, RequestResponder: def, Limiter: lim, Retrier: rtr}
+
+	if def.Batch != nil {
+		fetch := func(ctx context.Context, base string, quotes []string) (map[string]*rate.ExchangeRate, error) {
+			return prov.getBatch(ctx, def, base, quotes)
+		}
+
+		prov.Coalescer = NewCoalescer(time.Duration(def.Batch.Window), def.Batch.Max, fetch)
+	}
+
+	return prov
+}
+
+// BuildRequest builds the request from the templates of the Definition.
+func (d Definition) BuildRequest(ctx context.Context, pair rate.CurrencyPair, cfg Config) (*http.Request, error) {
+	opts := make([]web.RequestOption, 0, len(d.Query)+len(d.Headers)+1)
+
+	for key, val := range d.Query {
+		opts = append(opts, web.WithValue(key, expand(val, pair)))
+	}
+
+	for key, val := range d.Headers {
+		opts = append(opts, web.WithHeader(key, expand(val, pair)))
+	}
+
+	switch d.Auth.In {
+	case AuthHeader:
+		opts = append(opts, web.WithHeader(cfg.Header, cfg.Key))
+	case AuthQuery:
+		opts = append(opts, web.WithValue(cfg.Header, cfg.Key))
+	}
+
+	ctx = context.WithValue(ctx, pairKey{}, pair)
+
+	return newRequest(ctx, expand(cfg.Endpoint, pair), opts...)
+}
+
+// ProcessResponse extracts the quote from the response with the paths of the Definition.
+func (d Definition) ProcessResponse(resp *http.Response) (*rate.ExchangeRate, error) {
+	doc, err := decode(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var pair rate.CurrencyPair
+	if resp.Request != nil {
+		pair, _ = resp.Request.Context().Value(pairKey{}).(rate.CurrencyPair)
+	}
+
+	return d.extract(doc, pair)
+}
+
+// BuildBatchRequest builds the request for several quotes of the same base,
+// the quotes joined with the batch separator are substituted for {quote}.
+func (d Definition) BuildBatchRequest(ctx context.Context, base string, quotes []string, cfg Config) (*http.Request, error) {
+	return d.BuildRequest(ctx, rate.CurrencyPair{Base: base, Quote: strings.Join(quotes, d.Batch.separator())}, cfg)
+}
+
+// ProcessBatchResponse extracts the quotes from the response to the batch request by their codes.
+// The quotes the provider does not know are left out, any other failure fails the whole batch.
+func (d Definition) ProcessBatchResponse(resp *http.Response, base string, quotes []string) (map[string]*rate.ExchangeRate, error) {
+	doc, err := decode(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]*rate.ExchangeRate, len(quotes))
+
+	for _, quote := range quotes {
+		xrt, err := d.extract(doc, rate.CurrencyPair{Base: base, Quote: quote})
+
+		switch {
+		case errors.Is(err, rate.ErrUnknownSymbol):
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		rates[quote] = xrt
+	}
+
+	return rates, nil
+}
+
+// decode decodes the JSON response keeping the numbers as they are.
+func decode(resp *http.Response) (any, error) {
+	var doc any
+
+	if err := web.ErrFromStatusCode(resp.StatusCode); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return doc, nil
+}
+
+// extract extracts the quote for the pair from the decoded response.
+func (d Definition) extract(doc any, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+	path := func(expr string) Path {
+		p, _ := ParsePath(expand(expr, pair)) // Paths are validated on load.
+		return p
+	}
+
+	val, err := path(d.Extract.Rate).Float(doc)
+	if err != nil && path(d.Extract.Rate).Missing(doc) {
+		// The response is well-formed but does not quote the pair, unlike an error body of another shape.
+		return nil, fmt.Errorf("parsing rate: %w: %w", rate.ErrUnknownSymbol, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("parsing rate: %w", err)
+	}
+
+	xrt := rate.ExchangeRate{Value: val, Pair: pair}
+
+	// A missing symbol is reported as empty code, so it never matches the requested one.
+	if d.Extract.Base != "" {
+		xrt.Pair.Base = d.symbol(doc, path(d.Extract.Base))
+	}
+
+	if d.Extract.Quote != "" {
+		xrt.Pair.Quote = d.symbol(doc, path(d.Extract.Quote))
+	}
+
+	// Quote details are optional, so they are filled only when parsed successfully.
+	if d.Extract.Bid != "" {
+		xrt.Bid, _ = path(d.Extract.Bid).Float(doc)
+	}
+
+	if d.Extract.Ask != "" {
+		xrt.Ask, _ = path(d.Extract.Ask).Float(doc)
+	}
+
+	if d.Extract.Timestamp != "" {
+		xrt.Timestamp = d.timestamp(doc, path)
+	}
+
+	return &xrt, nil
+}
+
+// symbol extracts the currency code, empty if it can not be parsed.
+func (d Definition) symbol(doc any, p Path) string {
+	code, err := p.String(doc)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// timestamp extracts the quote time, zero if it can not be parsed.
+func (d Definition) timestamp(doc any, path func(string) Path) time.Time {
+	if d.Extract.TimestampFormat == TimestampUnix {
+		sec, err := path(d.Extract.Timestamp).Float(doc)
+		if err != nil {
+			return time.Time{}
+		}
+
+		return time.Unix(int64(sec), 0).UTC()
+	}
+
+	val, err := path(d.Extract.Timestamp).String(doc)
+	if err != nil {
+		return time.Time{}
+	}
+
+	layout := d.Extract.TimestampFormat
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	loc := time.UTC
+
+	if d.Extract.TimeZone != "" {
+		if name, err := path(d.Extract.TimeZone).String(doc); err == nil {
+			if l, err := time.LoadLocation(name); err == nil {
+				loc = l
+			}
+		}
+	}
+
+	ts, _ := time.ParseInLocation(layout, val, loc)
+
+	return ts
+}
+
+// expand replaces the currency pair placeholders.
+func expand(tmpl string, pair rate.CurrencyPair) string {
+	return strings.NewReplacer("{base}", pair.Base, "{quote}", pair.Quote).Replace(tmpl)
+}
+
+var _ RequestResponder = Definition{}
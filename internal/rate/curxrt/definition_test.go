@@ -0,0 +1,303 @@
+package curxrt_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate/curxrt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinedProviderGetExchangeRate(t *testing.T) {
+	t.Setenv("RATE_PROVIDER_ALPHA_VANTAGE_KEY", "alpha_key")
+	t.Setenv("RATE_PROVIDER_COIN_API_KEY", "coin_key")
+	t.Setenv("RATE_PROVIDER_NINJAS_KEY", "ninjas_key")
+
+	defs, err := curxrt.LoadDefinitions("")
+	require.NoError(t, err)
+
+	pair := rate.NewCurrencyPair("USD", "UAH")
+
+	tests := map[string]struct {
+		body     string
+		wantReq  func(t *testing.T, req *http.Request)
+		wantRate rate.ExchangeRate
+	}{
+		"ExchangeRateHost": {
+			body: `{"success":true,"base":"USD","date":"2023-07-01","rates":{"UAH":36.9}}`,
+			wantReq: func(t *testing.T, req *http.Request) {
+				require.Equal(t, "USD", req.URL.Query().Get("base"))
+				require.Equal(t, "UAH", req.URL.Query().Get("symbols"))
+			},
+			wantRate: rate.ExchangeRate{Value: 36.9, Timestamp: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)},
+		},
+
+		"AlphaVantage": {
+			body: `{"Realtime Currency Exchange Rate":{"1. From_Currency Code":"USD","3. To_Currency Code":"UAH",` +
+				`"5. Exchange Rate":"36.90","6. Last Refreshed":"2023-07-01 10:00:01",` +
+				`"7. Time Zone":"UTC","8. Bid Price":"36.89","9. Ask Price":"36.91"}}`,
+			wantReq: func(t *testing.T, req *http.Request) {
+				require.Equal(t, "CURRENCY_EXCHANGE_RATE", req.URL.Query().Get("function"))
+				require.Equal(t, "USD", req.URL.Query().Get("from_currency"))
+				require.Equal(t, "UAH", req.URL.Query().Get("to_currency"))
+				require.Equal(t, "alpha_key", req.URL.Query().Get("apikey"))
+			},
+			wantRate: rate.ExchangeRate{Value: 36.9, Bid: 36.89, Ask: 36.91, Timestamp: time.Date(2023, 7, 1, 10, 0, 1, 0, time.UTC)},
+		},
+
+		"CoinYep": {
+			body: `{"base_symbol":"USD","target_symbol":"UAH","price":"36.9","price_change":0.1}`,
+			wantReq: func(t *testing.T, req *http.Request) {
+				require.Equal(t, "USD", req.URL.Query().Get("from"))
+				require.Equal(t, "UAH", req.URL.Query().Get("to"))
+			},
+			wantRate: rate.ExchangeRate{Value: 36.9},
+		},
+
+		"CoinApi": {
+			body: `{"time":"2023-07-01T10:00:01.0000000Z","asset_id_base":"USD","asset_id_quote":"UAH","rate":36.9}`,
+			wantReq: func(t *testing.T, req *http.Request) {
+				require.Equal(t, "/v1/exchangerate/USD/UAH", req.URL.Path)
+				require.Equal(t, "coin_key", req.Header.Get("X-CoinAPI-Key"))
+			},
+			wantRate: rate.ExchangeRate{Value: 36.9, Timestamp: time.Date(2023, 7, 1, 10, 0, 1, 0, time.UTC)},
+		},
+
+		"Ninjas": {
+			body: `{"currency_pair":"USD_UAH","exchange_rate":36.9}`,
+			wantReq: func(t *testing.T, req *http.Request) {
+				require.Equal(t, "USD_UAH", req.URL.Query().Get("pair"))
+				require.Equal(t, "ninjas_key", req.Header.Get("X-Api-Key"))
+			},
+			wantRate: rate.ExchangeRate{Value: 36.9},
+		},
+	}
+
+	require.Len(t, defs, len(tests))
+
+	for _, def := range defs {
+		def := def
+		tc, ok := tests[def.Name]
+		require.True(t, ok, def.Name)
+
+		t.Run(def.Name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				tc.wantReq(t, req)
+				_, _ = rw.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			// The endpoint is redirected to the test server keeping its path.
+			u, err := url.Parse(def.Endpoint)
+			require.NoError(t, err)
+
+			def.Endpoint = srv.URL + u.Path
+
+			prov := curxrt.NewDefinedProvider(def, srv.Client(), nil, nil)
+
+			xrt, err := prov.GetExchangeRate(context.Background(), pair)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantRate.Value, xrt.Value)
+			require.Equal(t, tc.wantRate.Bid, xrt.Bid)
+			require.Equal(t, tc.wantRate.Ask, xrt.Ask)
+			require.True(t, tc.wantRate.Timestamp.Equal(xrt.Timestamp), xrt.Timestamp)
+			require.Equal(t, pair, xrt.Pair)
+			require.Equal(t, def.Name, xrt.Provider)
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	doc := map[string]any{
+		"rates":   map[string]any{"UAH": 36.9},
+		"5. Rate": "36.9",
+		"data":    []any{map[string]any{"price": "1.5"}},
+	}
+
+	tests := map[string]struct {
+		expr    string
+		want    float64
+		wantErr error
+	}{
+		"dot":            {expr: "$.rates.UAH", want: 36.9},
+		"quoted":         {expr: "$['5. Rate']", want: 36.9},
+		"double_quoted":  {expr: `$["5. Rate"]`, want: 36.9},
+		"index":          {expr: "$.data[0].price", want: 1.5},
+		"missing_key":    {expr: "$.rates.EUR", wantErr: curxrt.ErrInvalidPath},
+		"out_of_range":   {expr: "$.data[1].price", wantErr: curxrt.ErrInvalidPath},
+		"missing_root":   {expr: "rates.UAH", wantErr: curxrt.ErrInvalidPath},
+		"unclosed":       {expr: "$['5. Rate'", wantErr: curxrt.ErrInvalidPath},
+		"empty_key":      {expr: "$..rates", wantErr: curxrt.ErrInvalidPath},
+		"not_a_number":   {expr: "$.rates", wantErr: curxrt.ErrInvalidPath},
+		"negative_index": {expr: "$.data[-1]", wantErr: curxrt.ErrInvalidPath},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path, err := curxrt.ParsePath(tc.expr)
+			if err == nil {
+				var got float64
+
+				got, err = path.Float(doc)
+				if tc.wantErr == nil {
+					require.Equal(t, tc.want, got)
+				}
+			}
+
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestArrange(t *testing.T) {
+	defs := []curxrt.Definition{
+		{Name: "A", Priority: 2},
+		{Name: "B", Priority: 1},
+		{Name: "C", Priority: 1},
+		{Name: "D"},
+	}
+
+	names := func(defs []curxrt.Definition) []string {
+		list := make([]string, len(defs))
+		for i := range defs {
+			list[i] = defs[i].Name
+		}
+
+		return list
+	}
+
+	tests := map[string]struct {
+		order []string
+		want  []string
+	}{
+		"priority":      {want: []string{"D", "B", "C", "A"}},
+		"order":         {order: []string{"C", "A"}, want: []string{"C", "A", "D", "B"}},
+		"unknown_names": {order: []string{"-", "B"}, want: []string{"B", "D", "C", "A"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, names(curxrt.Arrange(defs, tc.order...)))
+		})
+	}
+}
+
+func TestParseDefinitions(t *testing.T) {
+	t.Setenv("TEST_PROVIDER_KEY", "")
+
+	defs, err := curxrt.ParseDefinitions([]byte(`[
+		{"name": "Keyless", "endpoint": "https://example.com/{base}", "extract": {"rate": "$.rate"}, "timeout": "2s"},
+		{"name": "Disabled", "endpoint": "https://example.com", "extract": {"rate": "$.rate"}, "enabled": false},
+		{"name": "NoKey", "endpoint": "https://example.com", "extract": {"rate": "$.rate"},
+			"auth": {"in": "header", "name": "X-Key", "key": "${TEST_PROVIDER_KEY}"}}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, defs, 3)
+
+	require.True(t, defs[0].IsEnabled())
+	require.True(t, defs[0].HasCredentials())
+	require.Equal(t, 2*time.Second, defs[0].Config().Timeout)
+
+	require.False(t, defs[1].IsEnabled())
+
+	require.True(t, defs[2].IsEnabled())
+	require.False(t, defs[2].HasCredentials())
+
+	_, err = curxrt.ParseDefinitions([]byte(`[{"name": "Bad", "endpoint": "https://example.com", "extract": {"rate": "rate"}}]`))
+	require.ErrorIs(t, err, curxrt.ErrInvalidDefinition)
+}
+
+func TestDefinitionProcessResponse(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		extract  curxrt.Extract
+		body     string
+		wantRate rate.ExchangeRate
+		wantErr  error
+	}{
+		"bid_ask_numbers": {
+			extract:  curxrt.Extract{Rate: "$.rate", Bid: "$.bid", Ask: "$.ask"},
+			body:     `{"rate":36.9,"bid":36.89,"ask":36.91}`,
+			wantRate: rate.ExchangeRate{Value: 36.9, Bid: 36.89, Ask: 36.91},
+		},
+		"bid_ask_strings": {
+			extract:  curxrt.Extract{Rate: "$.rate", Bid: "$.bid", Ask: "$.ask"},
+			body:     `{"rate":"36.9","bid":"36.89","ask":"36.91"}`,
+			wantRate: rate.ExchangeRate{Value: 36.9, Bid: 36.89, Ask: 36.91},
+		},
+		"bid_ask_missing": {
+			extract:  curxrt.Extract{Rate: "$.rate", Bid: "$.bid", Ask: "$.ask"},
+			body:     `{"rate":36.9,"bid":"n/a"}`,
+			wantRate: rate.ExchangeRate{Value: 36.9},
+		},
+		"timestamp_rfc3339": {
+			extract:  curxrt.Extract{Rate: "$.rate", Timestamp: "$.time"},
+			body:     `{"rate":36.9,"time":"2023-07-01T10:00:01+03:00"}`,
+			wantRate: rate.ExchangeRate{Value: 36.9, Timestamp: time.Date(2023, 7, 1, 7, 0, 1, 0, time.UTC)},
+		},
+		"timestamp_unix": {
+			extract:  curxrt.Extract{Rate: "$.rate", Timestamp: "$.time", TimestampFormat: curxrt.TimestampUnix},
+			body:     `{"rate":36.9,"time":1688205601}`,
+			wantRate: rate.ExchangeRate{Value: 36.9, Timestamp: time.Date(2023, 7, 1, 10, 0, 1, 0, time.UTC)},
+		},
+		"timestamp_in_time_zone": {
+			extract:  curxrt.Extract{Rate: "$.rate", Timestamp: "$.time", TimestampFormat: "2006-01-02 15:04:05", TimeZone: "$.tz"},
+			body:     `{"rate":36.9,"time":"2023-07-01 10:00:01","tz":"Europe/Kyiv"}`,
+			wantRate: rate.ExchangeRate{Value: 36.9, Timestamp: time.Date(2023, 7, 1, 10, 0, 1, 0, kyiv)},
+		},
+		"timestamp_unknown_time_zone": {
+			extract:  curxrt.Extract{Rate: "$.rate", Timestamp: "$.time", TimestampFormat: "2006-01-02 15:04:05", TimeZone: "$.tz"},
+			body:     `{"rate":36.9,"time":"2023-07-01 10:00:01","tz":"Mars/Olympus"}`,
+			wantRate: rate.ExchangeRate{Value: 36.9, Timestamp: time.Date(2023, 7, 1, 10, 0, 1, 0, time.UTC)},
+		},
+		"timestamp_malformed": {
+			extract:  curxrt.Extract{Rate: "$.rate", Timestamp: "$.time"},
+			body:     `{"rate":36.9,"time":"yesterday"}`,
+			wantRate: rate.ExchangeRate{Value: 36.9},
+		},
+		"unknown_symbol": {
+			extract: curxrt.Extract{Rate: "$.rates.XYZ"},
+			body:    `{"rates":{"UAH":36.9}}`,
+			wantErr: rate.ErrUnknownSymbol,
+		},
+		"error_body": {
+			extract: curxrt.Extract{Rate: "$.rates.XYZ"},
+			body:    `{"error":"invalid access key"}`,
+			wantErr: curxrt.ErrInvalidPath,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			def := curxrt.Definition{Name: "Test", Extract: tc.extract}
+
+			xrt, err := def.ProcessResponse(&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(tc.body)),
+			})
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, errors.Is(tc.wantErr, rate.ErrUnknownSymbol), errors.Is(err, rate.ErrUnknownSymbol))
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			require.Equal(t, tc.wantRate.Value, xrt.Value)
+			require.Equal(t, tc.wantRate.Bid, xrt.Bid)
+			require.Equal(t, tc.wantRate.Ask, xrt.Ask)
+			require.True(t, tc.wantRate.Timestamp.Equal(xrt.Timestamp), xrt.Timestamp)
+		})
+	}
+}
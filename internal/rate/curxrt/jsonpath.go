@@ -0,0 +1,161 @@
+package curxrt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPath = errors.New("invalid JSON path")
+
+// Path is a parsed JSON path expression like $.rates.USD, $['5. Exchange Rate'] or $.data[0].price.
+type Path []any
+
+// ParsePath parses a JSON path expression into object keys and array indices.
+// Object keys containing dots or spaces have to be quoted within brackets.
+func ParsePath(expr string) (Path, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(expr), "$")
+	if !ok {
+		return nil, fmt.Errorf("%w: %q: must start with $", ErrInvalidPath, expr)
+	}
+
+	var path Path
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			if end == 0 {
+				return nil, fmt.Errorf("%w: %q: empty key", ErrInvalidPath, expr)
+			}
+
+			path, rest = append(path, rest[:end]), rest[end:]
+
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %q: unclosed bracket", ErrInvalidPath, expr)
+			}
+
+			seg := rest[1:end]
+			rest = rest[end+1:]
+
+			if key, err := strconv.Unquote(strings.ReplaceAll(seg, "'", `"`)); err == nil {
+				path = append(path, key)
+				continue
+			}
+
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("%w: %q: invalid segment %q", ErrInvalidPath, expr, seg)
+			}
+
+			path = append(path, idx)
+
+		default:
+			return nil, fmt.Errorf("%w: %q: unexpected %q", ErrInvalidPath, expr, rest[0])
+		}
+	}
+
+	return path, nil
+}
+
+// Lookup returns the value the path points to in the decoded JSON document.
+func (p Path) Lookup(doc any) (any, error) {
+	val := doc
+
+	for _, seg := range p {
+		switch seg := seg.(type) {
+		case string:
+			obj, ok := val.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%w: %q: not an object", ErrInvalidPath, seg)
+			}
+
+			if val, ok = obj[seg]; !ok {
+				return nil, fmt.Errorf("%w: %q: key not found", ErrInvalidPath, seg)
+			}
+
+		case int:
+			arr, ok := val.([]any)
+			if !ok || seg >= len(arr) {
+				return nil, fmt.Errorf("%w: [%d]: index out of range", ErrInvalidPath, seg)
+			}
+
+			val = arr[seg]
+		}
+	}
+
+	return val, nil
+}
+
+// Missing reports whether the object the last key of the path belongs to is there but lacks the key.
+func (p Path) Missing(doc any) bool {
+	n := len(p) - 1
+	if n < 0 {
+		return false
+	}
+
+	key, ok := p[n].(string)
+	if !ok {
+		return false
+	}
+
+	parent, err := p[:n].Lookup(doc)
+	if err != nil {
+		return false
+	}
+
+	obj, ok := parent.(map[string]any)
+	if !ok {
+		return false
+	}
+
+	_, ok = obj[key]
+
+	return !ok
+}
+
+// Float returns the number the path points to, parsing it from a string if needed.
+func (p Path) Float(doc any) (float64, error) {
+	val, err := p.Lookup(doc)
+	if err != nil {
+		return 0, err
+	}
+
+	switch val := val.(type) {
+	case json.Number:
+		return val.Float64()
+	case float64:
+		return val, nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(val), 64)
+	default:
+		return 0, fmt.Errorf("%w: %T is not a number", ErrInvalidPath, val)
+	}
+}
+
+// String returns the string the path points to.
+func (p Path) String(doc any) (string, error) {
+	val, err := p.Lookup(doc)
+	if err != nil {
+		return "", err
+	}
+
+	switch val := val.(type) {
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	default:
+		return "", fmt.Errorf("%w: %T is not a string", ErrInvalidPath, val)
+	}
+}
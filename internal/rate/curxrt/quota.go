@@ -0,0 +1,175 @@
+package curxrt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+)
+
+// Usage represents the number of calls made to the provider during a day.
+type Usage struct {
+	Provider string
+	// Day is the UTC date in time.DateOnly format the calls are counted for.
+	Day  string
+	Used int
+}
+
+// UsageStorer is an interface for persisting the usage counters.
+type UsageStorer interface {
+	Append(...Usage) error
+	Replace(...Usage) error
+	FetchAll() ([]Usage, error)
+}
+
+// Quotas enforces per-minute rate limits with a token bucket and daily quotas of the providers,
+// the background requests leave the reserve of both limits for the on-demand ones.
+// Daily usage is persisted by appending every counted call, so the counters survive restarts
+// and the latest counter of the provider wins when loading them.
+type Quotas struct {
+	store   UsageStorer
+	reserve float64
+
+	mu      sync.Mutex
+	usage   map[string]*Usage
+	buckets map[string]*bucket
+}
+
+// bucket is a token bucket refilled continuously up to its capacity.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewQuotas creates a new Quotas instance loading the persisted usage and compacting it to the latest counters.
+// The reserve is the fraction of the limits like 0.2 the background requests are not allowed to use.
+func NewQuotas(store UsageStorer, reserve float64) (*Quotas, error) {
+	q := Quotas{
+		store:   store,
+		reserve: reserve,
+		usage:   make(map[string]*Usage),
+		buckets: make(map[string]*bucket),
+	}
+
+	list, err := store.FetchAll()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("loading usage: %w", err)
+	}
+
+	for i := range list {
+		q.usage[list[i].Provider] = &list[i]
+	}
+
+	if len(list) > len(q.usage) {
+		if err := store.Replace(q.snapshot()...); err != nil {
+			return nil, fmt.Errorf("compacting usage: %w", err)
+		}
+	}
+
+	return &q, nil
+}
+
+// Allow takes a token from the provider bucket and counts the call against the daily quota.
+// It returns rate.ErrQuotaExhausted without counting anything when either limit is reached,
+// which is short of the reserve for the requests marked with rate.WithBackground.
+// Zero limits are not enforced.
+func (q *Quotas) Allow(ctx context.Context, cfg Config) error {
+	if cfg.PerMinute <= 0 && cfg.Daily <= 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	day := now.UTC().Format(time.DateOnly)
+
+	use, ok := q.usage[cfg.Name]
+	if !ok || use.Day != day {
+		use = &Usage{Provider: cfg.Name, Day: day}
+		q.usage[cfg.Name] = use
+	}
+
+	var keep float64
+	if rate.IsBackground(ctx) {
+		keep = q.reserve
+	}
+
+	if cfg.Daily > 0 && use.Used >= cfg.Daily-int(math.Ceil(float64(cfg.Daily)*keep)) {
+		return fmt.Errorf("%w: %s: daily quota of %d calls", rate.ErrQuotaExhausted, cfg.Name, cfg.Daily)
+	}
+
+	if cfg.PerMinute > 0 && !q.take(cfg.Name, cfg.PerMinute, keep, now) {
+		return fmt.Errorf("%w: %s: rate limit of %d calls per minute", rate.ErrQuotaExhausted, cfg.Name, cfg.PerMinute)
+	}
+
+	if cfg.Daily <= 0 {
+		return nil
+	}
+
+	next := *use
+	next.Used++
+
+	// The call is counted once it is persisted, so a failed write does not eat into the quota.
+	if err := q.store.Append(next); err != nil {
+		return fmt.Errorf("persisting usage: %w", err)
+	}
+
+	*use = next
+
+	return nil
+}
+
+// Usage returns the number of calls counted for the provider today.
+func (q *Quotas) Usage(name string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	use, ok := q.usage[name]
+	if !ok || use.Day != time.Now().UTC().Format(time.DateOnly) {
+		return 0
+	}
+
+	return use.Used
+}
+
+// take takes a token from the bucket of the provider refilling it at the rate of perMinute tokens per minute,
+// the keep fraction of the capacity is left in the bucket.
+func (q *Quotas) take(name string, perMinute int, keep float64, now time.Time) bool {
+	capacity := float64(perMinute)
+
+	b, ok := q.buckets[name]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		q.buckets[name] = b
+	}
+
+	b.tokens += now.Sub(b.last).Minutes() * capacity
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+
+	b.last = now
+
+	if b.tokens < 1+capacity*keep {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
+
+func (q *Quotas) snapshot() []Usage {
+	list := make([]Usage, 0, len(q.usage))
+	for _, use := range q.usage {
+		list = append(list, *use)
+	}
+
+	return list
+}
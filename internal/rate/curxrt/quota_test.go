@@ -0,0 +1,146 @@
+package curxrt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate/curxrt"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/filestore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotasAllow(t *testing.T) {
+	tests := map[string]struct {
+		cfg        curxrt.Config
+		background bool
+		calls      int
+		allowed    int
+	}{
+		"unlimited": {
+			cfg:     curxrt.Config{Name: "Unlimited"},
+			calls:   10,
+			allowed: 10,
+		},
+
+		"per_minute": {
+			cfg:     curxrt.Config{Name: "PerMinute", PerMinute: 3},
+			calls:   5,
+			allowed: 3,
+		},
+
+		"daily": {
+			cfg:     curxrt.Config{Name: "Daily", Daily: 2},
+			calls:   5,
+			allowed: 2,
+		},
+
+		"per_minute_background": {
+			cfg:        curxrt.Config{Name: "PerMinute", PerMinute: 10},
+			background: true,
+			calls:      10,
+			allowed:    8,
+		},
+
+		"daily_background": {
+			cfg:        curxrt.Config{Name: "Daily", Daily: 2},
+			background: true,
+			calls:      5,
+			allowed:    1,
+		},
+
+		"both": {
+			cfg:     curxrt.Config{Name: "Both", PerMinute: 4, Daily: 2},
+			calls:   5,
+			allowed: 2,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			q, err := curxrt.NewQuotas(filestore.NewAppendLog[curxrt.Usage](t.TempDir()), 0.2)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			if tc.background {
+				ctx = rate.WithBackground(ctx)
+			}
+
+			var allowed int
+
+			for i := 0; i < tc.calls; i++ {
+				err := q.Allow(ctx, tc.cfg)
+				if err == nil {
+					allowed++
+					continue
+				}
+
+				require.ErrorIs(t, err, rate.ErrQuotaExhausted)
+			}
+
+			require.Equal(t, tc.allowed, allowed)
+		})
+	}
+}
+
+func TestQuotasPersist(t *testing.T) {
+	store := filestore.NewAppendLog[curxrt.Usage](t.TempDir())
+	cfg := curxrt.Config{Name: "Daily", Daily: 3}
+
+	q, err := curxrt.NewQuotas(store, 0)
+	require.NoError(t, err)
+	require.NoError(t, q.Allow(context.Background(), cfg))
+	require.NoError(t, q.Allow(context.Background(), cfg))
+
+	restarted, err := curxrt.NewQuotas(store, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, restarted.Usage(cfg.Name))
+
+	compacted, err := store.FetchAll()
+	require.NoError(t, err)
+	require.Len(t, compacted, 1)
+
+	require.NoError(t, restarted.Allow(context.Background(), cfg))
+	require.ErrorIs(t, restarted.Allow(context.Background(), cfg), rate.ErrQuotaExhausted)
+}
+
+func TestQuotasPersistFailed(t *testing.T) {
+	errWrite := errors.New("disk full")
+	store := &usageStore{err: errWrite}
+	cfg := curxrt.Config{Name: "Daily", Daily: 1}
+
+	q, err := curxrt.NewQuotas(store, 0)
+	require.NoError(t, err)
+	require.ErrorIs(t, q.Allow(context.Background(), cfg), errWrite)
+	require.Zero(t, q.Usage(cfg.Name))
+
+	store.err = nil
+	require.NoError(t, q.Allow(context.Background(), cfg))
+	require.Equal(t, 1, q.Usage(cfg.Name))
+	require.Equal(t, []curxrt.Usage{{Provider: cfg.Name, Day: store.items[0].Day, Used: 1}}, store.items)
+}
+
+type usageStore struct {
+	items []curxrt.Usage
+	err   error
+}
+
+func (s *usageStore) Append(items ...curxrt.Usage) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.items = append(s.items, items...)
+
+	return nil
+}
+
+func (s *usageStore) Replace(items ...curxrt.Usage) error {
+	s.items = items
+	return nil
+}
+
+func (s *usageStore) FetchAll() ([]curxrt.Usage, error) {
+	return s.items, nil
+}
@@ -0,0 +1,342 @@
+package curxrt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+)
+
+// Retryable classes of failures, the client errors including 429 are never retried.
+const (
+	ClassServer  = "server"
+	ClassUnknown = "unknown"
+	ClassNetwork = "network"
+)
+
+// RetryPolicy defines how the failed calls to the provider are retried and hedged.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts including the first one.
+	Attempts int
+	// Backoff is the delay before the first retry, doubled for every next one up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// RetryOn lists the retryable classes of failures: 5xx status codes, other unexpected ones or network errors.
+	RetryOn []string
+	// HedgeQuantile is the latency quantile like 0.95 after which a hedged attempt is fired, zero disables hedging.
+	HedgeQuantile float64
+}
+
+// Retrier retries failed attempts with exponential backoff and jitter,
+// optionally hedging slow attempts with a concurrent one.
+type Retrier struct {
+	policy RetryPolicy
+
+	mu  sync.Mutex
+	lat map[string]*latencies
+}
+
+// latencies is a ring buffer of the latest attempt durations.
+type latencies struct {
+	durs []time.Duration
+	next int
+}
+
+// Attempt is a single call to the provider.
+type Attempt func(context.Context) (*http.Response, error)
+
+// NewRetrier creates a new Retrier instance.
+func NewRetrier(policy RetryPolicy) *Retrier {
+	if policy.Attempts < 1 {
+		policy.Attempts = 1
+	}
+
+	return &Retrier{
+		policy: policy,
+		lat:    make(map[string]*latencies),
+	}
+}
+
+// Do runs the attempt retrying it while the failure is retryable and the context deadline allows to wait.
+// The name identifies the provider the latencies are tracked for.
+// Every attempt, the hedged one included, is started only if allow, when not nil, lets it through.
+func (r *Retrier) Do(ctx context.Context, name string, allow func(context.Context) error, attempt Attempt) (*http.Response, error) {
+	for i := 1; ; i++ {
+		resp, err := r.hedge(ctx, name, allow, attempt)
+		if i >= r.policy.Attempts || !r.retryable(ctx, resp, err) {
+			return resp, err
+		}
+
+		wait := r.backoff(i)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, err
+		}
+
+		if resp != nil {
+			drain(resp)
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// hedge runs the attempt firing another one concurrently
+// when the first is slower than the configured latency quantile and allow lets it through.
+// While an attempt is running only a success ends the call, the first non-retryable failure is reported otherwise.
+func (r *Retrier) hedge(ctx context.Context, name string, allow func(context.Context) error, attempt Attempt) (*http.Response, error) {
+	if allow != nil {
+		if err := allow(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	delay, ok := r.quantile(name)
+	if !ok {
+		return r.measure(ctx, name, attempt)
+	}
+
+	type result struct {
+		resp *http.Response
+		err  error
+		idx  int
+	}
+
+	results := make(chan result, 2)
+	cancels := make([]context.CancelFunc, 0, 2)
+
+	run := func() {
+		ctx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+
+		go func(idx int) {
+			resp, err := r.measure(ctx, name, attempt)
+			results <- result{resp: resp, err: err, idx: idx}
+		}(len(cancels) - 1)
+	}
+
+	discard := func(res *result) {
+		if res.resp != nil {
+			drain(res.resp)
+		}
+
+		cancels[res.idx]()
+	}
+
+	// failed is the failure reported if no attempt succeeds.
+	var failed *result
+
+	keep := func(res result) {
+		switch {
+		case failed == nil:
+		case !r.retryable(ctx, failed.resp, failed.err):
+			discard(&res)
+			return
+		default:
+			discard(failed)
+		}
+
+		failed = &res
+	}
+
+	run()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	pending := 1
+
+	for {
+		select {
+		case <-timer.C:
+			// The hedged attempt refused by the limits is not started, the first one keeps running.
+			if allow != nil && allow(ctx) != nil {
+				continue
+			}
+
+			pending++
+
+			run()
+
+		case res := <-results:
+			pending--
+
+			if res.err != nil || web.ErrFromStatusCode(res.resp.StatusCode) != nil {
+				keep(res)
+
+				if pending > 0 {
+					continue
+				}
+
+				res = *failed
+			}
+
+			// The late attempts are cancelled and cleaned up in the background.
+			for i := range cancels {
+				if i != res.idx {
+					cancels[i]()
+				}
+			}
+
+			go func(n int) {
+				for ; n > 0; n-- {
+					if late := <-results; late.resp != nil {
+						drain(late.resp)
+					}
+				}
+			}(pending)
+
+			if res.resp != nil {
+				res.resp.Body = &cancelBody{ReadCloser: res.resp.Body, cancel: cancels[res.idx]}
+			} else {
+				cancels[res.idx]()
+			}
+
+			return res.resp, res.err
+		}
+	}
+}
+
+// measure runs the attempt recording its latency when it succeeds.
+func (r *Retrier) measure(ctx context.Context, name string, attempt Attempt) (*http.Response, error) {
+	start := time.Now()
+
+	resp, err := attempt(ctx)
+	if err == nil && web.ErrFromStatusCode(resp.StatusCode) == nil {
+		r.record(name, time.Since(start))
+	}
+
+	return resp, err
+}
+
+func (r *Retrier) record(name string, dur time.Duration) {
+	const size = 100
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lat, ok := r.lat[name]
+	if !ok {
+		lat = &latencies{durs: make([]time.Duration, 0, size)}
+		r.lat[name] = lat
+	}
+
+	if len(lat.durs) < size {
+		lat.durs = append(lat.durs, dur)
+		return
+	}
+
+	lat.durs[lat.next] = dur
+	lat.next = (lat.next + 1) % size
+}
+
+// quantile returns the latency quantile of the provider once enough samples are collected.
+func (r *Retrier) quantile(name string) (time.Duration, bool) {
+	const minSamples = 10
+
+	if r.policy.HedgeQuantile <= 0 || r.policy.HedgeQuantile >= 1 {
+		return 0, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lat, ok := r.lat[name]
+	if !ok || len(lat.durs) < minSamples {
+		return 0, false
+	}
+
+	durs := append([]time.Duration(nil), lat.durs...)
+	sort.Slice(durs, func(i, j int) bool { return durs[i] < durs[j] })
+
+	return durs[int(float64(len(durs)-1)*r.policy.HedgeQuantile)], true
+}
+
+// backoff returns the jittered delay before the retry following the given attempt.
+func (r *Retrier) backoff(attempt int) time.Duration {
+	wait := r.policy.Backoff << (attempt - 1)
+	if wait <= 0 || (r.policy.MaxBackoff > 0 && wait > r.policy.MaxBackoff) {
+		wait = r.policy.MaxBackoff
+	}
+
+	if wait <= 0 {
+		return 0
+	}
+
+	const halves = 2
+
+	// Equal jitter keeps at least the half of the delay, so the deadline check stays meaningful.
+	half := wait / halves
+
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1)) //nolint:gosec
+}
+
+// retryable reports whether the failure belongs to one of the retryable classes.
+func (r *Retrier) retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var class string
+
+	switch {
+	case err != nil:
+		var netErr net.Error
+		if !errors.As(err, &netErr) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return false
+		}
+
+		class = ClassNetwork
+
+	case web.ErrFromStatusCode(resp.StatusCode) == nil:
+		return false
+
+	// Retrying a rejected request, even a rate limited one, only eats into the quota.
+	case resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError:
+		return false
+
+	case resp.StatusCode >= http.StatusInternalServerError:
+		class = ClassServer
+
+	default:
+		class = ClassUnknown
+	}
+
+	for i := range r.policy.RetryOn {
+		if r.policy.RetryOn[i] == class {
+			return true
+		}
+	}
+
+	return false
+}
+
+// drain discards the rest of the response body and closes it, so the connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// cancelBody cancels the context of the attempt once the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,238 @@
+package curxrt_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate/curxrt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrierDo(t *testing.T) {
+	policy := curxrt.RetryPolicy{
+		Attempts:   3,
+		Backoff:    time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+		RetryOn:    []string{curxrt.ClassServer, curxrt.ClassNetwork},
+	}
+
+	tests := map[string]struct {
+		codes     []int
+		timeout   time.Duration
+		backoff   time.Duration
+		wantCode  int
+		wantCalls int32
+		wantErr   error
+	}{
+		"success": {
+			codes:     []int{http.StatusOK},
+			wantCode:  http.StatusOK,
+			wantCalls: 1,
+		},
+
+		"retry_server_error": {
+			codes:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantCode:  http.StatusOK,
+			wantCalls: 3,
+		},
+
+		"attempts_exhausted": {
+			codes:     []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			wantCode:  http.StatusBadGateway,
+			wantCalls: 3,
+		},
+
+		"not_retryable": {
+			codes:     []int{http.StatusBadRequest, http.StatusOK},
+			wantCode:  http.StatusBadRequest,
+			wantCalls: 1,
+		},
+
+		"retry_gateway_timeout": {
+			codes:     []int{http.StatusGatewayTimeout, http.StatusOK},
+			wantCode:  http.StatusOK,
+			wantCalls: 2,
+		},
+
+		"rate_limited": {
+			codes:     []int{http.StatusTooManyRequests, http.StatusOK},
+			wantCode:  http.StatusTooManyRequests,
+			wantCalls: 1,
+		},
+
+		"deadline": {
+			codes:     []int{http.StatusBadGateway, http.StatusOK},
+			timeout:   50 * time.Millisecond,
+			backoff:   time.Second,
+			wantCode:  http.StatusBadGateway,
+			wantCalls: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				n := calls.Add(1)
+				rw.WriteHeader(tc.codes[n-1])
+			}))
+			defer srv.Close()
+
+			policy := policy
+			if tc.backoff > 0 {
+				policy.Backoff, policy.MaxBackoff = tc.backoff, tc.backoff
+			}
+
+			ctx := context.Background()
+
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+
+				defer cancel()
+			}
+
+			rtr := curxrt.NewRetrier(policy)
+
+			resp, err := rtr.Do(ctx, "TestProvider", nil, func(ctx context.Context) (*http.Response, error) {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+				if err != nil {
+					return nil, err
+				}
+
+				return srv.Client().Do(req)
+			})
+			require.ErrorIs(t, err, tc.wantErr)
+
+			defer resp.Body.Close()
+
+			require.Equal(t, tc.wantCode, resp.StatusCode)
+			require.Equal(t, tc.wantCalls, calls.Load())
+		})
+	}
+}
+
+func TestRetrierHedge(t *testing.T) {
+	const slow = time.Second
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// Every 11th call is slow, so the hedged attempt is expected to win.
+		if calls.Add(1) == 11 {
+			select {
+			case <-time.After(slow):
+			case <-req.Context().Done():
+			}
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	rtr := curxrt.NewRetrier(curxrt.RetryPolicy{Attempts: 1, HedgeQuantile: 0.9})
+
+	attempt := func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return srv.Client().Do(req)
+	}
+
+	for i := 0; i < 10; i++ {
+		resp, err := rtr.Do(context.Background(), "TestProvider", nil, attempt)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	start := time.Now()
+
+	resp, err := rtr.Do(context.Background(), "TestProvider", nil, attempt)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Less(t, time.Since(start), slow)
+	require.Equal(t, int32(12), calls.Load())
+}
+
+func TestRetrierHedgeFailure(t *testing.T) {
+	const slow = 200 * time.Millisecond
+
+	tests := map[string]struct {
+		// hedgeCode is the status code of the hedged attempt.
+		hedgeCode int
+		// refuse makes the limits refuse the hedged attempt.
+		refuse    bool
+		wantCalls int32
+	}{
+		"hedge_refused": {
+			refuse:    true,
+			wantCalls: 11,
+		},
+		"hedge_rejected": {
+			hedgeCode: http.StatusBadRequest,
+			wantCalls: 12,
+		},
+		"hedge_server_error": {
+			hedgeCode: http.StatusInternalServerError,
+			wantCalls: 12,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			var calls, allowed atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				switch calls.Add(1) {
+				case 11:
+					time.Sleep(slow)
+				case 12:
+					rw.WriteHeader(tc.hedgeCode)
+					return
+				}
+
+				rw.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			allow := func(context.Context) error {
+				if allowed.Add(1) > 11 && tc.refuse {
+					return rate.ErrQuotaExhausted
+				}
+
+				return nil
+			}
+
+			attempt := func(ctx context.Context) (*http.Response, error) {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+				if err != nil {
+					return nil, err
+				}
+
+				return srv.Client().Do(req)
+			}
+
+			rtr := curxrt.NewRetrier(curxrt.RetryPolicy{Attempts: 1, HedgeQuantile: 0.9})
+
+			for i := 0; i < 11; i++ {
+				resp, err := rtr.Do(context.Background(), "TestProvider", allow, attempt)
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				resp.Body.Close()
+			}
+
+			require.Equal(t, tc.wantCalls, calls.Load())
+		})
+	}
+}
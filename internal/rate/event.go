@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
 )
@@ -34,43 +35,88 @@ type CurrencyPairEvent interface {
 type ProviderResponse struct {
 	Provider     string
 	ExchangeRate *ExchangeRate
+	// Latency is the duration of the provider call.
+	Latency time.Duration
+}
+
+// BaseCurrency implements CurrencyPairEvent.
+func (r ProviderResponse) BaseCurrency() string {
+	if r.ExchangeRate == nil {
+		return ""
+	}
+
+	return r.ExchangeRate.Pair.Base
+}
+
+// QuoteCurrency implements CurrencyPairEvent.
+func (r ProviderResponse) QuoteCurrency() string {
+	if r.ExchangeRate == nil {
+		return ""
+	}
+
+	return r.ExchangeRate.Pair.Quote
+}
+
+// Rate returns the fetched exchange rate value, zero if there is none.
+func (r ProviderResponse) Rate() float64 {
+	if r.ExchangeRate == nil {
+		return 0
+	}
+
+	return r.ExchangeRate.Value
 }
 
 // ProviderErrorResponse represents the data of a provider error event.
 type ProviderErrorResponse struct {
 	Provider string
+	Pair     CurrencyPair
 	Err      error
+	// Latency is the duration of the provider call.
+	Latency time.Duration
 }
 
 // LogExchangeRate is an event listener designed for log the exchange rate fetching.
 func (svc *Service) LogExchangeRate(ctx context.Context, e event.Event) error {
-	switch e.Payload.(type) {
-	case ProviderResponse, ProviderErrorResponse:
-		// The Payload will be logged by event dispatcher.
-	default:
-		return fmt.Errorf("%w: unexpected payload: %T", ErrInvalidEvent, e.Payload)
-	}
-
-	return nil
+	return logExchangeRate(ctx, e)
 }
 
 // ResponseExchangeRate in an event listener that fetches the exchange rate for a requested currency pair.
 func (svc *Service) ResponseExchangeRate(ctx context.Context, e event.Event) error {
-	req, ok := e.Payload.(CurrencyPairEvent)
-	if !ok {
-		return fmt.Errorf("%w: unexpected payload, expected CurrencyPairEvent: %T", ErrInvalidEvent, e.Payload)
-	}
+	return RespondExchangeRate(svc)(ctx, e)
+}
 
-	xrt, err := svc.GetExchangeRate(ctx, NewCurrencyPair(req.BaseCurrency(), req.QuoteCurrency()))
-	if err != nil {
-		return fmt.Errorf("responding exchange rate event: %w", err)
-	}
+// RespondExchangeRate returns an event listener that responds
+// to the exchange rate requests using provided ExchangeRateService.
+func RespondExchangeRate(svc ExchangeRateService) event.Listener {
+	return func(ctx context.Context, e event.Event) error {
+		req, ok := e.Payload.(CurrencyPairEvent)
+		if !ok {
+			return fmt.Errorf("%w: unexpected payload, expected CurrencyPairEvent: %T", ErrInvalidEvent, e.Payload)
+		}
+
+		xrt, err := svc.GetExchangeRate(ctx, NewCurrencyPair(req.BaseCurrency(), req.QuoteCurrency()))
+		if err != nil {
+			return fmt.Errorf("responding exchange rate event: %w", err)
+		}
+
+		if e.Response == nil {
+			return fmt.Errorf("responding exchange rate event: %w", ErrInvalidChannel)
+		}
 
-	if e.Response == nil {
-		return fmt.Errorf("responding exchange rate event: %w", ErrInvalidChannel)
+		e.Response <- event.New(EventSource, EventKindResponded, xrt)
+
+		return nil
 	}
+}
 
-	e.Response <- event.New(EventSource, EventKindResponded, xrt)
+// logExchangeRate checks the payload of the exchange rate fetching events to be logged.
+func logExchangeRate(_ context.Context, e event.Event) error {
+	switch e.Payload.(type) {
+	case ProviderResponse, ProviderErrorResponse:
+		// The Payload will be logged by event dispatcher.
+	default:
+		return fmt.Errorf("%w: unexpected payload: %T", ErrInvalidEvent, e.Payload)
+	}
 
 	return nil
 }
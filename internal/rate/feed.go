@@ -0,0 +1,150 @@
+package rate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+// Feed fans the fetched exchange rates out to the watchers of their pairs.
+// It subscribes to the bus once, so the watchers come and go without touching the bus.
+type Feed struct {
+	mu       sync.RWMutex
+	watchers map[*Watcher]struct{}
+}
+
+// Watcher receives the fetched exchange rates of the pairs it watches.
+// Rates are dropped when the buffer is full, so a slow client can not block the bus.
+type Watcher struct {
+	feed  *Feed
+	ticks chan ExchangeRate
+
+	mu      sync.Mutex
+	pairs   map[CurrencyPair]struct{}
+	dropped int
+}
+
+// NewFeed creates a new Feed instance subscribed to the fetched exchange rates.
+func NewFeed(bus *event.Bus) *Feed {
+	f := Feed{watchers: make(map[*Watcher]struct{})}
+
+	bus.Subscribe(event.New(EventSource, EventKindFetched, nil), f.ForwardExchangeRate)
+
+	return &f
+}
+
+// Watch registers a new watcher of the pairs with the buffer of the given size.
+// The watcher must be closed once it is not needed anymore.
+func (f *Feed) Watch(buffer int, pairs ...CurrencyPair) *Watcher {
+	w := Watcher{
+		feed:  f,
+		ticks: make(chan ExchangeRate, buffer),
+		pairs: make(map[CurrencyPair]struct{}, len(pairs)),
+	}
+
+	w.Add(pairs...)
+
+	f.mu.Lock()
+	f.watchers[&w] = struct{}{}
+	f.mu.Unlock()
+
+	return &w
+}
+
+// ForwardExchangeRate is an event listener that passes the fetched exchange rate to the watchers of its pair.
+func (f *Feed) ForwardExchangeRate(_ context.Context, e event.Event) error {
+	resp, ok := e.Payload.(ProviderResponse)
+	if !ok {
+		return fmt.Errorf("%w: unexpected payload, expected ProviderResponse: %T", ErrInvalidEvent, e.Payload)
+	}
+
+	if resp.ExchangeRate == nil {
+		return fmt.Errorf("%w: missing exchange rate from %s", ErrInvalidEvent, resp.Provider)
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for w := range f.watchers {
+		w.offer(*resp.ExchangeRate)
+	}
+
+	return nil
+}
+
+// Ticks returns the channel the exchange rates are received from, it is closed by Close.
+func (w *Watcher) Ticks() <-chan ExchangeRate {
+	return w.ticks
+}
+
+// Add starts watching the pairs.
+func (w *Watcher) Add(pairs ...CurrencyPair) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i := range pairs {
+		w.pairs[pairs[i]] = struct{}{}
+	}
+}
+
+// Remove stops watching the pairs.
+func (w *Watcher) Remove(pairs ...CurrencyPair) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i := range pairs {
+		delete(w.pairs, pairs[i])
+	}
+}
+
+// Pairs returns the pairs being watched.
+func (w *Watcher) Pairs() []CurrencyPair {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	pairs := make([]CurrencyPair, 0, len(w.pairs))
+	for pair := range w.pairs {
+		pairs = append(pairs, pair)
+	}
+
+	return pairs
+}
+
+// Dropped returns the number of exchange rates dropped because of the full buffer.
+func (w *Watcher) Dropped() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.dropped
+}
+
+// Close unregisters the watcher and closes its channel.
+func (w *Watcher) Close() {
+	w.feed.mu.Lock()
+	defer w.feed.mu.Unlock()
+
+	if _, ok := w.feed.watchers[w]; !ok {
+		return
+	}
+
+	delete(w.feed.watchers, w)
+	close(w.ticks)
+}
+
+// offer passes the exchange rate without blocking, it must be called with the feed read lock held.
+func (w *Watcher) offer(xrt ExchangeRate) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.pairs[xrt.Pair]; !ok {
+		return
+	}
+
+	select {
+	case w.ticks <- xrt:
+	default:
+		w.dropped++
+	}
+}
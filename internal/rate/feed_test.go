@@ -0,0 +1,201 @@
+package rate_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+	"github.com/stretchr/testify/require"
+)
+
+func fetchedEvent(pair rate.CurrencyPair, val float64, at time.Time) event.Event {
+	return event.New(rate.EventSource, rate.EventKindFetched, rate.ProviderResponse{
+		Provider:     "Test",
+		ExchangeRate: &rate.ExchangeRate{Pair: pair, Value: val, Provider: "Test", FetchedAt: at},
+	})
+}
+
+func TestFeedWatch(t *testing.T) {
+	btc, eth := rate.NewCurrencyPair("BTC", "UAH"), rate.NewCurrencyPair("ETH", "USD")
+
+	tests := map[string]struct {
+		buffer      int
+		watch       []rate.CurrencyPair
+		update      func(*rate.Watcher)
+		events      []event.Event
+		want        []float64
+		wantDropped int
+	}{
+		"watched_pairs_only": {
+			buffer: 4,
+			watch:  []rate.CurrencyPair{btc},
+			events: []event.Event{fetchedEvent(btc, 1, time.Now()), fetchedEvent(eth, 2, time.Now()), fetchedEvent(btc, 3, time.Now())},
+			want:   []float64{1, 3},
+		},
+		"added_and_removed": {
+			buffer: 4,
+			watch:  []rate.CurrencyPair{btc},
+			update: func(w *rate.Watcher) {
+				w.Add(eth)
+				w.Remove(btc)
+			},
+			events: []event.Event{fetchedEvent(btc, 1, time.Now()), fetchedEvent(eth, 2, time.Now())},
+			want:   []float64{2},
+		},
+		"full_buffer_dropped": {
+			buffer:      1,
+			watch:       []rate.CurrencyPair{btc},
+			events:      []event.Event{fetchedEvent(btc, 1, time.Now()), fetchedEvent(btc, 2, time.Now())},
+			want:        []float64{1},
+			wantDropped: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			feed := rate.NewFeed(event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug))))
+
+			w := feed.Watch(tc.buffer, tc.watch...)
+
+			if tc.update != nil {
+				tc.update(w)
+			}
+
+			for _, e := range tc.events {
+				require.NoError(t, feed.ForwardExchangeRate(context.Background(), e))
+			}
+
+			w.Close()
+			w.Close()
+
+			var got []float64
+			for xrt := range w.Ticks() {
+				got = append(got, xrt.Value)
+			}
+
+			require.Equal(t, tc.want, got)
+			require.Equal(t, tc.wantDropped, w.Dropped())
+			require.NoError(t, feed.ForwardExchangeRate(context.Background(), tc.events[0]))
+		})
+	}
+}
+
+type historyFunc func(pair rate.CurrencyPair, from, to time.Time) []rate.ExchangeRate
+
+func (f historyFunc) Range(_ context.Context, pair rate.CurrencyPair, from, to time.Time, _ time.Duration) ([]rate.ExchangeRate, error) {
+	return f(pair, from, to), nil
+}
+
+func TestStreamHandlerStream(t *testing.T) {
+	btc := rate.NewCurrencyPair("BTC", "UAH")
+	start := time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	past := []rate.ExchangeRate{
+		{Pair: btc, Value: 1, FetchedAt: start},
+		{Pair: btc, Value: 2, FetchedAt: start.Add(time.Second)},
+		{Pair: btc, Value: 3, FetchedAt: start.Add(2 * time.Second)},
+	}
+
+	hist := historyFunc(func(_ rate.CurrencyPair, from, to time.Time) []rate.ExchangeRate {
+		var list []rate.ExchangeRate
+		for _, xrt := range past {
+			if !xrt.FetchedAt.Before(from) && xrt.FetchedAt.Before(to) {
+				list = append(list, xrt)
+			}
+		}
+
+		return list
+	})
+
+	tests := map[string]struct {
+		pairs      string
+		lastID     string
+		live       []float64
+		wantStatus int
+		wantRates  []string
+	}{
+		"live_only": {
+			pairs:      "BTC/UAH",
+			live:       []float64{4},
+			wantStatus: http.StatusOK,
+			wantRates:  []string{"4"},
+		},
+		"resumed": {
+			pairs:      "btc/uah",
+			lastID:     strconv.FormatInt(start.UnixNano(), 10),
+			live:       []float64{4},
+			wantStatus: http.StatusOK,
+			wantRates:  []string{"2", "3", "4"},
+		},
+		"invalid_pairs": {
+			pairs:      "BTC-UAH",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	reg := currency.NewRegistry(append(currency.ISO4217(), currency.Crypto()...)...)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			log := logger.New(logger.WithConsoleCore(logger.LevelDebug))
+			feed := rate.NewFeed(event.NewBus(log))
+			h := rate.NewStreamHandler(feed, hist, reg, rate.StreamConfig{Heartbeat: time.Hour, Buffer: 4})
+
+			w := web.New(nil, web.WithErrors(log))
+			w.Handle(http.MethodGet, "/", "stream", h.Stream)
+
+			srv := httptest.NewServer(w)
+			defer srv.Close()
+			defer w.Close()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/stream?pairs="+tc.pairs, nil)
+			require.NoError(t, err)
+
+			if tc.lastID != "" {
+				req.Header.Set("Last-Event-ID", tc.lastID)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+
+			defer resp.Body.Close()
+
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+
+			for i, val := range tc.live {
+				e := fetchedEvent(btc, val, time.Now().Add(time.Duration(i)*time.Second))
+				require.NoError(t, feed.ForwardExchangeRate(context.Background(), e))
+			}
+
+			sc := bufio.NewScanner(resp.Body)
+
+			var got []string
+
+			for len(got) < len(tc.wantRates) && sc.Scan() {
+				data, ok := strings.CutPrefix(sc.Text(), "data: ")
+				if !ok {
+					continue
+				}
+
+				val, _, _ := strings.Cut(strings.SplitN(data, `"rate":`, 2)[1], ",")
+				got = append(got, val)
+			}
+
+			require.Equal(t, tc.wantRates, got)
+		})
+	}
+}
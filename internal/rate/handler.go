@@ -2,10 +2,17 @@ package rate
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
 )
 
@@ -18,22 +25,111 @@ type ExchangeRateService interface {
 	GetExchangeRate(context.Context, CurrencyPair) (*ExchangeRate, error)
 }
 
+// ConsensusGetter interface to get the consensus rate along with providers contributed to it.
+type ConsensusGetter interface {
+	GetConsensus(context.Context, CurrencyPair) (*Consensus, error)
+}
+
+// HistoryGetter interface to get the history of exchange rates.
+type HistoryGetter interface {
+	Range(ctx context.Context, pair CurrencyPair, from, to time.Time, interval time.Duration) ([]ExchangeRate, error)
+}
+
+// CandleGetter interface to get the candles of exchange rates.
+type CandleGetter interface {
+	Range(ctx context.Context, pair CurrencyPair, interval time.Duration, from, to time.Time) ([]Candle, error)
+}
+
+// ConversionGetter interface to convert amounts between currencies.
+type ConversionGetter interface {
+	Convert(ctx context.Context, pair CurrencyPair, amount *big.Rat, mode Rounding) (*Conversion, error)
+}
+
+// BatchGetter interface to get the exchange rates for many currency pairs at once.
+type BatchGetter interface {
+	GetExchangeRates(ctx context.Context, pairs ...CurrencyPair) ([]PairResult, error)
+}
+
+// FeedWatcher interface to watch the fetched exchange rates of the pairs.
+type FeedWatcher interface {
+	Watch(buffer int, pairs ...CurrencyPair) *Watcher
+}
+
+// BreakerStatuser interface to get the status of the provider circuit breakers.
+type BreakerStatuser interface {
+	Status() []BreakerStatus
+}
+
+// RankingStatuser interface to get the current ranking of the providers.
+type RankingStatuser interface {
+	Rank(names ...string) []ProviderStats
+}
+
+// ProviderStatuser interface to get the statistics of the provider calls.
+type ProviderStatuser interface {
+	Status() []ProviderStatus
+}
+
+// Response is a response for rate.
+// Quote details are omitted when the provider does not report them.
 type Response struct {
-	Rate float64
+	Rate      float64
+	Provider  string     `json:"provider,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Bid       float64    `json:"bid,omitempty"`
+	Ask       float64    `json:"ask,omitempty"`
+	Path      []string   `json:"path,omitempty"`
+	// Age is the number of seconds since the exchange rate was fetched, omitted when it is unknown.
+	Age *int64 `json:"age,omitempty"`
 }
 
 func NewResponse(rate *ExchangeRate) *Response {
-	return &Response{Rate: rate.Value}
+	resp := Response{
+		Rate:     rate.Value,
+		Provider: rate.Provider,
+		Bid:      rate.Bid,
+		Ask:      rate.Ask,
+		Path:     rate.Path,
+	}
+
+	if !rate.Timestamp.IsZero() {
+		resp.Timestamp = &rate.Timestamp
+	}
+
+	if !rate.FetchedAt.IsZero() {
+		age := int64(time.Since(rate.FetchedAt).Seconds())
+		resp.Age = &age
+	}
+
+	return &resp
+}
+
+// ConsensusResponse is a response for consensus rate.
+type ConsensusResponse struct {
+	Rate         float64  `json:"rate"`
+	Contributors []string `json:"contributors"`
+	Outliers     []string `json:"outliers"`
+	Failed       []string `json:"failed"`
+}
+
+func NewConsensusResponse(cons *Consensus) *ConsensusResponse {
+	return &ConsensusResponse{
+		Rate:         cons.ExchangeRate.Value,
+		Contributors: cons.Contributors,
+		Outliers:     cons.Outliers,
+		Failed:       cons.Failed,
+	}
 }
 
 // Handler structure for handling rate requests.
 type Handler struct {
 	rate ExchangeRateService
+	reg  *currency.Registry
 }
 
 // NewHandler creates a new Handler instance.
-func NewHandler(rate ExchangeRateService) Handler {
-	return Handler{rate: rate}
+func NewHandler(rate ExchangeRateService, reg *currency.Registry) Handler {
+	return Handler{rate: rate, reg: reg}
 }
 
 // Rate handles the HTTP request for the rate.
@@ -46,7 +142,7 @@ func (h *Handler) Rate(ctx context.Context, rw http.ResponseWriter, req *http.Re
 		web.FromQuery(req, "quote"),
 	)
 
-	if err := pair.Validate(); err != nil {
+	if err := pair.ValidateWith(h.reg); err != nil {
 		return web.NewRequestError(err, http.StatusBadRequest)
 	}
 
@@ -60,5 +156,866 @@ func (h *Handler) Rate(ctx context.Context, rw http.ResponseWriter, req *http.Re
 		return err
 	}
 
+	if !rate.FetchedAt.IsZero() {
+		rw.Header().Set("Age", strconv.Itoa(int(time.Since(rate.FetchedAt).Seconds())))
+	}
+
 	return web.Respond(ctx, rw, NewResponse(rate), http.StatusOK)
 }
+
+// HistoryResponse is a response for history of rates.
+type HistoryResponse struct {
+	Base   string          `json:"base"`
+	Quote  string          `json:"quote"`
+	Points []PointResponse `json:"points"`
+}
+
+// PointResponse is a single point of the rate time series.
+type PointResponse struct {
+	Time     time.Time `json:"time"`
+	Rate     float64   `json:"rate"`
+	Provider string    `json:"provider,omitempty"`
+}
+
+func NewHistoryResponse(pair CurrencyPair, xrts []ExchangeRate) *HistoryResponse {
+	points := make([]PointResponse, len(xrts))
+	for i := range xrts {
+		points[i] = PointResponse{Time: xrts[i].FetchedAt, Rate: xrts[i].Value, Provider: xrts[i].Provider}
+	}
+
+	return &HistoryResponse{Base: pair.Base, Quote: pair.Quote, Points: points}
+}
+
+// CandlesResponse is a response for candles of rates.
+type CandlesResponse struct {
+	Base     string           `json:"base"`
+	Quote    string           `json:"quote"`
+	Interval string           `json:"interval"`
+	Candles  []CandleResponse `json:"candles"`
+}
+
+// CandleResponse is a single open/high/low/close candle.
+type CandleResponse struct {
+	Start time.Time `json:"start"`
+	Open  float64   `json:"open"`
+	High  float64   `json:"high"`
+	Low   float64   `json:"low"`
+	Close float64   `json:"close"`
+	Count int       `json:"count"`
+}
+
+func NewCandlesResponse(pair CurrencyPair, interval time.Duration, cndls []Candle) *CandlesResponse {
+	resp := make([]CandleResponse, len(cndls))
+	for i, c := range cndls {
+		resp[i] = CandleResponse{Start: c.Start, Open: c.Open, High: c.High, Low: c.Low, Close: c.Close, Count: c.Count}
+	}
+
+	return &CandlesResponse{Base: pair.Base, Quote: pair.Quote, Interval: interval.String(), Candles: resp}
+}
+
+// ConvertResponse is a response for currency conversion.
+// Amounts are decimal strings to be passed without losing precision.
+type ConvertResponse struct {
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Amount    string  `json:"amount"`
+	Rate      float64 `json:"rate"`
+	Result    string  `json:"result"`
+	Precision int     `json:"precision"`
+	Rounding  string  `json:"rounding"`
+}
+
+func NewConvertResponse(pair CurrencyPair, amount string, conv *Conversion) *ConvertResponse {
+	return &ConvertResponse{
+		From:      pair.Base,
+		To:        pair.Quote,
+		Amount:    amount,
+		Rate:      conv.Rate.Value,
+		Result:    conv.Result.FloatString(conv.Precision),
+		Precision: conv.Precision,
+		Rounding:  string(conv.Rounding),
+	}
+}
+
+// ConsensusHandler structure for handling consensus rate requests.
+type ConsensusHandler struct {
+	cons ConsensusGetter
+	reg  *currency.Registry
+}
+
+// NewConsensusHandler creates a new ConsensusHandler instance.
+func NewConsensusHandler(cons ConsensusGetter, reg *currency.Registry) ConsensusHandler {
+	return ConsensusHandler{cons: cons, reg: reg}
+}
+
+// Consensus handles the HTTP request for the consensus rate.
+func (h *ConsensusHandler) Consensus(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	pair := NewCurrencyPair(
+		web.FromQuery(req, "base"),
+		web.FromQuery(req, "quote"),
+	)
+
+	if err := pair.ValidateWith(h.reg); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	cons, err := h.cons.GetConsensus(ctx, pair)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return web.NewRequestError(err, http.StatusRequestTimeout)
+		case errors.Is(err, ErrNoConsensus):
+			return web.NewRequestError(err, http.StatusServiceUnavailable)
+		}
+
+		return err
+	}
+
+	return web.Respond(ctx, rw, NewConsensusResponse(cons), http.StatusOK)
+}
+
+// HistoryHandler structure for handling history of rates requests.
+type HistoryHandler struct {
+	hist HistoryGetter
+	reg  *currency.Registry
+}
+
+// NewHistoryHandler creates a new HistoryHandler instance.
+func NewHistoryHandler(hist HistoryGetter, reg *currency.Registry) HistoryHandler {
+	return HistoryHandler{hist: hist, reg: reg}
+}
+
+// History handles the HTTP request for the time series of rates.
+// The time range defaults to the last 24 hours.
+func (h *HistoryHandler) History(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	pair := NewCurrencyPair(
+		web.FromQuery(req, "base"),
+		web.FromQuery(req, "quote"),
+	)
+
+	if err := pair.ValidateWith(h.reg); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	from, to, err := parseRange(req)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	var interval time.Duration
+
+	if val := web.FromQuery(req, "interval"); val != "" {
+		if interval, err = ParseInterval(val); err != nil || interval < 0 {
+			return web.NewRequestError(fmt.Errorf("%w: interval %q", ErrInvalidRange, val), http.StatusBadRequest)
+		}
+	}
+
+	xrts, err := h.hist.Range(ctx, pair, from, to, interval)
+	if err != nil {
+		return err
+	}
+
+	return web.Respond(ctx, rw, NewHistoryResponse(pair, xrts), http.StatusOK)
+}
+
+// CandleHandler structure for handling candles requests.
+type CandleHandler struct {
+	cndl CandleGetter
+	reg  *currency.Registry
+}
+
+// NewCandleHandler creates a new CandleHandler instance.
+func NewCandleHandler(cndl CandleGetter, reg *currency.Registry) CandleHandler {
+	return CandleHandler{cndl: cndl, reg: reg}
+}
+
+// Candles handles the HTTP request for the open/high/low/close candles of rates.
+// The time range defaults to the last 24 hours.
+func (h *CandleHandler) Candles(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	pair := NewCurrencyPair(
+		web.FromQuery(req, "base"),
+		web.FromQuery(req, "quote"),
+	)
+
+	if err := pair.ValidateWith(h.reg); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	from, to, err := parseRange(req)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	interval, err := ParseInterval(web.FromQuery(req, "interval"))
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	cndls, err := h.cndl.Range(ctx, pair, interval, from, to)
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedInterval) {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		return err
+	}
+
+	return web.Respond(ctx, rw, NewCandlesResponse(pair, interval, cndls), http.StatusOK)
+}
+
+// ConvertHandler structure for handling currency conversion requests.
+type ConvertHandler struct {
+	conv ConversionGetter
+	reg  *currency.Registry
+}
+
+// NewConvertHandler creates a new ConvertHandler instance.
+func NewConvertHandler(conv ConversionGetter, reg *currency.Registry) ConvertHandler {
+	return ConvertHandler{conv: conv, reg: reg}
+}
+
+// Convert handles the HTTP request for converting an amount between currencies.
+// The rounding mode defaults to RoundHalfUp.
+func (h *ConvertHandler) Convert(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	pair := NewCurrencyPair(
+		web.FromQuery(req, "from"),
+		web.FromQuery(req, "to"),
+	)
+
+	if err := pair.ValidateWith(h.reg); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	val := web.FromQuery(req, "amount")
+
+	amount, err := ParseAmount(val)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	mode := RoundHalfUp
+	if val := web.FromQuery(req, "rounding"); val != "" {
+		mode = Rounding(val)
+	}
+
+	if err := mode.Validate(); err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	conv, err := h.conv.Convert(ctx, pair, amount, mode)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return web.NewRequestError(err, http.StatusRequestTimeout)
+		}
+
+		return err
+	}
+
+	return web.Respond(ctx, rw, NewConvertResponse(pair, val, conv), http.StatusOK)
+}
+
+// BatchRequest is a request for the exchange rates of many currency pairs like "BTC/UAH".
+type BatchRequest struct {
+	Pairs []string `json:"pairs"`
+}
+
+// BatchResponse is a response for the exchange rates of many currency pairs.
+type BatchResponse struct {
+	Rates []PairResponse `json:"rates"`
+}
+
+// PairResponse is the outcome for a single currency pair, either the rate or the error is set.
+type PairResponse struct {
+	Pair   string    `json:"pair"`
+	Rate   *Response `json:"rate,omitempty"`
+	Status int       `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// NewPairResponse creates a new PairResponse instance hiding the details of internal errors.
+func NewPairResponse(pair string, xrt *ExchangeRate, err error) PairResponse {
+	switch {
+	case err == nil:
+		return PairResponse{Pair: pair, Rate: NewResponse(xrt), Status: http.StatusOK}
+	case errors.Is(err, ErrInvalidCurrency):
+		return PairResponse{Pair: pair, Status: http.StatusBadRequest, Error: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return PairResponse{Pair: pair, Status: http.StatusRequestTimeout, Error: http.StatusText(http.StatusRequestTimeout)}
+	default:
+		return PairResponse{Pair: pair, Status: http.StatusInternalServerError, Error: http.StatusText(http.StatusInternalServerError)}
+	}
+}
+
+// BatchHandler structure for handling batch rate requests.
+type BatchHandler struct {
+	batch BatchGetter
+	reg   *currency.Registry
+}
+
+// NewBatchHandler creates a new BatchHandler instance.
+func NewBatchHandler(batch BatchGetter, reg *currency.Registry) BatchHandler {
+	return BatchHandler{batch: batch, reg: reg}
+}
+
+// Rates handles the HTTP request for the rates of many currency pairs.
+// The pairs are given either in the JSON body or comma-separated in the "pairs" query parameter.
+// Every pair gets its own status, so the response is successful even when some pairs fail.
+func (h *BatchHandler) Rates(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var request BatchRequest
+
+	if req.Method == http.MethodPost {
+		if err := web.DecodeBody(req.Body, &request); err != nil {
+			return err
+		}
+	} else if val := web.FromQuery(req, "pairs"); val != "" {
+		request.Pairs = strings.Split(val, ",")
+	}
+
+	if len(request.Pairs) == 0 {
+		return web.NewRequestError(fmt.Errorf("%w: no pairs given", ErrInvalidCurrency), http.StatusBadRequest)
+	}
+
+	resp := make([]PairResponse, len(request.Pairs))
+	pairs := make([]CurrencyPair, 0, len(request.Pairs))
+	index := make([]int, 0, len(request.Pairs))
+
+	for i, val := range request.Pairs {
+		pair, err := ParseCurrencyPair(val)
+		if err == nil {
+			err = pair.ValidateWith(h.reg)
+		}
+
+		if err != nil {
+			resp[i] = NewPairResponse(val, nil, err)
+			continue
+		}
+
+		pairs = append(pairs, pair)
+		index = append(index, i)
+	}
+
+	results, err := h.batch.GetExchangeRates(ctx, pairs...)
+	if err != nil {
+		if errors.Is(err, ErrTooManyPairs) {
+			return web.NewRequestError(err, http.StatusBadRequest)
+		}
+
+		return err
+	}
+
+	for j, res := range results {
+		i := index[j]
+		resp[i] = NewPairResponse(request.Pairs[i], res.ExchangeRate, res.Err)
+	}
+
+	return web.Respond(ctx, rw, &BatchResponse{Rates: resp}, http.StatusOK)
+}
+
+// TickResponse is a single exchange rate update pushed to the client.
+type TickResponse struct {
+	Base     string    `json:"base"`
+	Quote    string    `json:"quote"`
+	Rate     float64   `json:"rate"`
+	Provider string    `json:"provider,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+func NewTickResponse(xrt ExchangeRate) TickResponse {
+	return TickResponse{
+		Base:     xrt.Pair.Base,
+		Quote:    xrt.Pair.Quote,
+		Rate:     xrt.Value,
+		Provider: xrt.Provider,
+		Time:     xrt.FetchedAt,
+	}
+}
+
+// StreamHandler structure for handling the streams of exchange rate updates.
+type StreamHandler struct {
+	feed FeedWatcher
+	hist HistoryGetter
+	reg  *currency.Registry
+	cfg  StreamConfig
+}
+
+// NewStreamHandler creates a new StreamHandler instance.
+func NewStreamHandler(feed FeedWatcher, hist HistoryGetter, reg *currency.Registry, cfg StreamConfig) StreamHandler {
+	const defaultHeartbeat = 15 * time.Second
+
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = defaultHeartbeat
+	}
+
+	return StreamHandler{feed: feed, hist: hist, reg: reg, cfg: cfg}
+}
+
+// Stream handles the HTTP request for the server-sent events of the fetched rates of the comma-separated "pairs".
+// Event ids are the fetching times, so the client reconnecting with Last-Event-ID gets the missed rates from the history.
+func (h *StreamHandler) Stream(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	pairs, err := parsePairs(h.reg, web.FromQuery(req, "pairs"))
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	// Watching starts before the history is read, so no rate falls in between.
+	w := h.feed.Watch(h.cfg.Buffer, pairs...)
+	defer w.Close()
+
+	missed, err := h.missed(ctx, req.Header.Get("Last-Event-ID"), pairs)
+	if err != nil {
+		return err
+	}
+
+	stream, err := web.NewEventStream(ctx, rw)
+	if err != nil {
+		return err
+	}
+
+	sent := make(map[CurrencyPair]time.Time, len(pairs))
+
+	send := func(xrt ExchangeRate) error {
+		if !xrt.FetchedAt.After(sent[xrt.Pair]) {
+			return nil
+		}
+
+		sent[xrt.Pair] = xrt.FetchedAt
+
+		return stream.Send(strconv.FormatInt(xrt.FetchedAt.UnixNano(), 10), "rate", NewTickResponse(xrt))
+	}
+
+	for i := range missed {
+		if err := send(missed[i]); err != nil {
+			return err
+		}
+	}
+
+	heartbeat := time.NewTicker(h.cfg.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-stream.Done():
+			return nil
+
+		case <-heartbeat.C:
+			if err := stream.Comment("heartbeat"); err != nil {
+				return err
+			}
+
+		case xrt := <-w.Ticks():
+			if err := send(xrt); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// missed returns the rates of the pairs fetched after the last event id in chronological order.
+func (h *StreamHandler) missed(ctx context.Context, lastID string, pairs []CurrencyPair) ([]ExchangeRate, error) {
+	nano, err := strconv.ParseInt(lastID, 10, 64)
+	if lastID == "" || err != nil {
+		return nil, nil
+	}
+
+	from, to := time.Unix(0, nano+1), time.Now()
+
+	var missed []ExchangeRate
+
+	for i := range pairs {
+		xrts, err := h.hist.Range(ctx, pairs[i], from, to, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		missed = append(missed, xrts...)
+	}
+
+	sort.SliceStable(missed, func(i, j int) bool { return missed[i].FetchedAt.Before(missed[j].FetchedAt) })
+
+	return missed, nil
+}
+
+// parsePairs parses the comma-separated currency pairs like "BTC/UAH,ETH/USD" known to the currency registry.
+func parsePairs(reg *currency.Registry, val string) ([]CurrencyPair, error) {
+	if val == "" {
+		return nil, fmt.Errorf("%w: no pairs given", ErrInvalidCurrency)
+	}
+
+	list := strings.Split(val, ",")
+	pairs := make([]CurrencyPair, len(list))
+
+	for i := range list {
+		pair, err := ParseCurrencyPair(list[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if err := pair.ValidateWith(reg); err != nil {
+			return nil, err
+		}
+
+		pairs[i] = pair
+	}
+
+	return pairs, nil
+}
+
+// Socket message types.
+const (
+	MessageSubscribe   = "subscribe"
+	MessageUnsubscribe = "unsubscribe"
+	MessageTick        = "tick"
+	MessageError       = "error"
+)
+
+var ErrInvalidMessage = errors.New("invalid message")
+
+// SocketMessage is a message of the WebSocket protocol.
+// Clients send subscribe and unsubscribe messages with pairs like "BTC/UAH",
+// the server acknowledges them with all pairs watched and pushes tick and error messages.
+type SocketMessage struct {
+	Type  string        `json:"type"`
+	Pairs []string      `json:"pairs,omitempty"`
+	Tick  *TickResponse `json:"tick,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+// SocketHandler structure for handling WebSocket connections of exchange rate updates.
+type SocketHandler struct {
+	feed    FeedWatcher
+	reg     *currency.Registry
+	cfg     StreamConfig
+	origins []string
+}
+
+// NewSocketHandler creates a new SocketHandler instance accepting the browser connections from the origins.
+func NewSocketHandler(feed FeedWatcher, reg *currency.Registry, cfg StreamConfig, origins ...string) SocketHandler {
+	const defaultHeartbeat = 15 * time.Second
+
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = defaultHeartbeat
+	}
+
+	return SocketHandler{feed: feed, reg: reg, cfg: cfg, origins: origins}
+}
+
+// Socket handles the WebSocket connection the client subscribes to and unsubscribes from the pairs with.
+// The server pings the client every heartbeat and drops the connection missing two of them.
+// Ticks are dropped for a slow client, so it never blocks the bus, and the client is told how many were lost.
+func (h *SocketHandler) Socket(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ws, err := web.Upgrade(rw, req, h.origins...)
+	if err != nil {
+		return err
+	}
+
+	defer ws.Close()
+
+	const missedPings = 2
+
+	ws.SetReadTimeout(missedPings * h.cfg.Heartbeat)
+
+	w := h.feed.Watch(h.cfg.Buffer)
+	defer w.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	replies := make(chan SocketMessage)
+	gone := make(chan error, 1)
+
+	go func() {
+		for {
+			data, err := ws.ReadMessage()
+			if err != nil {
+				gone <- err
+				return
+			}
+
+			select {
+			case replies <- h.handleMessage(w, data):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(h.cfg.Heartbeat)
+	defer ping.Stop()
+
+	var dropped int
+
+	for {
+		var msg SocketMessage
+
+		select {
+		case <-web.Closing(ctx):
+			return ws.CloseWith(web.CloseGoingAway, "server shutdown")
+
+		case <-gone:
+			// The connection is already closed or broken, there is nobody to tell about it.
+			return nil
+
+		case <-ping.C:
+			if err := ws.Ping(); err != nil {
+				return nil
+			}
+
+			continue
+
+		case msg = <-replies:
+
+		case xrt := <-w.Ticks():
+			tick := NewTickResponse(xrt)
+			msg = SocketMessage{Type: MessageTick, Tick: &tick}
+		}
+
+		if n := w.Dropped(); n > dropped {
+			lost := SocketMessage{Type: MessageError, Error: fmt.Sprintf("%d ticks dropped, client is too slow", n-dropped)}
+			dropped = n
+
+			if err := ws.WriteJSON(lost); err != nil {
+				return nil
+			}
+		}
+
+		if err := ws.WriteJSON(msg); err != nil {
+			return nil
+		}
+	}
+}
+
+// handleMessage applies the client message to the watcher and returns the reply.
+func (h *SocketHandler) handleMessage(w *Watcher, data []byte) SocketMessage {
+	var msg SocketMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return SocketMessage{Type: MessageError, Error: fmt.Errorf("%w: %w", ErrInvalidMessage, err).Error()}
+	}
+
+	if msg.Type != MessageSubscribe && msg.Type != MessageUnsubscribe {
+		return SocketMessage{Type: MessageError, Error: fmt.Sprintf("%s: unknown type %q", ErrInvalidMessage, msg.Type)}
+	}
+
+	pairs, err := parsePairs(h.reg, strings.Join(msg.Pairs, ","))
+	if err != nil {
+		return SocketMessage{Type: MessageError, Error: err.Error()}
+	}
+
+	if msg.Type == MessageSubscribe {
+		w.Add(pairs...)
+	} else {
+		w.Remove(pairs...)
+	}
+
+	watched := w.Pairs()
+
+	reply := SocketMessage{Type: msg.Type, Pairs: make([]string, len(watched))}
+	for i := range watched {
+		reply.Pairs[i] = watched[i].Base + "/" + watched[i].Quote
+	}
+
+	sort.Strings(reply.Pairs)
+
+	return reply
+}
+
+// BreakersResponse is a response for the status of the provider circuit breakers.
+type BreakersResponse struct {
+	Breakers []BreakerResponse `json:"breakers"`
+}
+
+// BreakerResponse is the status of a single provider circuit breaker.
+type BreakerResponse struct {
+	Provider  string     `json:"provider"`
+	State     string     `json:"state"`
+	Failures  int        `json:"failures"`
+	OpenedAt  *time.Time `json:"opened_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+func NewBreakersResponse(list []BreakerStatus) *BreakersResponse {
+	resp := make([]BreakerResponse, len(list))
+	for i, st := range list {
+		resp[i] = BreakerResponse{Provider: st.Provider, State: string(st.State), Failures: st.Failures, LastError: st.LastError}
+		if !st.OpenedAt.IsZero() {
+			resp[i].OpenedAt = &list[i].OpenedAt
+		}
+	}
+
+	return &BreakersResponse{Breakers: resp}
+}
+
+// BreakerHandler structure for handling provider circuit breakers requests.
+type BreakerHandler struct {
+	brk BreakerStatuser
+}
+
+// NewBreakerHandler creates a new BreakerHandler instance.
+func NewBreakerHandler(brk BreakerStatuser) BreakerHandler {
+	return BreakerHandler{brk: brk}
+}
+
+// Breakers handles the HTTP request for the status of the provider circuit breakers.
+func (h *BreakerHandler) Breakers(ctx context.Context, rw http.ResponseWriter, _ *http.Request) error {
+	return web.Respond(ctx, rw, NewBreakersResponse(h.brk.Status()), http.StatusOK)
+}
+
+// RankingResponse is a response for the current ranking of the providers.
+type RankingResponse struct {
+	Providers []ProviderRankResponse `json:"providers"`
+}
+
+// ProviderRankResponse is the rolling statistics of a single provider.
+type ProviderRankResponse struct {
+	Rank        int     `json:"rank"`
+	Provider    string  `json:"provider"`
+	Calls       int     `json:"calls"`
+	Failures    int     `json:"failures"`
+	SuccessRate float64 `json:"success_rate"`
+	LatencyMS   float64 `json:"latency_ms"`
+}
+
+// NewRankingResponse creates a new RankingResponse instance.
+func NewRankingResponse(list []ProviderStats) *RankingResponse {
+	resp := make([]ProviderRankResponse, len(list))
+	for i, st := range list {
+		resp[i] = ProviderRankResponse{
+			Rank:        i + 1,
+			Provider:    st.Provider,
+			Calls:       st.Calls,
+			Failures:    st.Failures,
+			SuccessRate: st.SuccessRate,
+			LatencyMS:   float64(st.Latency) / float64(time.Millisecond),
+		}
+	}
+
+	return &RankingResponse{Providers: resp}
+}
+
+// RankingHandler structure for handling provider ranking requests.
+type RankingHandler struct {
+	rank RankingStatuser
+}
+
+// NewRankingHandler creates a new RankingHandler instance.
+func NewRankingHandler(rank RankingStatuser) RankingHandler {
+	return RankingHandler{rank: rank}
+}
+
+// Ranking handles the HTTP request for the current ranking of the providers.
+func (h *RankingHandler) Ranking(ctx context.Context, rw http.ResponseWriter, _ *http.Request) error {
+	return web.Respond(ctx, rw, NewRankingResponse(h.rank.Rank()), http.StatusOK)
+}
+
+// ProvidersResponse is a response for the statistics of the provider calls.
+type ProvidersResponse struct {
+	Providers []ProviderResponseStats `json:"providers"`
+}
+
+// ProviderResponseStats is the statistics of a single provider.
+type ProviderResponseStats struct {
+	Provider     string     `json:"provider"`
+	Requests     int        `json:"requests"`
+	Successes    int        `json:"successes"`
+	Failures     int        `json:"failures"`
+	Skipped      int        `json:"skipped"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+	LastSuccess  *time.Time `json:"last_success,omitempty"`
+	AvgLatencyMS float64    `json:"avg_latency_ms"`
+	P95LatencyMS float64    `json:"p95_latency_ms"`
+	LastRate     *float64   `json:"last_rate,omitempty"`
+	LastPair     string     `json:"last_pair,omitempty"`
+}
+
+// NewProvidersResponse creates a new ProvidersResponse instance.
+func NewProvidersResponse(list []ProviderStatus) *ProvidersResponse {
+	resp := make([]ProviderResponseStats, len(list))
+	for i, st := range list {
+		resp[i] = ProviderResponseStats{
+			Provider:     st.Provider,
+			Requests:     st.Requests,
+			Successes:    st.Successes,
+			Failures:     st.Failures,
+			Skipped:      st.Skipped,
+			LastError:    st.LastError,
+			AvgLatencyMS: float64(st.AvgLatency) / float64(time.Millisecond),
+			P95LatencyMS: float64(st.P95Latency) / float64(time.Millisecond),
+		}
+
+		if !st.LastErrorAt.IsZero() {
+			resp[i].LastErrorAt = &list[i].LastErrorAt
+		}
+
+		if !st.LastSuccess.IsZero() {
+			resp[i].LastSuccess = &list[i].LastSuccess
+		}
+
+		if st.LastRate != nil {
+			resp[i].LastRate = &list[i].LastRate.Value
+			resp[i].LastPair = st.LastRate.Pair.Base + "/" + st.LastRate.Pair.Quote
+		}
+	}
+
+	return &ProvidersResponse{Providers: resp}
+}
+
+// MonitorHandler structure for handling provider statistics requests.
+type MonitorHandler struct {
+	mon ProviderStatuser
+}
+
+// NewMonitorHandler creates a new MonitorHandler instance.
+func NewMonitorHandler(mon ProviderStatuser) MonitorHandler {
+	return MonitorHandler{mon: mon}
+}
+
+// Providers handles the HTTP request for the statistics of the provider calls.
+func (h *MonitorHandler) Providers(ctx context.Context, rw http.ResponseWriter, _ *http.Request) error {
+	return web.Respond(ctx, rw, NewProvidersResponse(h.mon.Status()), http.StatusOK)
+}
+
+// parseRange parses RFC 3339 "from" and "to" query parameters.
+// The range defaults to the last 24 hours.
+func parseRange(req *http.Request) (from, to time.Time, err error) {
+	const defaultRange = 24 * time.Hour
+
+	to, err = parseTime(web.FromQuery(req, "to"), time.Now())
+	if err != nil {
+		return from, to, err
+	}
+
+	from, err = parseTime(web.FromQuery(req, "from"), to.Add(-defaultRange))
+	if err != nil {
+		return from, to, err
+	}
+
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("%w: from %s is not before to %s", ErrInvalidRange, from, to)
+	}
+
+	return from, to, nil
+}
+
+// parseTime parses RFC 3339 time returning the fallback for an empty value.
+func parseTime(val string, fallback time.Time) (time.Time, error) {
+	if val == "" {
+		return fallback, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalidRange, err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,212 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+// HistoryConfig defines how long the fetched exchange rates are kept in the history and its candles.
+type HistoryConfig struct {
+	// Retention is how long the exchange rates are kept, zero keeps them forever.
+	Retention time.Duration
+	// Interval is the period the expired exchange rates are dropped from the memory and the store at.
+	Interval time.Duration
+}
+
+// expired reports whether the exchange rate fetched at the time is beyond the retention at now.
+func (cfg HistoryConfig) expired(fetched, now time.Time) bool {
+	return cfg.Retention > 0 && now.Sub(fetched) > cfg.Retention
+}
+
+// HistoryStorer defines the interface for persisting and loading exchange rate observations.
+type HistoryStorer interface {
+	Append(...ExchangeRate) error
+	Replace(...ExchangeRate) error
+	FetchAll() ([]ExchangeRate, error)
+}
+
+// History keeps every fetched exchange rate indexed by currency pair and persists it to the store.
+type History struct {
+	store HistoryStorer
+	cfg   HistoryConfig
+
+	mu    sync.RWMutex
+	items map[CurrencyPair][]ExchangeRate
+	// stale is set when the store holds the exchange rates dropped from the memory.
+	stale bool
+}
+
+// NewHistory loads the stored exchange rates within the retention and subscribes History to the fetched exchange rates.
+func NewHistory(bus *event.Bus, store HistoryStorer, cfg HistoryConfig) (*History, error) {
+	xrts, err := store.FetchAll()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("loading history: %w", err)
+	}
+
+	h := History{
+		store: store,
+		cfg:   cfg,
+		items: make(map[CurrencyPair][]ExchangeRate),
+	}
+
+	now := time.Now()
+
+	for i := range xrts {
+		if cfg.expired(xrts[i].FetchedAt, now) {
+			h.stale = true
+			continue
+		}
+
+		h.add(xrts[i])
+	}
+
+	bus.Subscribe(event.New(EventSource, EventKindFetched, nil), h.RecordExchangeRate)
+
+	return &h, nil
+}
+
+// RecordExchangeRate is an event listener that stores every fetched exchange rate.
+func (h *History) RecordExchangeRate(ctx context.Context, e event.Event) error {
+	resp, ok := e.Payload.(ProviderResponse)
+	if !ok {
+		return fmt.Errorf("%w: unexpected payload, expected ProviderResponse: %T", ErrInvalidEvent, e.Payload)
+	}
+
+	if resp.ExchangeRate == nil {
+		return fmt.Errorf("%w: missing exchange rate from %s", ErrInvalidEvent, resp.Provider)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// The store is appended under the lock, so the compaction does not lose the exchange rate.
+	if err := h.store.Append(*resp.ExchangeRate); err != nil {
+		return fmt.Errorf("recording exchange rate: %w", err)
+	}
+
+	h.insert(*resp.ExchangeRate)
+
+	return nil
+}
+
+// Run drops the expired exchange rates every interval until the context is done.
+func (h *History) Run(ctx context.Context) {
+	if h.cfg.Retention <= 0 || h.cfg.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(h.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Failures are retried on the next tick, so they are not reported anywhere else.
+		_ = h.Compact(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Compact drops the exchange rates beyond the retention at the time and rewrites the store with the rest.
+func (h *History) Compact(at time.Time) error {
+	if h.cfg.Retention <= 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var kept []ExchangeRate
+
+	for pair, items := range h.items {
+		i := sort.Search(len(items), func(i int) bool { return !h.cfg.expired(items[i].FetchedAt, at) })
+		if i == 0 {
+			kept = append(kept, items...)
+			continue
+		}
+
+		h.stale = true
+
+		if i == len(items) {
+			delete(h.items, pair)
+			continue
+		}
+
+		// The series is copied, so the expired exchange rates are released.
+		h.items[pair] = append([]ExchangeRate(nil), items[i:]...)
+		kept = append(kept, h.items[pair]...)
+	}
+
+	if !h.stale {
+		return nil
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].FetchedAt.Before(kept[j].FetchedAt) })
+
+	if err := h.store.Replace(kept...); err != nil {
+		return fmt.Errorf("compacting history: %w", err)
+	}
+
+	h.stale = false
+
+	return nil
+}
+
+// Range returns the exchange rates of the pair fetched within [from, to) in chronological order.
+// If interval is positive, only the last exchange rate of every interval is returned.
+func (h *History) Range(_ context.Context, pair CurrencyPair, from, to time.Time, interval time.Duration) ([]ExchangeRate, error) {
+	if err := pair.Validate(); err != nil {
+		return nil, err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	items := h.items[pair]
+
+	lo := sort.Search(len(items), func(i int) bool { return !items[i].FetchedAt.Before(from) })
+	hi := sort.Search(len(items), func(i int) bool { return !items[i].FetchedAt.Before(to) })
+
+	series := make([]ExchangeRate, 0, hi-lo)
+
+	for i := lo; i < hi; i++ {
+		if interval > 0 && i+1 < hi && items[i].FetchedAt.Truncate(interval).Equal(items[i+1].FetchedAt.Truncate(interval)) {
+			continue
+		}
+
+		series = append(series, items[i])
+	}
+
+	return series, nil
+}
+
+// add inserts the exchange rate keeping the pair series sorted by the fetching time.
+func (h *History) add(xrt ExchangeRate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.insert(xrt)
+}
+
+// insert inserts the exchange rate into the pair series, the caller holds the lock.
+func (h *History) insert(xrt ExchangeRate) {
+	items := h.items[xrt.Pair]
+
+	i := sort.Search(len(items), func(i int) bool { return items[i].FetchedAt.After(xrt.FetchedAt) })
+
+	items = append(items, ExchangeRate{})
+	copy(items[i+1:], items[i:])
+	items[i] = xrt
+
+	h.items[xrt.Pair] = items
+}
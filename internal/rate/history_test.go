@@ -0,0 +1,127 @@
+package rate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/filestore"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	log := logger.New(logger.WithConsoleCore(logger.LevelDebug))
+	bus := event.NewBus(log)
+	pair := rate.NewCurrencyPair("BTC", "UAH")
+	start := time.Date(2023, 7, 10, 12, 0, 0, 0, time.UTC)
+
+	observe := func(val float64, after time.Duration) event.Event {
+		return event.New(rate.EventSource, rate.EventKindFetched, rate.ProviderResponse{
+			Provider:     "TestProvider",
+			ExchangeRate: &rate.ExchangeRate{Value: val, Pair: pair, FetchedAt: start.Add(after)},
+		})
+	}
+
+	tests := map[string]struct {
+		events   []event.Event
+		from, to time.Time
+		interval time.Duration
+		want     []float64
+		wantErr  error
+	}{
+		"all_in_range": {
+			events: []event.Event{observe(3, 3*time.Minute), observe(1, time.Minute), observe(2, 2*time.Minute)},
+			from:   start,
+			to:     start.Add(time.Hour),
+			want:   []float64{1, 2, 3},
+		},
+
+		"out_of_range": {
+			events: []event.Event{observe(1, time.Minute), observe(2, 2*time.Minute), observe(3, 3*time.Minute)},
+			from:   start.Add(2 * time.Minute),
+			to:     start.Add(3 * time.Minute),
+			want:   []float64{2},
+		},
+
+		"downsampled": {
+			events:   []event.Event{observe(1, 10*time.Second), observe(2, 20*time.Second), observe(3, 70*time.Second)},
+			from:     start,
+			to:       start.Add(time.Hour),
+			interval: time.Minute,
+			want:     []float64{2, 3},
+		},
+
+		"invalid_payload": {
+			events:  []event.Event{event.New(rate.EventSource, rate.EventKindFetched, "invalid_payload")},
+			wantErr: rate.ErrInvalidEvent,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := filestore.NewAppendLog[rate.ExchangeRate](t.TempDir())
+
+			hist, err := rate.NewHistory(bus, store, rate.HistoryConfig{})
+			require.NoError(t, err)
+
+			for _, e := range tc.events {
+				err := hist.RecordExchangeRate(context.Background(), e)
+				require.ErrorIs(t, err, tc.wantErr)
+			}
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			got, err := hist.Range(context.Background(), pair, tc.from, tc.to, tc.interval)
+			require.NoError(t, err)
+
+			vals := make([]float64, len(got))
+			for i := range got {
+				vals[i] = got[i].Value
+			}
+
+			require.Equal(t, tc.want, vals)
+
+			reloaded, err := rate.NewHistory(bus, store, rate.HistoryConfig{})
+			require.NoError(t, err)
+
+			got, err = reloaded.Range(context.Background(), pair, tc.from, tc.to, tc.interval)
+			require.NoError(t, err)
+			require.Len(t, got, len(tc.want))
+		})
+	}
+}
+
+func TestHistoryCompact(t *testing.T) {
+	bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+	pair := rate.NewCurrencyPair("BTC", "UAH")
+	start := time.Date(2023, 7, 10, 12, 0, 0, 0, time.UTC)
+	store := filestore.NewAppendLog[rate.ExchangeRate](t.TempDir())
+
+	hist, err := rate.NewHistory(bus, store, rate.HistoryConfig{Retention: 90 * time.Minute})
+	require.NoError(t, err)
+
+	for i, after := range []time.Duration{0, time.Hour, 2 * time.Hour} {
+		e := event.New(rate.EventSource, rate.EventKindFetched, rate.ProviderResponse{
+			Provider:     "TestProvider",
+			ExchangeRate: &rate.ExchangeRate{Value: float64(i + 1), Pair: pair, FetchedAt: start.Add(after)},
+		})
+		require.NoError(t, hist.RecordExchangeRate(context.Background(), e))
+	}
+
+	require.NoError(t, hist.Compact(start.Add(2*time.Hour+time.Minute)))
+
+	got, err := hist.Range(context.Background(), pair, start, start.Add(24*time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, 2.0, got[0].Value)
+
+	stored, err := store.FetchAll()
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	require.Equal(t, 2.0, stored[0].Value)
+}
@@ -1,14 +1,28 @@
 package rate
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
 )
 
 // ExchangeRate represents exchange rate.
 type ExchangeRate struct {
 	Value float64
 	Pair  CurrencyPair
+	// Bid and Ask are optional and zero when the provider does not quote them.
+	Bid, Ask float64
+	// Provider is the name of the provider that produced the quote.
+	Provider string
+	// Timestamp is the quote time reported by the provider, zero if unknown.
+	Timestamp time.Time
+	// FetchedAt is the time the quote was received from the provider.
+	FetchedAt time.Time
+	// Path lists the currencies the cross rate was computed through, empty for a direct quote.
+	Path []string
 }
 
 // CurrencyPair represents a currency pair.
@@ -19,7 +33,13 @@ type CurrencyPair struct {
 
 // NewExchangeRate creates a new ExchangeRate instance.
 func NewExchangeRate(rate float64, pair CurrencyPair) *ExchangeRate {
-	return &ExchangeRate{Value: rate, Pair: pair}
+	return &ExchangeRate{Value: rate, Pair: pair, FetchedAt: time.Now()}
+}
+
+// clone returns a copy of ExchangeRate, so cached values are not shared with callers.
+func (s *ExchangeRate) clone() *ExchangeRate {
+	xrt := *s
+	return &xrt
 }
 
 // ExchangeRate is implementation of ExchangeRateEvent.
@@ -35,6 +55,18 @@ func NewCurrencyPair(base, quote string) CurrencyPair {
 	}
 }
 
+// ParseCurrencyPair parses a currency pair given like "BTC/UAH" and validates it.
+func ParseCurrencyPair(val string) (CurrencyPair, error) {
+	base, quote, ok := strings.Cut(strings.TrimSpace(val), "/")
+	if !ok {
+		return CurrencyPair{}, fmt.Errorf("%w: %q", ErrInvalidCurrency, val)
+	}
+
+	pair := NewCurrencyPair(strings.TrimSpace(base), strings.TrimSpace(quote))
+
+	return pair, pair.Validate()
+}
+
 // BaseCurrency is implementation of CurrencyPairEvent.
 func (cp CurrencyPair) BaseCurrency() string {
 	return cp.Base
@@ -46,7 +78,6 @@ func (cp CurrencyPair) QuoteCurrency() string {
 }
 
 // Validate validates a CurrencyPair instance.
-// TODO: Improve validation for all possible currency pairs.
 func (cp CurrencyPair) Validate() error {
 	if cp.Base == "" || cp.Quote == "" {
 		return fmt.Errorf("%w: %+v", ErrInvalidCurrency, cp)
@@ -55,4 +86,17 @@ func (cp CurrencyPair) Validate() error {
 	return nil
 }
 
+// ValidateWith validates a CurrencyPair instance against the currency registry.
+func (cp CurrencyPair) ValidateWith(reg *currency.Registry) error {
+	if err := cp.Validate(); err != nil {
+		return err
+	}
+
+	if err := errors.Join(reg.Validate(cp.Base), reg.Validate(cp.Quote)); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCurrency, err)
+	}
+
+	return nil
+}
+
 var _ CurrencyPairEvent = (*CurrencyPair)(nil)
@@ -0,0 +1,168 @@
+package rate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+const (
+	// latencySamples is the number of the latest calls the latency percentile is computed over.
+	latencySamples = 100
+	p95            = 0.95
+)
+
+// ProviderStatus represents the traffic served by the provider since the start.
+type ProviderStatus struct {
+	Provider string
+	// Requests is the number of calls that reached the provider, Skipped ones are not included.
+	Requests  int
+	Successes int
+	Failures  int
+	// Skipped is the number of calls not made for the unsupported pair, open circuit breaker or exhausted quota.
+	Skipped int
+	// LastError is the category of the last failure, see ErrorCategory.
+	LastError   string
+	LastErrorAt time.Time
+	LastSuccess time.Time
+	// AvgLatency is the mean duration of all requests.
+	AvgLatency time.Duration
+	// P95Latency is the 95th percentile of the duration of the latest requests.
+	P95Latency time.Duration
+	// LastRate is the last exchange rate returned by the provider.
+	LastRate *ExchangeRate
+}
+
+// Monitor collects the statistics of the provider calls from the fetched and failed events,
+// so the providers themselves stay unaware of it.
+type Monitor struct {
+	mu    sync.Mutex
+	items map[string]*providerTraffic
+}
+
+type providerTraffic struct {
+	ProviderStatus
+	total   time.Duration
+	samples []time.Duration
+	next    int
+}
+
+// NewMonitor creates a new Monitor instance subscribed to the provider events.
+func NewMonitor(bus *event.Bus) *Monitor {
+	m := Monitor{items: make(map[string]*providerTraffic)}
+
+	bus.Subscribe(event.New(EventSource, EventKindFetched, nil), m.RecordCall)
+	bus.Subscribe(event.New(EventSource, EventKindFailed, nil), m.RecordCall)
+
+	return &m
+}
+
+// RecordCall is an event listener that records the provider call.
+func (m *Monitor) RecordCall(_ context.Context, e event.Event) error {
+	switch resp := e.Payload.(type) {
+	case ProviderResponse:
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		tr := m.traffic(resp.Provider)
+		tr.Successes++
+		tr.LastSuccess = time.Now()
+		tr.LastRate = resp.ExchangeRate
+		tr.measure(resp.Latency)
+
+	case ProviderErrorResponse:
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		tr := m.traffic(resp.Provider)
+		if isSkipped(resp.Err) {
+			tr.Skipped++
+			return nil
+		}
+
+		tr.Failures++
+		tr.LastErrorAt = time.Now()
+
+		if resp.Err != nil {
+			tr.LastError = ErrorCategory(resp.Err)
+		}
+
+		tr.measure(resp.Latency)
+
+	default:
+		return fmt.Errorf("%w: unexpected payload: %T", ErrInvalidEvent, e.Payload)
+	}
+
+	return nil
+}
+
+// Status returns the statistics of all providers seen so far ordered by provider name.
+func (m *Monitor) Status() []ProviderStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]ProviderStatus, 0, len(m.items))
+
+	for _, tr := range m.items {
+		st := tr.ProviderStatus
+		if st.Requests > 0 {
+			st.AvgLatency = tr.total / time.Duration(st.Requests)
+		}
+
+		st.P95Latency = tr.percentile(p95)
+
+		list = append(list, st)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Provider < list[j].Provider })
+
+	return list
+}
+
+// traffic returns the traffic of the provider, it must be called with the lock held.
+func (m *Monitor) traffic(name string) *providerTraffic {
+	tr, ok := m.items[name]
+	if !ok {
+		tr = &providerTraffic{ProviderStatus: ProviderStatus{Provider: name}}
+		m.items[name] = tr
+	}
+
+	return tr
+}
+
+// measure counts the request and keeps its duration in the ring of the latest samples.
+func (tr *providerTraffic) measure(lat time.Duration) {
+	tr.Requests++
+	tr.total += lat
+
+	if len(tr.samples) < latencySamples {
+		tr.samples = append(tr.samples, lat)
+		return
+	}
+
+	tr.samples[tr.next] = lat
+	tr.next = (tr.next + 1) % latencySamples
+}
+
+// percentile returns the q-quantile of the latest samples using the nearest-rank method.
+func (tr *providerTraffic) percentile(q float64) time.Duration {
+	if len(tr.samples) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(tr.samples))
+	copy(sorted, tr.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	idx := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+
+	return sorted[idx]
+}
@@ -0,0 +1,85 @@
+package rate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor(t *testing.T) {
+	xrt := &rate.ExchangeRate{Value: 1.2, Pair: rate.NewCurrencyPair("USD", "EUR")}
+
+	fetched := func(prov string, lat time.Duration) event.Event {
+		return event.New(rate.EventSource, rate.EventKindFetched, rate.ProviderResponse{Provider: prov, ExchangeRate: xrt, Latency: lat})
+	}
+
+	failed := func(prov string, err error, lat time.Duration) event.Event {
+		return event.New(rate.EventSource, rate.EventKindFailed, rate.ProviderErrorResponse{Provider: prov, Err: err, Latency: lat})
+	}
+
+	tests := map[string]struct {
+		events  []event.Event
+		want    []rate.ProviderStatus
+		wantErr error
+	}{
+		"no_calls": {
+			want: []rate.ProviderStatus{},
+		},
+		"successes_and_failures": {
+			events: []event.Event{
+				fetched("b", 10*time.Millisecond),
+				fetched("a", 10*time.Millisecond),
+				failed("a", rate.ErrProviderUnavailable, 30*time.Millisecond),
+				fetched("a", 20*time.Millisecond),
+			},
+			want: []rate.ProviderStatus{
+				{
+					Provider: "a", Requests: 3, Successes: 2, Failures: 1, LastError: "unavailable",
+					AvgLatency: 20 * time.Millisecond, P95Latency: 30 * time.Millisecond, LastRate: xrt,
+				},
+				{
+					Provider: "b", Requests: 1, Successes: 1,
+					AvgLatency: 10 * time.Millisecond, P95Latency: 10 * time.Millisecond, LastRate: xrt,
+				},
+			},
+		},
+		"skipped_not_requested": {
+			events: []event.Event{
+				failed("a", rate.ErrUnsupportedPair, 0),
+				failed("a", rate.ErrCircuitOpen, 0),
+			},
+			want: []rate.ProviderStatus{{Provider: "a", Skipped: 2}},
+		},
+		"invalid_payload": {
+			events:  []event.Event{event.New(rate.EventSource, rate.EventKindFetched, "invalid")},
+			want:    []rate.ProviderStatus{},
+			wantErr: rate.ErrInvalidEvent,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+			mon := rate.NewMonitor(bus)
+
+			for _, e := range tc.events {
+				err := mon.RecordCall(context.Background(), e)
+				require.ErrorIs(t, err, tc.wantErr)
+			}
+
+			got := mon.Status()
+			for i := range got {
+				require.Equal(t, got[i].Successes > 0, !got[i].LastSuccess.IsZero())
+				require.Equal(t, got[i].Failures > 0, !got[i].LastErrorAt.IsZero())
+				got[i].LastSuccess, got[i].LastErrorAt = time.Time{}, time.Time{}
+			}
+
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
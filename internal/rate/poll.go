@@ -0,0 +1,258 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+const (
+	EventKindPolled = "polled"
+	// EventKindTopicsRequested is the request for the currency pairs having subscribers.
+	EventKindTopicsRequested = "topics_requested"
+)
+
+// PollConfig defines which pairs are refreshed in background and how often.
+type PollConfig struct {
+	// Interval is the duration every pair is refreshed within, zero disables polling.
+	Interval time.Duration
+	// Pairs lists the pairs like "BTC/UAH" refreshed besides the ones having subscribers.
+	Pairs []string
+	// Reserve is the fraction of the provider limits like 0.2 polling leaves for the on-demand requests.
+	Reserve float64
+}
+
+// CurrencyPairsEvent is an event carrying currency pairs like "BTC/UAH".
+type CurrencyPairsEvent interface {
+	CurrencyPairs() []string
+}
+
+// PairSource returns the currency pairs to be polled.
+type PairSource func(context.Context) ([]CurrencyPair, error)
+
+// Refresher is implemented by the services able to bypass their cache, so the polled rate is always fresh.
+type Refresher interface {
+	Refresh(context.Context, CurrencyPair) (*ExchangeRate, error)
+}
+
+// PollRound represents the data of a finished poll round event.
+type PollRound struct {
+	Pairs   int
+	Fetched int
+	Failed  int
+	// Skipped is the number of pairs left out since the quotas of all providers are exhausted.
+	Skipped int
+	Err     error
+}
+
+// Poller refreshes the exchange rates of the configured pairs and the pairs having subscribers in background.
+// Requests are spread evenly over the interval, so providers are not hit by a burst,
+// and the round is cut short once the quotas of all providers are exhausted.
+// The requests are marked with WithBackground, so the providers keep a reserve of their quotas for on-demand ones.
+// Fetched rates reach the history and the feed through the fetched events as usual.
+type Poller struct {
+	bus     *event.Bus
+	svc     ExchangeRateService
+	cfg     PollConfig
+	pairs   []CurrencyPair
+	sources []PairSource
+}
+
+// NewPoller creates a new Poller instance, the rates are refreshed through Refresher when svc implements it.
+func NewPoller(bus *event.Bus, svc ExchangeRateService, cfg PollConfig, sources ...PairSource) (*Poller, error) {
+	pairs := make([]CurrencyPair, 0, len(cfg.Pairs))
+
+	for _, val := range cfg.Pairs {
+		if val == "" || val == "-" {
+			continue
+		}
+
+		pair, err := ParseCurrencyPair(val)
+		if err != nil {
+			return nil, fmt.Errorf("parsing poll pairs: %w", err)
+		}
+
+		pairs = append(pairs, pair)
+	}
+
+	return &Poller{bus: bus, svc: svc, cfg: cfg, pairs: pairs, sources: sources}, nil
+}
+
+// Run polls until the context is cancelled, it returns once the current request is done.
+func (p *Poller) Run(ctx context.Context) {
+	if p.cfg.Interval <= 0 {
+		return
+	}
+
+	for {
+		start := time.Now()
+
+		round := p.poll(ctx)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		_ = p.bus.Publish(ctx, event.New(EventSource, EventKindPolled, round))
+
+		if !sleep(ctx, p.cfg.Interval-time.Since(start)) {
+			return
+		}
+	}
+}
+
+// poll refreshes every pair once spreading the requests over the interval.
+func (p *Poller) poll(ctx context.Context) PollRound {
+	pairs, err := p.collect(ctx)
+
+	round := PollRound{Pairs: len(pairs), Err: err}
+	if len(pairs) == 0 {
+		return round
+	}
+
+	get := p.svc.GetExchangeRate
+	if r, ok := p.svc.(Refresher); ok {
+		get = r.Refresh
+	}
+
+	step := p.cfg.Interval / time.Duration(len(pairs))
+
+	for i, pair := range pairs {
+		if i > 0 && !sleep(ctx, step) {
+			return round
+		}
+
+		reqCtx, cancel := context.WithTimeout(WithBackground(ctx), defaultTimeout)
+		_, err := get(reqCtx, pair)
+		cancel()
+
+		switch {
+		case err == nil:
+			round.Fetched++
+
+		case errors.Is(err, ErrAllQuotasExhausted):
+			round.Failed++
+			round.Skipped = len(pairs) - i - 1
+
+			return round
+
+		default:
+			round.Failed++
+		}
+	}
+
+	return round
+}
+
+// collect returns the configured pairs and the pairs of the sources without duplicates.
+// A failing source does not stop the round, the rest of the pairs are polled anyway.
+func (p *Poller) collect(ctx context.Context) ([]CurrencyPair, error) {
+	const sourceTimeout = 5 * time.Second
+
+	seen := make(map[CurrencyPair]struct{})
+	pairs := make([]CurrencyPair, 0, len(p.pairs))
+
+	add := func(list []CurrencyPair) {
+		for _, pair := range list {
+			if _, ok := seen[pair]; !ok {
+				seen[pair] = struct{}{}
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+
+	add(p.pairs)
+
+	var errs []error
+
+	for _, src := range p.sources {
+		srcCtx, cancel := context.WithTimeout(ctx, sourceTimeout)
+		list, err := src(srcCtx)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		add(list)
+	}
+
+	return pairs, errors.Join(errs...)
+}
+
+// SubscribedPairs returns the PairSource requesting the currency pairs having subscribers over the bus.
+func SubscribedPairs(bus *event.Bus) PairSource {
+	return func(ctx context.Context) ([]CurrencyPair, error) {
+		e := event.New(EventSource, EventKindTopicsRequested, nil)
+		if err := bus.Publish(ctx, e); err != nil {
+			return nil, fmt.Errorf("publishing topics request: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("requesting subscribed pairs: %w", ctx.Err())
+
+		case resp := <-e.Response:
+			val, ok := resp.Payload.(CurrencyPairsEvent)
+			if !ok {
+				return nil, fmt.Errorf("%w: unexpected payload: %T", ErrInvalidEvent, resp.Payload)
+			}
+
+			var pairs []CurrencyPair
+
+			for _, pair := range val.CurrencyPairs() {
+				// Malformed pairs are left out rather than failing the whole round.
+				if cp, err := ParseCurrencyPair(pair); err == nil {
+					pairs = append(pairs, cp)
+				}
+			}
+
+			return pairs, nil
+		}
+	}
+}
+
+// backgroundKey is the context key of the requests made in background.
+type backgroundKey struct{}
+
+// WithBackground marks the requests made with the context as the background ones.
+func WithBackground(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backgroundKey{}, true)
+}
+
+// IsBackground reports whether the requests made with the context are the background ones.
+func IsBackground(ctx context.Context) bool {
+	bg, _ := ctx.Value(backgroundKey{}).(bool)
+	return bg
+}
+
+// detach returns the context free of the cancellation and values of the context
+// except the background marker, so the quota reserve still applies to the calls outliving their request.
+func detach(ctx context.Context) context.Context {
+	if IsBackground(ctx) {
+		return WithBackground(context.Background())
+	}
+
+	return context.Background()
+}
+
+// sleep waits for the duration, it returns false when the context is cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,186 @@
+package rate_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type pairsPayload []string
+
+func (p pairsPayload) CurrencyPairs() []string { return p }
+
+func TestPollerRun(t *testing.T) {
+	btc, eth := rate.NewCurrencyPair("BTC", "UAH"), rate.NewCurrencyPair("ETH", "USD")
+	errSource := errors.New("source failed")
+
+	tests := map[string]struct {
+		pairs     []string
+		source    rate.PairSource
+		err       error
+		want      rate.PollRound
+		wantPairs []rate.CurrencyPair
+		wantErr   error
+	}{
+		"configured_and_sourced_deduplicated": {
+			pairs: []string{"BTC/UAH"},
+			source: func(context.Context) ([]rate.CurrencyPair, error) {
+				return []rate.CurrencyPair{eth, btc}, nil
+			},
+			want:      rate.PollRound{Pairs: 2, Fetched: 2},
+			wantPairs: []rate.CurrencyPair{btc, eth},
+		},
+		"all_quotas_exhausted_skips_round": {
+			pairs:     []string{"BTC/UAH", "ETH/USD"},
+			err:       errors.Join(rate.ErrAllQuotasExhausted, rate.ErrQuotaExhausted),
+			want:      rate.PollRound{Pairs: 2, Failed: 1, Skipped: 1},
+			wantPairs: []rate.CurrencyPair{btc},
+		},
+		"quota_exhausted_round_continues": {
+			pairs:     []string{"BTC/UAH", "ETH/USD"},
+			err:       rate.ErrQuotaExhausted,
+			want:      rate.PollRound{Pairs: 2, Failed: 2},
+			wantPairs: []rate.CurrencyPair{btc, eth},
+		},
+		"failed_source_configured_polled": {
+			pairs: []string{"BTC/UAH"},
+			source: func(context.Context) ([]rate.CurrencyPair, error) {
+				return nil, errSource
+			},
+			want:      rate.PollRound{Pairs: 1, Fetched: 1},
+			wantPairs: []rate.CurrencyPair{btc},
+			wantErr:   errSource,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+
+			var (
+				mu         sync.Mutex
+				pairs      []rate.CurrencyPair
+				foreground int
+			)
+
+			svc := &mock.ExchangeRateServiceMock{
+				GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+					mu.Lock()
+					pairs = append(pairs, pair)
+					if !rate.IsBackground(ctx) {
+						foreground++
+					}
+					mu.Unlock()
+
+					if tc.err != nil {
+						return nil, tc.err
+					}
+
+					return rate.NewExchangeRate(1, pair), nil
+				},
+			}
+
+			rounds := make(chan rate.PollRound, 1)
+
+			bus.Subscribe(event.New(rate.EventSource, rate.EventKindPolled, nil), func(_ context.Context, e event.Event) error {
+				select {
+				case rounds <- e.Payload.(rate.PollRound):
+				default:
+				}
+
+				return nil
+			})
+
+			var sources []rate.PairSource
+			if tc.source != nil {
+				sources = append(sources, tc.source)
+			}
+
+			poll, err := rate.NewPoller(bus, svc, rate.PollConfig{Interval: 20 * time.Millisecond, Pairs: tc.pairs}, sources...)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+				poll.Run(ctx)
+			}()
+
+			var round rate.PollRound
+
+			select {
+			case round = <-rounds:
+			case <-time.After(time.Second):
+				t.Fatal("poll round timed out")
+			}
+
+			cancel()
+			<-done
+
+			require.ErrorIs(t, round.Err, tc.wantErr)
+			round.Err = nil
+			require.Equal(t, tc.want, round)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			require.Equal(t, tc.wantPairs, pairs[:len(tc.wantPairs)])
+			require.Zero(t, foreground)
+		})
+	}
+}
+
+func TestServiceAllQuotasExhausted(t *testing.T) {
+	tests := map[string]struct {
+		errs    []error
+		wantAll bool
+	}{
+		"all_exhausted":   {errs: []error{rate.ErrQuotaExhausted, rate.ErrQuotaExhausted}, wantAll: true},
+		"first_exhausted": {errs: []error{rate.ErrQuotaExhausted, rate.ErrProviderUnavailable}},
+		"last_exhausted":  {errs: []error{rate.ErrProviderUnavailable, rate.ErrQuotaExhausted}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			provs := make([]rate.ExchangeRateProvider, len(tc.errs))
+
+			for i := range tc.errs {
+				err := tc.errs[i]
+				provs[i] = &mock.ExchangeRateProviderMock{
+					GetExchangeRateFunc: func(context.Context, rate.CurrencyPair) (*rate.ExchangeRate, error) { return nil, err },
+					StringFunc:          func() string { return "Provider" },
+				}
+			}
+
+			bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+
+			_, err := rate.NewService(bus, provs...).GetExchangeRate(context.Background(), rate.NewCurrencyPair("BTC", "UAH"))
+			require.Error(t, err)
+			require.Equal(t, tc.wantAll, errors.Is(err, rate.ErrAllQuotasExhausted))
+		})
+	}
+}
+
+func TestSubscribedPairs(t *testing.T) {
+	bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+
+	bus.Subscribe(event.New(rate.EventSource, rate.EventKindTopicsRequested, nil), func(_ context.Context, e event.Event) error {
+		e.Response <- event.New("subscription", "topics_responded", pairsPayload{"BTC/UAH", "invalid"})
+		return nil
+	})
+
+	pairs, err := rate.SubscribedPairs(bus)(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []rate.CurrencyPair{rate.NewCurrencyPair("BTC", "UAH")}, pairs)
+}
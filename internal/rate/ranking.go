@@ -0,0 +1,243 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+const (
+	RankStatic      = "static"
+	RankLatency     = "latency"
+	RankReliability = "reliability"
+)
+
+var ErrUnknownRanker = errors.New("unknown ranking algorithm")
+
+// RankingConfig defines how the providers are ranked.
+type RankingConfig struct {
+	// Algorithm is one of RankStatic, RankLatency or RankReliability.
+	Algorithm string
+	// Window is the number of the latest calls the statistics are computed over.
+	Window int
+}
+
+// ProviderStats represents rolling statistics of the provider calls.
+type ProviderStats struct {
+	Provider string
+	Calls    int
+	Failures int
+	// SuccessRate is the share of successful calls, 1 when there were no calls yet.
+	SuccessRate float64
+	// Latency is the mean duration of successful calls.
+	Latency time.Duration
+}
+
+// Ranker is an interface for ranking algorithms ordering the providers from the most preferred one.
+// Rankers are expected to sort stably, so the providers with equal statistics keep their configured order.
+type Ranker interface {
+	Rank([]ProviderStats) []ProviderStats
+}
+
+// RankerFunc is an adapter to use ordinary functions as Ranker.
+type RankerFunc func([]ProviderStats) []ProviderStats
+
+func (f RankerFunc) Rank(stats []ProviderStats) []ProviderStats { return f(stats) }
+
+// NewRanker returns the built-in ranking algorithm by its name.
+func NewRanker(name string) (Ranker, error) {
+	switch name {
+	case RankStatic:
+		return RankerFunc(func(stats []ProviderStats) []ProviderStats { return stats }), nil
+	case RankLatency:
+		return RankerFunc(rankByLatency), nil
+	case RankReliability:
+		return RankerFunc(rankByReliability), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownRanker, name)
+	}
+}
+
+// rankByLatency prefers the providers with the lowest expected time to a successful quote.
+// Providers not called yet go first, so they get measured.
+func rankByLatency(stats []ProviderStats) []ProviderStats {
+	score := func(st ProviderStats) float64 {
+		if st.SuccessRate == 0 {
+			return math.Inf(1)
+		}
+
+		return float64(st.Latency) / st.SuccessRate
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool { return score(stats[i]) < score(stats[j]) })
+
+	return stats
+}
+
+// rankByReliability prefers the providers with the highest success rate, the faster one of equally reliable.
+func rankByReliability(stats []ProviderStats) []ProviderStats {
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].SuccessRate != stats[j].SuccessRate {
+			return stats[i].SuccessRate > stats[j].SuccessRate
+		}
+
+		return stats[i].Latency < stats[j].Latency
+	})
+
+	return stats
+}
+
+// Ranking keeps rolling statistics of the provider calls learnt from the fetched and failed events
+// and orders the providers with the Ranker.
+type Ranking struct {
+	ranker Ranker
+	window int
+
+	mu    sync.Mutex
+	calls map[string]*outcomes
+	seen  []string
+}
+
+// outcomes is a ring buffer of the latest provider calls.
+type outcomes struct {
+	items []outcome
+	next  int
+}
+
+type outcome struct {
+	ok  bool
+	lat time.Duration
+}
+
+// NewRanking creates a new Ranking instance subscribed to the provider events.
+func NewRanking(bus *event.Bus, ranker Ranker, cfg RankingConfig) *Ranking {
+	const defaultWindow = 50
+
+	if cfg.Window < 1 {
+		cfg.Window = defaultWindow
+	}
+
+	r := Ranking{
+		ranker: ranker,
+		window: cfg.Window,
+		calls:  make(map[string]*outcomes),
+	}
+
+	bus.Subscribe(event.New(EventSource, EventKindFetched, nil), r.RecordOutcome)
+	bus.Subscribe(event.New(EventSource, EventKindFailed, nil), r.RecordOutcome)
+
+	return &r
+}
+
+// RecordOutcome is an event listener that records the outcome of the provider call.
+// Calls skipped for the pair the provider does not support and cancelled ones are not counted.
+func (r *Ranking) RecordOutcome(_ context.Context, e event.Event) error {
+	var (
+		prov string
+		out  outcome
+	)
+
+	switch resp := e.Payload.(type) {
+	case ProviderResponse:
+		prov, out = resp.Provider, outcome{ok: true, lat: resp.Latency}
+
+	case ProviderErrorResponse:
+		// The skipped providers have not been called, so they tell nothing about the success rate or latency.
+		if isSkipped(resp.Err) || errors.Is(resp.Err, context.Canceled) {
+			return nil
+		}
+
+		prov, out = resp.Provider, outcome{lat: resp.Latency}
+
+	default:
+		return fmt.Errorf("%w: unexpected payload: %T", ErrInvalidEvent, e.Payload)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls, ok := r.calls[prov]
+	if !ok {
+		calls = &outcomes{items: make([]outcome, 0, r.window)}
+		r.calls[prov] = calls
+		r.seen = append(r.seen, prov)
+	}
+
+	if len(calls.items) < r.window {
+		calls.items = append(calls.items, out)
+		return nil
+	}
+
+	calls.items[calls.next] = out
+	calls.next = (calls.next + 1) % r.window
+
+	return nil
+}
+
+// Order orders the providers by their names from the most preferred one.
+func (r *Ranking) Order(names []string) []string {
+	stats := r.Rank(names...)
+
+	order := make([]string, len(stats))
+	for i := range stats {
+		order[i] = stats[i].Provider
+	}
+
+	return order
+}
+
+// Rank returns the statistics of the providers ranked from the most preferred one.
+// All providers seen so far are ranked when no names are given.
+func (r *Ranking) Rank(names ...string) []ProviderStats {
+	r.mu.Lock()
+
+	if len(names) == 0 {
+		names = r.seen
+	}
+
+	stats := make([]ProviderStats, len(names))
+	for i := range names {
+		stats[i] = r.stats(names[i])
+	}
+
+	r.mu.Unlock()
+
+	return r.ranker.Rank(stats)
+}
+
+// stats computes the statistics of the provider.
+func (r *Ranking) stats(name string) ProviderStats {
+	st := ProviderStats{Provider: name, SuccessRate: 1}
+
+	calls, ok := r.calls[name]
+	if !ok || len(calls.items) == 0 {
+		return st
+	}
+
+	var total time.Duration
+
+	for _, out := range calls.items {
+		st.Calls++
+
+		if !out.ok {
+			st.Failures++
+			continue
+		}
+
+		total += out.lat
+	}
+
+	st.SuccessRate = float64(st.Calls-st.Failures) / float64(st.Calls)
+
+	if ok := st.Calls - st.Failures; ok > 0 {
+		st.Latency = total / time.Duration(ok)
+	}
+
+	return st
+}
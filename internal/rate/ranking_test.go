@@ -0,0 +1,209 @@
+package rate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRanking(t *testing.T) {
+	type call struct {
+		prov string
+		lat  time.Duration
+		err  error
+	}
+
+	tests := map[string]struct {
+		algorithm string
+		window    int
+		calls     []call
+		names     []string
+		want      []string
+	}{
+		"static_keeps_order": {
+			algorithm: rate.RankStatic,
+			calls:     []call{{prov: "a", lat: time.Second}, {prov: "b", lat: time.Millisecond}},
+			names:     []string{"a", "b"},
+			want:      []string{"a", "b"},
+		},
+		"latency_fastest_first": {
+			algorithm: rate.RankLatency,
+			calls:     []call{{prov: "a", lat: time.Second}, {prov: "b", lat: time.Millisecond}},
+			names:     []string{"a", "b"},
+			want:      []string{"b", "a"},
+		},
+		"latency_unhealthy_last": {
+			algorithm: rate.RankLatency,
+			calls: []call{
+				{prov: "a", lat: 100 * time.Millisecond},
+				{prov: "b", lat: time.Millisecond, err: rate.ErrProviderUnavailable},
+			},
+			names: []string{"b", "a"},
+			want:  []string{"a", "b"},
+		},
+		"latency_unknown_first": {
+			algorithm: rate.RankLatency,
+			calls:     []call{{prov: "a", lat: time.Millisecond}},
+			names:     []string{"a", "b"},
+			want:      []string{"b", "a"},
+		},
+		"unsupported_pair_ignored": {
+			algorithm: rate.RankLatency,
+			calls: []call{
+				{prov: "a", lat: 100 * time.Millisecond},
+				{prov: "b", lat: 10 * time.Millisecond},
+				{prov: "b", err: rate.ErrUnsupportedPair},
+			},
+			names: []string{"a", "b"},
+			want:  []string{"b", "a"},
+		},
+		"skipped_ignored": {
+			algorithm: rate.RankReliability,
+			calls: []call{
+				{prov: "a", lat: time.Second},
+				{prov: "b", lat: time.Second},
+				{prov: "b", err: rate.ErrCircuitOpen},
+				{prov: "b", err: rate.ErrQuotaExhausted},
+			},
+			names: []string{"b", "a"},
+			want:  []string{"b", "a"},
+		},
+		"window_forgets_old_calls": {
+			algorithm: rate.RankLatency,
+			window:    1,
+			calls: []call{
+				{prov: "a", lat: 100 * time.Millisecond},
+				{prov: "b", err: rate.ErrProviderUnavailable},
+				{prov: "b", lat: 10 * time.Millisecond},
+			},
+			names: []string{"a", "b"},
+			want:  []string{"b", "a"},
+		},
+		"reliability_most_reliable_first": {
+			algorithm: rate.RankReliability,
+			calls: []call{
+				{prov: "a", lat: time.Millisecond, err: rate.ErrProviderUnavailable},
+				{prov: "a", lat: time.Millisecond},
+				{prov: "b", lat: time.Second},
+			},
+			names: []string{"a", "b"},
+			want:  []string{"b", "a"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ranker, err := rate.NewRanker(tc.algorithm)
+			require.NoError(t, err)
+
+			bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+			rnk := rate.NewRanking(bus, ranker, rate.RankingConfig{Window: tc.window})
+
+			for _, c := range tc.calls {
+				e := event.New(rate.EventSource, rate.EventKindFetched, rate.ProviderResponse{Provider: c.prov, Latency: c.lat})
+				if c.err != nil {
+					e = event.New(rate.EventSource, rate.EventKindFailed, rate.ProviderErrorResponse{Provider: c.prov, Err: c.err, Latency: c.lat})
+				}
+
+				require.NoError(t, rnk.RecordOutcome(context.Background(), e))
+			}
+
+			require.Equal(t, tc.want, rnk.Order(tc.names))
+		})
+	}
+}
+
+func TestNewRankerUnknown(t *testing.T) {
+	_, err := rate.NewRanker("fastest")
+	require.ErrorIs(t, err, rate.ErrUnknownRanker)
+}
+
+type orderFunc func([]string) []string
+
+func (f orderFunc) Order(names []string) []string { return f(names) }
+
+func TestServiceUseRanking(t *testing.T) {
+	var called []string
+
+	newProvider := func(name string, err error) *mock.ExchangeRateProviderMock {
+		return &mock.ExchangeRateProviderMock{
+			GetExchangeRateFunc: func(_ context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+				called = append(called, name)
+				if err != nil {
+					return nil, err
+				}
+
+				return &rate.ExchangeRate{Pair: pair, Value: 1}, nil
+			},
+			StringFunc: func() string { return name },
+		}
+	}
+
+	reverse := orderFunc(func(names []string) []string {
+		out := make([]string, 0, len(names))
+		for i := len(names) - 1; i >= 0; i-- {
+			out = append(out, names[i])
+		}
+
+		return out
+	})
+
+	tests := map[string]struct {
+		order      rate.Orderer
+		provs      []rate.ExchangeRateProvider
+		wantCalled []string
+		wantErr    error
+	}{
+		"no_ranking": {
+			provs:      []rate.ExchangeRateProvider{newProvider("a", nil), newProvider("b", nil)},
+			wantCalled: []string{"a"},
+		},
+		"ranked_first": {
+			order:      reverse,
+			provs:      []rate.ExchangeRateProvider{newProvider("a", nil), newProvider("b", nil)},
+			wantCalled: []string{"b"},
+		},
+		"ranked_fallback": {
+			order:      reverse,
+			provs:      []rate.ExchangeRateProvider{newProvider("a", nil), newProvider("b", errors.New("down"))},
+			wantCalled: []string{"b", "a"},
+		},
+		"missed_kept_last": {
+			order: orderFunc(func([]string) []string { return []string{"c"} }),
+			provs: []rate.ExchangeRateProvider{
+				newProvider("a", errors.New("down")), newProvider("b", nil), newProvider("c", errors.New("down")),
+			},
+			wantCalled: []string{"c", "a", "b"},
+		},
+		"all_failed": {
+			order:      reverse,
+			provs:      []rate.ExchangeRateProvider{newProvider("a", errors.New("down")), newProvider("b", errors.New("down"))},
+			wantCalled: []string{"b", "a"},
+			wantErr:    rate.ErrProviderUnavailable,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			called = nil
+
+			bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+			svc := rate.NewService(bus, tc.provs...)
+
+			if tc.order != nil {
+				svc.UseRanking(tc.order)
+			}
+
+			_, err := svc.GetExchangeRate(context.Background(), rate.NewCurrencyPair("USD", "EUR"))
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantCalled, called)
+		})
+	}
+}
@@ -7,11 +7,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
 )
 
-var ErrInvalidCurrency = fmt.Errorf("invalid currency")
+var (
+	ErrInvalidCurrency = fmt.Errorf("invalid currency")
+	ErrInvalidRange    = errors.New("invalid time range")
+	ErrQuotaExhausted  = errors.New("provider quota exhausted")
+	// ErrAllQuotasExhausted is returned along with the provider errors when every provider has run out of its quota.
+	ErrAllQuotasExhausted = errors.New("quotas of all providers exhausted")
+	// ErrAllUnsupported is returned along with the provider errors when no provider quotes the currency pair.
+	ErrAllUnsupported = errors.New("currency pair is not supported by any provider")
+)
 
 // ExchangeRateProvider is an interface for types that provide exchange rates.
 //
@@ -21,10 +30,17 @@ type ExchangeRateProvider interface {
 	String() string
 }
 
+// Orderer is an interface for ordering providers by their names.
+type Orderer interface {
+	Order(names []string) []string
+}
+
 type Service struct {
 	bus  *event.Bus
 	next *Service
 	prov ExchangeRateProvider
+	// rank orders the chain, it is set for the first instance in the chain only.
+	rank Orderer
 }
 
 // NewService constructs a new Service instance.
@@ -41,40 +57,110 @@ func NewService(bus *event.Bus, provs ...ExchangeRateProvider) *Service {
 		}
 	}
 
-	svc.bus.Subscribe(event.New(EventSource, EventKindRequested, nil), svc.ResponseExchangeRate)
 	svc.bus.Subscribe(event.New(EventSource, EventKindFetched, nil), svc.LogExchangeRate)
 
 	return svc
 }
 
+// UseRanking makes the chain try the providers in the order given by Orderer
+// instead of the order they were provided in.
+func (svc *Service) UseRanking(rank Orderer) {
+	svc.rank = rank
+}
+
 // GetExchangeRate attempts to get the exchange rate for a pair of currencies.
 // If the Service fails to get the exchange rate, it passes the request to the next Service in the chain, if any.
-func (svc *Service) GetExchangeRate(ctx context.Context, pair CurrencyPair) (xrt *ExchangeRate, err error) {
+// Every provider in the chain publishes the outcome of its own call only.
+func (svc *Service) GetExchangeRate(ctx context.Context, pair CurrencyPair) (*ExchangeRate, error) {
 	if err := pair.Validate(); err != nil {
 		return nil, err
 	}
 
-	defer func() {
-		e := event.New(EventSource, EventKindFetched, ProviderResponse{Provider: svc.prov.String(), ExchangeRate: xrt})
+	chain := svc.chain()
+	exhausted, unsupported := true, true
+
+	for i, node := range chain {
+		start := time.Now()
 
+		xrt, err := node.prov.GetExchangeRate(ctx, pair)
 		if err != nil {
+			exhausted = exhausted && errors.Is(err, ErrQuotaExhausted)
+			unsupported = unsupported && isUnsupported(err)
 			err = errors.Join(ErrProviderUnavailable, err)
-			e = event.New(EventSource, EventKindFailed, ProviderErrorResponse{Provider: svc.prov.String(), Err: err})
 		}
 
-		if errpub := svc.bus.Publish(ctx, e); errpub != nil {
-			err = errors.Join(err, errpub)
+		errpub := node.publish(ctx, pair, xrt, err, time.Since(start))
+
+		if err != nil && i < len(chain)-1 {
+			continue
+		}
+
+		if err != nil {
+			if exhausted {
+				err = errors.Join(ErrAllQuotasExhausted, err)
+			}
+
+			if unsupported {
+				err = errors.Join(ErrAllUnsupported, err)
+			}
+
+			return nil, fmt.Errorf("failed to execute exchange rate providers chain: %w", errors.Join(err, errpub))
+		}
+
+		if errpub != nil {
+			return nil, errpub
+		}
+
+		return xrt, nil
+	}
+
+	return nil, fmt.Errorf("failed to execute exchange rate providers chain: %w", ErrProviderUnavailable)
+}
+
+// chain returns the instances of the chain in the order the providers are tried.
+func (svc *Service) chain() []*Service {
+	var chain []*Service
+	for node := svc; node != nil; node = node.next {
+		chain = append(chain, node)
+	}
+
+	if svc.rank == nil {
+		return chain
+	}
+
+	names := make([]string, len(chain))
+	nodes := make(map[string]*Service, len(chain))
+
+	for i := range chain {
+		names[i] = chain[i].prov.String()
+		nodes[names[i]] = chain[i]
+	}
+
+	ranked := make([]*Service, 0, len(chain))
+
+	for _, name := range svc.rank.Order(names) {
+		if node, ok := nodes[name]; ok {
+			ranked = append(ranked, node)
+			delete(nodes, name)
 		}
-	}()
+	}
 
-	xrt, err = svc.prov.GetExchangeRate(ctx, pair)
-	if err != nil && svc.next != nil {
-		return svc.next.GetExchangeRate(ctx, pair)
+	// Providers the Orderer has missed keep their original order at the end.
+	for i := range chain {
+		if _, ok := nodes[names[i]]; ok {
+			ranked = append(ranked, chain[i])
+		}
 	}
 
+	return ranked
+}
+
+// publish publishes the outcome of the provider call.
+func (svc *Service) publish(ctx context.Context, pair CurrencyPair, xrt *ExchangeRate, err error, lat time.Duration) error {
+	e := event.New(EventSource, EventKindFetched, ProviderResponse{Provider: svc.prov.String(), ExchangeRate: xrt, Latency: lat})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute exchange rate providers chain: %w", err)
+		e = event.New(EventSource, EventKindFailed, ProviderErrorResponse{Provider: svc.prov.String(), Pair: pair, Err: err, Latency: lat})
 	}
 
-	return xrt, nil
+	return svc.bus.Publish(ctx, e)
 }
@@ -0,0 +1,110 @@
+package rate_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocketHandlerSocket(t *testing.T) {
+	btc := rate.NewCurrencyPair("BTC", "UAH")
+
+	log := logger.New(logger.WithConsoleCore(logger.LevelDebug))
+	feed := rate.NewFeed(event.NewBus(log))
+	reg := currency.NewRegistry(append(currency.ISO4217(), currency.Crypto()...)...)
+	h := rate.NewSocketHandler(feed, reg, rate.StreamConfig{Heartbeat: time.Hour, Buffer: 4})
+
+	w := web.New(nil, web.WithErrors(log))
+	w.Handle(http.MethodGet, "/", "ws", h.Socket)
+
+	srv := httptest.NewServer(w)
+	defer srv.Close()
+
+	ws, err := web.DialWebSocket(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws")
+	require.NoError(t, err)
+
+	defer ws.Close()
+
+	exchange := func(send string) rate.SocketMessage {
+		if send != "" {
+			require.NoError(t, ws.WriteMessage([]byte(send)))
+		}
+
+		data, err := ws.ReadMessage()
+		require.NoError(t, err)
+
+		var msg rate.SocketMessage
+		require.NoError(t, json.Unmarshal(data, &msg))
+
+		return msg
+	}
+
+	tests := []struct {
+		name  string
+		send  string
+		event *event.Event
+		want  rate.SocketMessage
+	}{
+		{
+			name: "subscribe",
+			send: `{"type":"subscribe","pairs":["btc/uah","ETH/USD"]}`,
+			want: rate.SocketMessage{Type: rate.MessageSubscribe, Pairs: []string{"BTC/UAH", "ETH/USD"}},
+		},
+		{
+			name: "unsubscribe",
+			send: `{"type":"unsubscribe","pairs":["ETH/USD"]}`,
+			want: rate.SocketMessage{Type: rate.MessageUnsubscribe, Pairs: []string{"BTC/UAH"}},
+		},
+		{
+			name: "tick",
+			event: func() *event.Event {
+				e := fetchedEvent(btc, 1.5, time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC))
+				return &e
+			}(),
+			want: rate.SocketMessage{Type: rate.MessageTick, Tick: &rate.TickResponse{
+				Base: "BTC", Quote: "UAH", Rate: 1.5, Provider: "Test", Time: time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "invalid_pair",
+			send: `{"type":"subscribe","pairs":["BTCUAH"]}`,
+			want: rate.SocketMessage{Type: rate.MessageError, Error: `invalid currency: "BTCUAH"`},
+		},
+		{
+			name: "unknown_type",
+			send: `{"type":"publish"}`,
+			want: rate.SocketMessage{Type: rate.MessageError, Error: `invalid message: unknown type "publish"`},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.event != nil {
+				require.NoError(t, feed.ForwardExchangeRate(context.Background(), *tc.event))
+			}
+
+			require.Equal(t, tc.want, exchange(tc.send))
+		})
+	}
+
+	t.Run("shutdown", func(t *testing.T) {
+		w.Close()
+
+		_, err := ws.ReadMessage()
+
+		var cerr *web.CloseError
+		require.ErrorAs(t, err, &cerr)
+		require.Equal(t, web.CloseGoingAway, cerr.Code)
+	})
+}
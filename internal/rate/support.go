@@ -0,0 +1,137 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+var (
+	ErrUnsupportedPair = errors.New("currency pair is not supported by provider")
+	// ErrUnknownSymbol is returned by the provider responding it does not quote the currency pair.
+	ErrUnknownSymbol = errors.New("currency symbol is not quoted by provider")
+)
+
+// SupportConfig defines when a currency pair is considered unsupported by a provider.
+type SupportConfig struct {
+	// Threshold is the number of consecutive unknown symbol responses after which the pair is unsupported.
+	Threshold int
+	// Recheck is the duration after which the unsupported pair is tried again.
+	Recheck time.Duration
+}
+
+// Support keeps track of the currency pairs providers fail to serve,
+// so the calls known to fail are skipped.
+// It learns from the fetched and failed events, only the responses telling the symbol is unknown count,
+// the other failures like rejected credentials, rate limits or rejected quotes tell nothing about the support.
+type Support struct {
+	cfg SupportConfig
+
+	mu    sync.Mutex
+	fails map[supportKey]*supportState
+}
+
+type supportKey struct {
+	prov string
+	pair CurrencyPair
+}
+
+type supportState struct {
+	count int
+	last  time.Time
+}
+
+// NewSupport creates a new Support instance subscribed to the provider events.
+func NewSupport(bus *event.Bus, cfg SupportConfig) *Support {
+	if cfg.Threshold < 1 {
+		cfg.Threshold = 1
+	}
+
+	s := Support{
+		cfg:   cfg,
+		fails: make(map[supportKey]*supportState),
+	}
+
+	bus.Subscribe(event.New(EventSource, EventKindFetched, nil), s.TrackExchangeRate)
+	bus.Subscribe(event.New(EventSource, EventKindFailed, nil), s.TrackExchangeRate)
+
+	return &s
+}
+
+// TrackExchangeRate is an event listener that records the outcome of the provider call.
+func (s *Support) TrackExchangeRate(ctx context.Context, e event.Event) error {
+	switch resp := e.Payload.(type) {
+	case ProviderResponse:
+		if resp.ExchangeRate == nil {
+			return fmt.Errorf("%w: missing exchange rate from %s", ErrInvalidEvent, resp.Provider)
+		}
+
+		s.mu.Lock()
+		delete(s.fails, supportKey{prov: resp.Provider, pair: resp.ExchangeRate.Pair})
+		s.mu.Unlock()
+
+	case ProviderErrorResponse:
+		if !errors.Is(resp.Err, ErrUnknownSymbol) {
+			return nil
+		}
+
+		key := supportKey{prov: resp.Provider, pair: resp.Pair}
+
+		s.mu.Lock()
+		st, ok := s.fails[key]
+		if !ok {
+			st = new(supportState)
+			s.fails[key] = st
+		}
+		st.count++
+		st.last = time.Now()
+		s.mu.Unlock()
+
+	default:
+		return fmt.Errorf("%w: unexpected payload: %T", ErrInvalidEvent, e.Payload)
+	}
+
+	return nil
+}
+
+// Supports reports whether the provider is expected to serve the pair.
+func (s *Support) Supports(prov string, pair CurrencyPair) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.fails[supportKey{prov: prov, pair: pair}]
+
+	return !ok || st.count < s.cfg.Threshold || time.Since(st.last) >= s.cfg.Recheck
+}
+
+// Wrap returns the provider that fails immediately for the pairs it is known not to support.
+func (s *Support) Wrap(prov ExchangeRateProvider) ExchangeRateProvider {
+	return &supportedProvider{ExchangeRateProvider: prov, sup: s}
+}
+
+type supportedProvider struct {
+	ExchangeRateProvider
+	sup *Support
+}
+
+func (p *supportedProvider) GetExchangeRate(ctx context.Context, pair CurrencyPair) (*ExchangeRate, error) {
+	if !p.sup.Supports(p.String(), pair) {
+		return nil, fmt.Errorf("%w: %s: %s/%s", ErrUnsupportedPair, p, pair.Base, pair.Quote)
+	}
+
+	return p.ExchangeRateProvider.GetExchangeRate(ctx, pair)
+}
+
+// isSkipped reports whether the provider has not been called at all.
+func isSkipped(err error) bool {
+	return errors.Is(err, ErrUnsupportedPair) || errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrQuotaExhausted)
+}
+
+// isUnsupported reports whether the provider does not quote the pair, either known in advance or responded.
+func isUnsupported(err error) bool {
+	return errors.Is(err, ErrUnsupportedPair) || errors.Is(err, ErrUnknownSymbol)
+}
@@ -0,0 +1,104 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Triangulator is an ExchangeRateService decorator computing cross rates through pivot currencies
+// when the pair is not supported by any provider.
+type Triangulator struct {
+	svc    ExchangeRateService
+	pivots []string
+}
+
+// NewTriangulator wraps ExchangeRateService with cross rate computation through the given pivot currencies.
+// Pivots are tried in the order they are provided.
+func NewTriangulator(svc ExchangeRateService, pivots ...string) *Triangulator {
+	t := Triangulator{svc: svc}
+	for i := range pivots {
+		t.pivots = append(t.pivots, strings.ToUpper(pivots[i]))
+	}
+
+	return &t
+}
+
+// GetExchangeRate gets the exchange rate directly, falling back to the cross rates through pivot currencies
+// only when the pair is unsupported, so the outages and exhausted quotas are not multiplied by the legs.
+// The cross rate reports the currencies it was computed through in ExchangeRate.Path.
+func (t *Triangulator) GetExchangeRate(ctx context.Context, pair CurrencyPair) (*ExchangeRate, error) {
+	if err := pair.Validate(); err != nil {
+		return nil, err
+	}
+
+	xrt, err := t.svc.GetExchangeRate(ctx, pair)
+	if err == nil || !errors.Is(err, ErrAllUnsupported) {
+		return xrt, err
+	}
+
+	errs := []error{err}
+
+	for _, pivot := range t.pivots {
+		if pivot == pair.Base || pivot == pair.Quote {
+			continue
+		}
+
+		xrt, err := t.cross(ctx, pair, pivot)
+		if err == nil {
+			return xrt, nil
+		}
+
+		errs = append(errs, fmt.Errorf("through %s: %w", pivot, err))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("triangulating exchange rate: %w", errors.Join(errs...))
+}
+
+// cross fetches both legs through the pivot concurrently and multiplies them.
+func (t *Triangulator) cross(ctx context.Context, pair CurrencyPair, pivot string) (*ExchangeRate, error) {
+	type leg struct {
+		xrt *ExchangeRate
+		err error
+	}
+
+	second := make(chan leg, 1)
+
+	go func() {
+		xrt, err := t.svc.GetExchangeRate(ctx, NewCurrencyPair(pivot, pair.Quote))
+		second <- leg{xrt: xrt, err: err}
+	}()
+
+	xrt1, err1 := t.svc.GetExchangeRate(ctx, NewCurrencyPair(pair.Base, pivot))
+	l2 := <-second
+
+	if err := errors.Join(err1, l2.err); err != nil {
+		return nil, err
+	}
+
+	xrt := ExchangeRate{
+		Value:     xrt1.Value * l2.xrt.Value,
+		Pair:      pair,
+		Provider:  xrt1.Provider + "," + l2.xrt.Provider,
+		Timestamp: earliest(xrt1.Timestamp, l2.xrt.Timestamp),
+		FetchedAt: earliest(xrt1.FetchedAt, l2.xrt.FetchedAt),
+		Path:      []string{pair.Base, pivot, pair.Quote},
+	}
+
+	return &xrt, nil
+}
+
+// earliest returns the earliest non-zero time.
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,163 @@
+package rate_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriangulatorGetExchangeRate(t *testing.T) {
+	quotes := map[rate.CurrencyPair]float64{
+		rate.NewCurrencyPair("BTC", "UAH"):  1_000_000,
+		rate.NewCurrencyPair("SOL", "USDT"): 20,
+		rate.NewCurrencyPair("USDT", "UAH"): 40,
+		rate.NewCurrencyPair("ETH", "USDT"): 2000,
+	}
+
+	down := map[rate.CurrencyPair]bool{
+		rate.NewCurrencyPair("ETH", "UAH"): true,
+	}
+
+	svc := &mock.ExchangeRateServiceMock{
+		GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+			if down[pair] {
+				return nil, rate.ErrProviderUnavailable
+			}
+
+			val, ok := quotes[pair]
+			if !ok {
+				return nil, errors.Join(rate.ErrAllUnsupported, rate.ErrProviderUnavailable)
+			}
+
+			return rate.NewExchangeRate(val, pair), nil
+		},
+	}
+
+	tests := map[string]struct {
+		pair     rate.CurrencyPair
+		wantRate float64
+		wantPath []string
+		wantErr  error
+	}{
+		"direct": {
+			pair:     rate.NewCurrencyPair("BTC", "UAH"),
+			wantRate: 1_000_000,
+		},
+
+		"through_pivot": {
+			pair:     rate.NewCurrencyPair("SOL", "UAH"),
+			wantRate: 800,
+			wantPath: []string{"SOL", "USDT", "UAH"},
+		},
+
+		"no_path": {
+			pair:    rate.NewCurrencyPair("DOGE", "UAH"),
+			wantErr: rate.ErrAllUnsupported,
+		},
+
+		"outage_not_triangulated": {
+			pair:    rate.NewCurrencyPair("ETH", "UAH"),
+			wantErr: rate.ErrProviderUnavailable,
+		},
+
+		"missing_currency": {
+			pair:    rate.NewCurrencyPair("", "UAH"),
+			wantErr: rate.ErrInvalidCurrency,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tri := rate.NewTriangulator(svc, "usd", "usdt", "btc")
+
+			xrt, err := tri.GetExchangeRate(context.Background(), tc.pair)
+			require.ErrorIs(t, err, tc.wantErr)
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			require.Equal(t, tc.wantRate, xrt.Value)
+			require.Equal(t, tc.pair, xrt.Pair)
+			require.Equal(t, tc.wantPath, xrt.Path)
+		})
+	}
+}
+
+func TestSupportWrap(t *testing.T) {
+	log := logger.New(logger.WithConsoleCore(logger.LevelDebug))
+	bus := event.NewBus(log)
+	pair := rate.NewCurrencyPair("SOL", "UAH")
+
+	tests := map[string]struct {
+		err             error
+		wantCalls       int
+		wantUnsupported bool
+	}{
+		"unknown_symbol": {
+			err:             fmt.Errorf("parsing rate: %w", rate.ErrUnknownSymbol),
+			wantCalls:       2,
+			wantUnsupported: true,
+		},
+		"unauthorized": {
+			err:       web.NewRequestError(web.ErrClientError, http.StatusUnauthorized),
+			wantCalls: 3,
+		},
+		"rate_limited": {
+			err:       web.NewRequestError(web.ErrUnknownError, http.StatusTooManyRequests),
+			wantCalls: 3,
+		},
+		"rejected_quote": {
+			err:       fmt.Errorf("%w: %w", rate.ErrRejectedQuote, rate.ErrSymbolMismatch),
+			wantCalls: 3,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			var calls int
+
+			prov := &mock.ExchangeRateProviderMock{
+				GetExchangeRateFunc: func(ctx context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+					calls++
+					return nil, tc.err
+				},
+				StringFunc: func() string { return "TestProvider" },
+			}
+
+			sup := rate.NewSupport(bus, rate.SupportConfig{Threshold: 2, Recheck: time.Hour})
+			wrapped := sup.Wrap(prov)
+
+			for i := 0; i < 3; i++ {
+				_, err := wrapped.GetExchangeRate(context.Background(), pair)
+				require.Error(t, err)
+
+				e := event.New(rate.EventSource, rate.EventKindFailed, rate.ProviderErrorResponse{Provider: prov.String(), Pair: pair, Err: err})
+				require.NoError(t, sup.TrackExchangeRate(context.Background(), e))
+			}
+
+			require.Equal(t, tc.wantCalls, calls)
+			require.Equal(t, tc.wantUnsupported, !sup.Supports(prov.String(), pair))
+			require.True(t, sup.Supports(prov.String(), rate.NewCurrencyPair("BTC", "UAH")))
+
+			e := event.New(rate.EventSource, rate.EventKindFetched, rate.ProviderResponse{
+				Provider:     prov.String(),
+				ExchangeRate: rate.NewExchangeRate(1, pair),
+			})
+			require.NoError(t, sup.TrackExchangeRate(context.Background(), e))
+			require.True(t, sup.Supports(prov.String(), pair))
+		})
+	}
+}
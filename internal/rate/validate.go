@@ -0,0 +1,173 @@
+package rate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+const EventKindRejected = "rejected"
+
+var (
+	ErrRejectedQuote   = errors.New("quote rejected")
+	ErrNonPositiveRate = errors.New("non-positive rate")
+	ErrRateDeviation   = errors.New("rate deviates from the other providers")
+	ErrStaleQuote      = errors.New("quote is too old")
+	ErrSymbolMismatch  = errors.New("quoted pair does not match the requested one")
+)
+
+// ValidationConfig defines the rules the provider quotes are checked against.
+type ValidationConfig struct {
+	// MaxDeviation is the largest relative difference from the median of the latest quotes of the other providers like 0.2,
+	// zero disables the check.
+	MaxDeviation float64
+	// DeviationWindow is the duration the latest quote of a provider stays a reference for the deviation check.
+	DeviationWindow time.Duration
+	// MaxAge is the largest age of the quote timestamp reported by the provider, zero disables the check.
+	MaxAge time.Duration
+}
+
+// Rejection represents the data of a quote rejection event.
+type Rejection struct {
+	Provider     string
+	Pair         CurrencyPair
+	ExchangeRate *ExchangeRate
+	Err          error
+}
+
+// Validator checks the quotes returned by the wrapped providers.
+// A rejected quote fails the call, so the chain falls through to the next provider,
+// and is published as the rejected event.
+type Validator struct {
+	bus *event.Bus
+	cfg ValidationConfig
+
+	mu sync.Mutex
+	// latest is the latest quote of every provider keyed by pair and provider name.
+	latest map[CurrencyPair]map[string]observed
+}
+
+type observed struct {
+	value float64
+	at    time.Time
+}
+
+// NewValidator creates a new Validator instance.
+func NewValidator(bus *event.Bus, cfg ValidationConfig) *Validator {
+	return &Validator{
+		bus:    bus,
+		cfg:    cfg,
+		latest: make(map[CurrencyPair]map[string]observed),
+	}
+}
+
+// Wrap returns the provider whose quotes are validated.
+func (v *Validator) Wrap(prov ExchangeRateProvider) ExchangeRateProvider {
+	return &validatedProvider{ExchangeRateProvider: prov, val: v}
+}
+
+// Validate checks the quote of the provider for the requested pair.
+func (v *Validator) Validate(prov string, pair CurrencyPair, xrt *ExchangeRate) error {
+	if err := v.check(prov, pair, xrt); err != nil {
+		return fmt.Errorf("%w: %w", ErrRejectedQuote, err)
+	}
+
+	return nil
+}
+
+func (v *Validator) check(prov string, pair CurrencyPair, xrt *ExchangeRate) error {
+	if xrt.Pair != (CurrencyPair{}) && xrt.Pair != pair {
+		return fmt.Errorf("%w: got %s/%s, want %s/%s", ErrSymbolMismatch, xrt.Pair.Base, xrt.Pair.Quote, pair.Base, pair.Quote)
+	}
+
+	if xrt.Value <= 0 || math.IsNaN(xrt.Value) || math.IsInf(xrt.Value, 0) {
+		return fmt.Errorf("%w: %v", ErrNonPositiveRate, xrt.Value)
+	}
+
+	if v.cfg.MaxAge > 0 && !xrt.Timestamp.IsZero() {
+		if age := time.Since(xrt.Timestamp); age > v.cfg.MaxAge {
+			return fmt.Errorf("%w: %s old", ErrStaleQuote, age.Truncate(time.Second))
+		}
+	}
+
+	if v.cfg.MaxDeviation > 0 {
+		if ref, ok := v.reference(prov, pair); ok {
+			if dev := math.Abs(xrt.Value-ref) / ref; dev > v.cfg.MaxDeviation {
+				return fmt.Errorf("%w: %v against %v", ErrRateDeviation, xrt.Value, ref)
+			}
+		}
+
+		v.record(prov, pair, xrt.Value)
+	}
+
+	return nil
+}
+
+// reference returns the median of the latest accepted quotes of the other providers within the deviation window,
+// a quote is accepted as is when no other provider has quoted the pair.
+func (v *Validator) reference(prov string, pair CurrencyPair) (float64, bool) {
+	now := time.Now()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	quotes := v.latest[pair]
+	vals := make([]float64, 0, len(quotes))
+
+	for name, q := range quotes {
+		if v.cfg.DeviationWindow > 0 && now.Sub(q.at) >= v.cfg.DeviationWindow {
+			delete(quotes, name)
+			continue
+		}
+
+		if name != prov {
+			vals = append(vals, q.value)
+		}
+	}
+
+	if len(vals) == 0 {
+		return 0, false
+	}
+
+	return median(vals), true
+}
+
+// record keeps the accepted quote of the provider as the reference of the others,
+// so a rejected one never shifts the reference away from the market.
+func (v *Validator) record(prov string, pair CurrencyPair, val float64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	quotes, ok := v.latest[pair]
+	if !ok {
+		quotes = make(map[string]observed)
+		v.latest[pair] = quotes
+	}
+
+	quotes[prov] = observed{value: val, at: time.Now()}
+}
+
+type validatedProvider struct {
+	ExchangeRateProvider
+	val *Validator
+}
+
+func (p *validatedProvider) GetExchangeRate(ctx context.Context, pair CurrencyPair) (*ExchangeRate, error) {
+	xrt, err := p.ExchangeRateProvider.GetExchangeRate(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.val.Validate(p.String(), pair, xrt); err != nil {
+		e := event.New(EventSource, EventKindRejected, Rejection{Provider: p.String(), Pair: pair, ExchangeRate: xrt, Err: err})
+
+		return nil, errors.Join(err, p.val.bus.Publish(ctx, e))
+	}
+
+	return xrt, nil
+}
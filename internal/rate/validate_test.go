@@ -0,0 +1,153 @@
+package rate_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/rate"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator(t *testing.T) {
+	pair := rate.NewCurrencyPair("BTC", "USD")
+	cfg := rate.ValidationConfig{MaxDeviation: 0.2, DeviationWindow: time.Hour, MaxAge: time.Hour}
+
+	tests := map[string]struct {
+		cfg     rate.ValidationConfig
+		quotes  []rate.ExchangeRate
+		wantErr []error
+	}{
+		"valid": {
+			cfg:     cfg,
+			quotes:  []rate.ExchangeRate{{Value: 30000, Pair: pair}, {Value: 31000, Pair: pair}},
+			wantErr: []error{nil, nil},
+		},
+		"non_positive": {
+			cfg:     cfg,
+			quotes:  []rate.ExchangeRate{{Value: 0, Pair: pair}, {Value: -1, Pair: pair}},
+			wantErr: []error{rate.ErrNonPositiveRate, rate.ErrNonPositiveRate},
+		},
+		"deviation": {
+			cfg: cfg,
+			quotes: []rate.ExchangeRate{
+				{Provider: "A", Value: 30000, Pair: pair},
+				{Provider: "B", Value: 31000, Pair: pair},
+				{Provider: "C", Value: 3000, Pair: pair},
+			},
+			wantErr: []error{nil, nil, rate.ErrRateDeviation},
+		},
+		"deviation_single_provider": {
+			cfg:     cfg,
+			quotes:  []rate.ExchangeRate{{Provider: "A", Value: 30000, Pair: pair}, {Provider: "A", Value: 3000, Pair: pair}},
+			wantErr: []error{nil, nil},
+		},
+		"deviation_rejected_not_reference": {
+			cfg: cfg,
+			quotes: []rate.ExchangeRate{
+				{Provider: "A", Value: 100, Pair: pair},
+				{Provider: "B", Value: 100, Pair: pair},
+				{Provider: "A", Value: 1000, Pair: pair},
+				{Provider: "B", Value: 100, Pair: pair},
+				{Provider: "B", Value: 101, Pair: pair},
+			},
+			wantErr: []error{nil, nil, rate.ErrRateDeviation, nil, nil},
+		},
+		"deviation_outlier_then_correct": {
+			cfg: cfg,
+			quotes: []rate.ExchangeRate{
+				{Provider: "A", Value: 30000, Pair: pair},
+				{Provider: "B", Value: 30000, Pair: pair},
+				{Provider: "C", Value: 3000, Pair: pair},
+				{Provider: "C", Value: 30500, Pair: pair},
+				{Provider: "A", Value: 30200, Pair: pair},
+			},
+			wantErr: []error{nil, nil, rate.ErrRateDeviation, nil, nil},
+		},
+		"deviation_disabled": {
+			cfg:     rate.ValidationConfig{},
+			quotes:  []rate.ExchangeRate{{Provider: "A", Value: 30000, Pair: pair}, {Provider: "B", Value: 3000, Pair: pair}},
+			wantErr: []error{nil, nil},
+		},
+		"deviation_reference_expired": {
+			cfg:     rate.ValidationConfig{MaxDeviation: 0.2, DeviationWindow: time.Nanosecond},
+			quotes:  []rate.ExchangeRate{{Provider: "A", Value: 30000, Pair: pair}, {Provider: "B", Value: 3000, Pair: pair}},
+			wantErr: []error{nil, nil},
+		},
+		"stale": {
+			cfg: cfg,
+			quotes: []rate.ExchangeRate{
+				{Value: 30000, Pair: pair, Timestamp: time.Now().Add(-2 * time.Hour)},
+				{Value: 30000, Pair: pair, Timestamp: time.Now().Add(-time.Minute)},
+			},
+			wantErr: []error{rate.ErrStaleQuote, nil},
+		},
+		"symbol_mismatch": {
+			cfg: cfg,
+			quotes: []rate.ExchangeRate{
+				{Value: 30000, Pair: rate.NewCurrencyPair("BTC", "EUR")},
+				{Value: 30000, Pair: rate.CurrencyPair{Base: "BTC"}},
+			},
+			wantErr: []error{rate.ErrSymbolMismatch, rate.ErrSymbolMismatch},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			val := rate.NewValidator(nil, tc.cfg)
+
+			for i := range tc.quotes {
+				err := val.Validate(tc.quotes[i].Provider, pair, &tc.quotes[i])
+				require.ErrorIs(t, err, tc.wantErr[i], i)
+
+				if tc.wantErr[i] != nil {
+					require.ErrorIs(t, err, rate.ErrRejectedQuote, i)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorWrap(t *testing.T) {
+	bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+	val := rate.NewValidator(bus, rate.ValidationConfig{})
+
+	var (
+		wg       sync.WaitGroup
+		rejected rate.Rejection
+	)
+
+	wg.Add(1)
+	bus.Subscribe(event.New(rate.EventSource, rate.EventKindRejected, nil), func(_ context.Context, e event.Event) error {
+		defer wg.Done()
+		rejected, _ = e.Payload.(rate.Rejection)
+
+		return nil
+	})
+
+	bad := val.Wrap(&mock.ExchangeRateProviderMock{
+		GetExchangeRateFunc: func(_ context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+			return &rate.ExchangeRate{Pair: pair}, nil
+		},
+		StringFunc: func() string { return "Bad" },
+	})
+
+	good := val.Wrap(&mock.ExchangeRateProviderMock{
+		GetExchangeRateFunc: func(_ context.Context, pair rate.CurrencyPair) (*rate.ExchangeRate, error) {
+			return &rate.ExchangeRate{Pair: pair, Value: 1.1}, nil
+		},
+		StringFunc: func() string { return "Good" },
+	})
+
+	xrt, err := rate.NewService(bus, bad, good).GetExchangeRate(context.Background(), rate.NewCurrencyPair("EUR", "USD"))
+	require.NoError(t, err)
+	require.Equal(t, 1.1, xrt.Value)
+
+	wg.Wait()
+	require.Equal(t, "Bad", rejected.Provider)
+	require.ErrorIs(t, rejected.Err, rate.ErrNonPositiveRate)
+}
@@ -0,0 +1,487 @@
+package subs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+const (
+	EventKindAlerted = "alerted"
+	// EventKindRateFetched is the event of the exchange rate fetched from a provider.
+	EventKindRateFetched = "fetched"
+)
+
+const (
+	// AlertAbove triggers once the rate reaches the threshold from below.
+	AlertAbove = "above"
+	// AlertBelow triggers once the rate reaches the threshold from above.
+	AlertBelow = "below"
+	// AlertChange triggers once the rate moves by the relative threshold within the window.
+	AlertChange = "change"
+)
+
+// defaultAlertInterval is the period the alerting subscriptions are reloaded and the alert states are saved at by default.
+const defaultAlertInterval = time.Minute
+
+// sampleSpacing is the least duration between the rates kept for measuring the change,
+// so frequent fetches do not grow the samples beyond the window in minutes.
+const sampleSpacing = time.Minute
+
+// ErrInvalidAlert is an error indicating that the alert rule is malformed.
+var ErrInvalidAlert = errors.New("invalid alert")
+
+// FetchedRateEvent is an event of the exchange rate fetched from a provider.
+type FetchedRateEvent interface {
+	CurrencyPairEvent
+	Rate() float64
+}
+
+// Alert represents a value object of a rule the subscriber is notified on.
+type Alert struct {
+	// Kind is one of AlertAbove, AlertBelow or AlertChange.
+	Kind string
+	// Threshold is the rate for AlertAbove and AlertBelow and the relative change like 0.05 for AlertChange.
+	Threshold float64
+	// Window is the duration the change is measured over for AlertChange.
+	Window time.Duration
+}
+
+// AlertConfig defines how often the triggered alerts are notified.
+type AlertConfig struct {
+	// Hysteresis is the relative distance the rate has to move back past the threshold
+	// for the triggered alert to be re-armed, like 0.01.
+	Hysteresis float64
+	// Cooldown is the least duration between two notifications of the same alert.
+	Cooldown time.Duration
+	// Interval is the period the alerting subscriptions are reloaded and the alert states are saved at, a minute by default.
+	Interval time.Duration
+}
+
+// AlertStorer is an interface for persisting the state of the alerts.
+type AlertStorer interface {
+	Replace(...AlertSnapshot) error
+	FetchAll() ([]AlertSnapshot, error)
+}
+
+// AlertSnapshot represents the persisted state of the alerts, so the cooldown and hysteresis survive restarts.
+type AlertSnapshot struct {
+	// States are keyed by the subscriber address, topic and alert rule.
+	States map[string]AlertState
+	// Samples are the rates the change is measured over keyed by the topic like "BTC/UAH".
+	Samples map[string][]Sample
+}
+
+// AlertState represents whether the alert has fired and when it was notified last.
+type AlertState struct {
+	Fired    bool
+	Notified time.Time
+}
+
+// Sample represents the rate of the topic at the time.
+type Sample struct {
+	Value float64
+	At    time.Time
+}
+
+// Alerted represents the data of a triggered alert event.
+type Alerted struct {
+	Subscriber Subscriber
+	Topic      Topic
+	Alert      Alert
+	Rate       float64
+	// Change is the relative change of the rate over the window for AlertChange, zero otherwise.
+	Change float64
+}
+
+// Validate checks the alert rule is complete.
+func (a Alert) Validate() error {
+	switch a.Kind {
+	case AlertAbove, AlertBelow:
+		if a.Threshold <= 0 {
+			return fmt.Errorf("%w: %s threshold must be positive", ErrInvalidAlert, a.Kind)
+		}
+
+	case AlertChange:
+		if a.Threshold <= 0 {
+			return fmt.Errorf("%w: change threshold must be positive", ErrInvalidAlert)
+		}
+
+		if a.Window <= 0 {
+			return fmt.Errorf("%w: change window must be positive", ErrInvalidAlert)
+		}
+
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidAlert, a.Kind)
+	}
+
+	return nil
+}
+
+// String returns the rule like "above 1500000" or "change 5% in 24h0m0s".
+func (a Alert) String() string {
+	if a.Kind == AlertChange {
+		return fmt.Sprintf("%s %s%% in %s", a.Kind, strconv.FormatFloat(a.Threshold*100, 'f', -1, 64), a.Window)
+	}
+
+	return a.Kind + " " + strconv.FormatFloat(a.Threshold, 'f', -1, 64)
+}
+
+// Subscribers implements SubscribersEvent.
+func (a Alerted) Subscribers() []string {
+	return []string{a.Subscriber.Address.String()}
+}
+
+// BaseCurrency implements CurrencyPairEvent.
+func (a Alerted) BaseCurrency() string {
+	return a.Topic.Base
+}
+
+// QuoteCurrency implements CurrencyPairEvent.
+func (a Alerted) QuoteCurrency() string {
+	return a.Topic.Quote
+}
+
+// ExchangeRate implements ExchangeRateEvent.
+func (a Alerted) ExchangeRate() float64 {
+	return a.Rate
+}
+
+// Condition returns the rule the alert was triggered by.
+func (a Alerted) Condition() string {
+	return a.Alert.String()
+}
+
+// Alerter evaluates the alerts of the subscriptions whenever a new rate is fetched
+// and publishes the alerted event for every triggered one.
+// A triggered alert is not notified again until the rate moves back past the threshold by the hysteresis
+// and the cooldown has passed, so a rate oscillating at the threshold does not flood the subscriber.
+// The alerting subscriptions are indexed by topic and reloaded at the interval, so the changes apply within it,
+// and the alert states are saved at the interval and once the Alerter stops.
+type Alerter struct {
+	bus   *event.Bus
+	repo  SubscriberRepository
+	store AlertStorer
+	cfg   AlertConfig
+
+	mu      sync.Mutex
+	states  map[string]*AlertState
+	samples map[Topic][]Sample
+	dirty   bool
+
+	idxMu   sync.Mutex
+	index   map[Topic][]Subscription
+	indexed time.Time
+}
+
+// NewAlerter creates a new Alerter instance listening to the fetched rates and loading the saved alert states.
+func NewAlerter(bus *event.Bus, repo SubscriberRepository, store AlertStorer, cfg AlertConfig) (*Alerter, error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultAlertInterval
+	}
+
+	a := Alerter{
+		bus:     bus,
+		repo:    repo,
+		store:   store,
+		cfg:     cfg,
+		states:  make(map[string]*AlertState),
+		samples: make(map[Topic][]Sample),
+	}
+
+	snaps, err := store.FetchAll()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("loading alert states: %w", err)
+	}
+
+	if len(snaps) > 0 {
+		a.restore(snaps[len(snaps)-1])
+	}
+
+	a.bus.Subscribe(event.New(EventSource, EventKindRateFetched, nil), a.EvaluateAlerts)
+
+	return &a, nil
+}
+
+// Run saves the alert states at the interval until the context is cancelled and once more then.
+func (a *Alerter) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = a.Save()
+			return
+
+		case <-ticker.C:
+			// Failures are retried on the next tick, so they are not reported anywhere else.
+			_ = a.Save()
+		}
+	}
+}
+
+// Save persists the alert states if they have changed since saved last.
+func (a *Alerter) Save() error {
+	a.mu.Lock()
+	if !a.dirty {
+		a.mu.Unlock()
+		return nil
+	}
+
+	snap := a.snapshot()
+	a.dirty = false
+	a.mu.Unlock()
+
+	if err := a.store.Replace(snap); err != nil {
+		a.mu.Lock()
+		a.dirty = true
+		a.mu.Unlock()
+
+		return fmt.Errorf("saving alert states: %w", err)
+	}
+
+	return nil
+}
+
+// snapshot copies the alert states, so they can be saved without holding the lock.
+func (a *Alerter) snapshot() AlertSnapshot {
+	snap := AlertSnapshot{
+		States:  make(map[string]AlertState, len(a.states)),
+		Samples: make(map[string][]Sample, len(a.samples)),
+	}
+
+	for key, st := range a.states {
+		snap.States[key] = *st
+	}
+
+	for topic, samples := range a.samples {
+		snap.Samples[topic.Base+"/"+topic.Quote] = append([]Sample(nil), samples...)
+	}
+
+	return snap
+}
+
+func (a *Alerter) restore(snap AlertSnapshot) {
+	for key, st := range snap.States {
+		st := st
+		a.states[key] = &st
+	}
+
+	for key, samples := range snap.Samples {
+		if base, quote, ok := strings.Cut(key, "/"); ok {
+			a.samples[NewTopic(base, quote)] = samples
+		}
+	}
+}
+
+// EvaluateAlerts handles an event of the fetched rate evaluating the alerts subscribed to its pair.
+func (a *Alerter) EvaluateAlerts(ctx context.Context, e event.Event) error {
+	req, ok := e.Payload.(FetchedRateEvent)
+	if !ok {
+		return fmt.Errorf("%w: unexpected payload: %T", ErrInvalidEvent, e.Payload)
+	}
+
+	if req.Rate() <= 0 {
+		return nil
+	}
+
+	topic := NewTopic(req.BaseCurrency(), req.QuoteCurrency())
+	now := time.Now()
+
+	subss, err := a.alerting(ctx, topic, now)
+	if err != nil {
+		return fmt.Errorf("evaluating alerts: %w", err)
+	}
+
+	for _, alerted := range a.Evaluate(topic, req.Rate(), now, subss...) {
+		if err := a.bus.Publish(ctx, event.New(EventSource, EventKindAlerted, alerted)); err != nil {
+			return fmt.Errorf("publishing alerted event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// alerting returns the confirmed subscriptions having alerts on the topic,
+// the index is reloaded from the repository once it is older than the interval.
+func (a *Alerter) alerting(ctx context.Context, topic Topic, at time.Time) ([]Subscription, error) {
+	a.idxMu.Lock()
+	defer a.idxMu.Unlock()
+
+	if a.index == nil || at.Sub(a.indexed) >= a.cfg.Interval {
+		subss, err := a.repo.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		index := make(map[Topic][]Subscription)
+
+		for _, subs := range subss {
+			if len(subs.Alerts) > 0 {
+				index[subs.Topic] = append(index[subs.Topic], subs)
+			}
+		}
+
+		a.index, a.indexed = index, at
+	}
+
+	return a.index[topic], nil
+}
+
+// Evaluate records the rate of the topic and returns the alerts of the subscriptions triggered by it.
+func (a *Alerter) Evaluate(topic Topic, value float64, at time.Time, subss ...Subscription) []Alerted {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var list []Alerted
+
+	samples := a.record(topic, value, at)
+	a.dirty = true
+
+	for _, subs := range subss {
+		// The alerts still triggered once the quiet hours end are notified then.
+		if subs.Topic != topic || subs.Subscriber.Preferences.Quiet(at) {
+			continue
+		}
+
+		for _, alert := range subs.Alerts {
+			var change float64
+
+			if alert.Kind == AlertChange {
+				change = changeWithin(samples, alert.Window, at)
+			}
+
+			key := subs.Subscriber.Address.Address + "|" + topic.Base + "/" + topic.Quote + "|" + alert.String()
+
+			st, ok := a.states[key]
+			if !ok {
+				st = new(AlertState)
+				a.states[key] = st
+			}
+
+			if st.Fired && a.rearmed(alert, value, change) {
+				st.Fired = false
+			}
+
+			if st.Fired || !triggered(alert, value, change) || at.Sub(st.Notified) < a.cfg.Cooldown {
+				continue
+			}
+
+			st.Fired, st.Notified = true, at
+
+			list = append(list, Alerted{Subscriber: subs.Subscriber, Topic: topic, Alert: alert, Rate: value, Change: change})
+		}
+	}
+
+	a.prune(topic, changeWindow(topic, subss...), at)
+
+	return list
+}
+
+// changeWindow returns the longest window of the change alerts on the topic,
+// the ones within the quiet hours included, so their samples are kept until the quiet hours end.
+func changeWindow(topic Topic, subss ...Subscription) time.Duration {
+	var window time.Duration
+
+	for _, subs := range subss {
+		if subs.Topic != topic {
+			continue
+		}
+
+		for _, alert := range subs.Alerts {
+			if alert.Kind == AlertChange {
+				window = maxDuration(window, alert.Window)
+			}
+		}
+	}
+
+	return window
+}
+
+// record adds the rate to the samples of the topic and returns them.
+func (a *Alerter) record(topic Topic, value float64, at time.Time) []Sample {
+	samples := a.samples[topic]
+
+	if n := len(samples); n > 1 && at.Sub(samples[n-2].At) < sampleSpacing {
+		samples[n-1] = Sample{Value: value, At: at}
+	} else {
+		samples = append(samples, Sample{Value: value, At: at})
+	}
+
+	a.samples[topic] = samples
+
+	return samples
+}
+
+// prune drops the samples of the topic older than the longest window of its change alerts.
+func (a *Alerter) prune(topic Topic, window time.Duration, at time.Time) {
+	samples := a.samples[topic]
+
+	var n int
+	for n < len(samples)-1 && at.Sub(samples[n].At) > window {
+		n++
+	}
+
+	a.samples[topic] = append(samples[:0], samples[n:]...)
+}
+
+// rearmed reports whether the rate moved back past the threshold by the hysteresis.
+func (a *Alerter) rearmed(alert Alert, value, change float64) bool {
+	switch alert.Kind {
+	case AlertAbove:
+		return value < alert.Threshold*(1-a.cfg.Hysteresis)
+	case AlertBelow:
+		return value > alert.Threshold*(1+a.cfg.Hysteresis)
+	case AlertChange:
+		return math.Abs(change) < alert.Threshold*(1-a.cfg.Hysteresis)
+	}
+
+	return false
+}
+
+func triggered(alert Alert, value, change float64) bool {
+	switch alert.Kind {
+	case AlertAbove:
+		return value >= alert.Threshold
+	case AlertBelow:
+		return value <= alert.Threshold
+	case AlertChange:
+		return math.Abs(change) >= alert.Threshold
+	}
+
+	return false
+}
+
+// changeWithin returns the relative change of the latest rate from the earliest one within the window.
+func changeWithin(samples []Sample, window time.Duration, at time.Time) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	last := samples[len(samples)-1].Value
+
+	for _, s := range samples {
+		if at.Sub(s.At) <= window {
+			return (last - s.Value) / s.Value
+		}
+	}
+
+	return 0
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,185 @@
+package subs_test
+
+import (
+	"context"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/subs"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/filestore"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlerterEvaluate(t *testing.T) {
+	topic := subs.NewTopic("BTC", "UAH")
+	start := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	type rate struct {
+		value float64
+		after time.Duration
+	}
+
+	tests := map[string]struct {
+		cfg   subs.AlertConfig
+		alert subs.Alert
+		prefs subs.Preferences
+		rates []rate
+		want  []float64
+	}{
+		"above_once_while_oscillating": {
+			cfg:   subs.AlertConfig{Hysteresis: 0.01},
+			alert: subs.Alert{Kind: subs.AlertAbove, Threshold: 100},
+			rates: []rate{{99, 0}, {100, time.Minute}, {99.5, 2 * time.Minute}, {100.5, 3 * time.Minute}},
+			want:  []float64{100},
+		},
+		"above_rearmed_past_hysteresis": {
+			cfg:   subs.AlertConfig{Hysteresis: 0.01},
+			alert: subs.Alert{Kind: subs.AlertAbove, Threshold: 100},
+			rates: []rate{{101, 0}, {98, time.Minute}, {102, 2 * time.Minute}},
+			want:  []float64{101, 102},
+		},
+		"below_delayed_by_cooldown": {
+			cfg:   subs.AlertConfig{Hysteresis: 0.01, Cooldown: time.Hour},
+			alert: subs.Alert{Kind: subs.AlertBelow, Threshold: 100},
+			rates: []rate{{99, 0}, {105, time.Minute}, {98, 2 * time.Minute}, {97, time.Hour}},
+			want:  []float64{99, 97},
+		},
+		"change_within_window": {
+			alert: subs.Alert{Kind: subs.AlertChange, Threshold: 0.05, Window: 24 * time.Hour},
+			rates: []rate{{100, 0}, {104, time.Hour}, {95, 2 * time.Hour}},
+			want:  []float64{95},
+		},
+		"change_outside_window": {
+			alert: subs.Alert{Kind: subs.AlertChange, Threshold: 0.05, Window: time.Hour},
+			rates: []rate{{100, 0}, {102, 2 * time.Hour}, {106, 3 * time.Hour}},
+			want:  nil,
+		},
+		"change_kept_through_quiet_hours": {
+			alert: subs.Alert{Kind: subs.AlertChange, Threshold: 0.05, Window: 24 * time.Hour},
+			prefs: subs.Preferences{QuietFrom: "00:00", QuietTo: "03:00"},
+			rates: []rate{{100, 0}, {96, time.Hour}, {94, 4 * time.Hour}},
+			want:  []float64{94},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+			alerter, err := subs.NewAlerter(bus, &mock.SubscriberRepositoryMock{}, filestore.NewAppendLog[subs.AlertSnapshot](t.TempDir()), tc.cfg)
+			require.NoError(t, err)
+
+			sub := subs.Subscription{
+				Subscriber: subs.NewSubscriber(&mail.Address{Address: "user@example.com"}),
+				Topic:      topic,
+				Alerts:     []subs.Alert{tc.alert},
+			}
+			sub.Subscriber.Preferences = tc.prefs
+
+			var got []float64
+
+			for _, r := range tc.rates {
+				for _, alerted := range alerter.Evaluate(topic, r.value, start.Add(r.after), sub) {
+					require.Equal(t, tc.alert, alerted.Alert)
+					got = append(got, alerted.Rate)
+				}
+			}
+
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestAlerterSave(t *testing.T) {
+	topic := subs.NewTopic("BTC", "UAH")
+	start := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	store := filestore.NewAppendLog[subs.AlertSnapshot](t.TempDir())
+	cfg := subs.AlertConfig{Hysteresis: 0.01, Cooldown: time.Hour}
+
+	sub := subs.Subscription{
+		Subscriber: subs.NewSubscriber(&mail.Address{Address: "user@example.com"}),
+		Topic:      topic,
+		Alerts:     []subs.Alert{{Kind: subs.AlertAbove, Threshold: 100}, {Kind: subs.AlertChange, Threshold: 0.05, Window: time.Hour}},
+	}
+
+	bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+
+	alerter, err := subs.NewAlerter(bus, &mock.SubscriberRepositoryMock{}, store, cfg)
+	require.NoError(t, err)
+	require.Len(t, alerter.Evaluate(topic, 101, start, sub), 1)
+	require.NoError(t, alerter.Save())
+
+	restarted, err := subs.NewAlerter(bus, &mock.SubscriberRepositoryMock{}, store, cfg)
+	require.NoError(t, err)
+
+	// The fired alert stays fired and the change is measured from the rate seen before the restart.
+	got := restarted.Evaluate(topic, 107, start.Add(time.Minute), sub)
+	require.Len(t, got, 1)
+	require.Equal(t, subs.AlertChange, got[0].Alert.Kind)
+}
+
+func TestAlerterEvaluateAlerts(t *testing.T) {
+	topic := subs.NewTopic("BTC", "UAH")
+
+	var lists int
+
+	repo := &mock.SubscriberRepositoryMock{
+		ListFunc: func(context.Context) ([]subs.Subscription, error) {
+			lists++
+
+			return []subs.Subscription{{
+				Subscriber: subs.NewSubscriber(&mail.Address{Address: "user@example.com"}),
+				Topic:      topic,
+				Alerts:     []subs.Alert{{Kind: subs.AlertAbove, Threshold: 100}},
+			}}, nil
+		},
+	}
+
+	bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+	store := filestore.NewAppendLog[subs.AlertSnapshot](t.TempDir())
+
+	alerter, err := subs.NewAlerter(bus, repo, store, subs.AlertConfig{Interval: time.Hour})
+	require.NoError(t, err)
+
+	for _, val := range []float64{99, 101, 102} {
+		e := event.New(subs.EventSource, subs.EventKindRateFetched, fetchedRate{base: topic.Base, quote: topic.Quote, rate: val})
+		require.NoError(t, alerter.EvaluateAlerts(context.Background(), e))
+	}
+
+	require.Equal(t, 1, lists)
+}
+
+type fetchedRate struct {
+	base, quote string
+	rate        float64
+}
+
+func (r fetchedRate) BaseCurrency() string  { return r.base }
+func (r fetchedRate) QuoteCurrency() string { return r.quote }
+func (r fetchedRate) Rate() float64         { return r.rate }
+
+func TestAlertValidate(t *testing.T) {
+	tests := map[string]struct {
+		alert   subs.Alert
+		wantErr error
+	}{
+		"valid_above":        {alert: subs.Alert{Kind: subs.AlertAbove, Threshold: 1_500_000}},
+		"valid_change":       {alert: subs.Alert{Kind: subs.AlertChange, Threshold: 0.05, Window: 24 * time.Hour}},
+		"unknown_kind":       {alert: subs.Alert{Kind: "sideways", Threshold: 1}, wantErr: subs.ErrInvalidAlert},
+		"negative_threshold": {alert: subs.Alert{Kind: subs.AlertBelow, Threshold: -1}, wantErr: subs.ErrInvalidAlert},
+		"missing_window":     {alert: subs.Alert{Kind: subs.AlertChange, Threshold: 0.05}, wantErr: subs.ErrInvalidAlert},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, tc.alert.Validate(), tc.wantErr)
+		})
+	}
+}
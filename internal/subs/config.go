@@ -2,4 +2,8 @@ package subs
 
 type Config struct {
 	RepoData string
+	Alert    AlertConfig
+	Token    TokenConfig
+	Confirm  ConfirmConfig
+	Digest   DigestConfig
 }
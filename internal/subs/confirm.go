@@ -0,0 +1,101 @@
+package subs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EventKindConfirmationRequested is the request for the confirmation message of a new subscription.
+const EventKindConfirmationRequested = "confirmation_requested"
+
+// ConfirmConfig defines how long the new subscriptions await the confirmation and how often it is sent.
+type ConfirmConfig struct {
+	// Window is the duration the confirmation link is valid for, the pending subscription is purged afterwards.
+	Window time.Duration
+	// Purge is the interval the expired pending subscriptions are purged at.
+	Purge time.Duration
+	// Resend is the minimum interval between the confirmation messages sent for the same pending subscription.
+	Resend time.Duration
+}
+
+// Confirmation represents the data of a confirmation request event.
+type Confirmation struct {
+	Subscription Subscription
+}
+
+// Subscribers implements SubscribersEvent.
+func (c Confirmation) Subscribers() []string {
+	return Subscriptions{c.Subscription}.Subscribers()
+}
+
+// BaseCurrency implements CurrencyPairEvent.
+func (c Confirmation) BaseCurrency() string {
+	return c.Subscription.Topic.Base
+}
+
+// QuoteCurrency implements CurrencyPairEvent.
+func (c Confirmation) QuoteCurrency() string {
+	return c.Subscription.Topic.Quote
+}
+
+// Purger removes the pending subscriptions which were not confirmed within the window.
+type Purger struct {
+	repo SubscriberRepository
+	cfg  ConfirmConfig
+}
+
+// NewPurger creates a new Purger instance.
+func NewPurger(repo SubscriberRepository, cfg ConfirmConfig) *Purger {
+	return &Purger{repo: repo, cfg: cfg}
+}
+
+// Run purges the expired pending subscriptions at the interval until the context is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	if p.cfg.Purge <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.cfg.Purge)
+	defer ticker.Stop()
+
+	for {
+		// Failures are retried on the next tick, so they are not reported anywhere else.
+		_, _ = p.Purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge removes the pending subscriptions requested before the window and returns their number.
+func (p *Purger) Purge(ctx context.Context) (int, error) {
+	pending, err := p.repo.ListPending(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing pending subscriptions: %w", err)
+	}
+
+	var (
+		n    int
+		errs []error
+	)
+
+	for _, subs := range pending {
+		if time.Since(subs.CreatedAt) <= p.cfg.Window {
+			continue
+		}
+
+		if err := p.repo.Remove(ctx, subs); err != nil && !errors.Is(err, ErrNotFound) {
+			errs = append(errs, err)
+			continue
+		}
+
+		n++
+	}
+
+	return n, errors.Join(errs...)
+}
@@ -0,0 +1,154 @@
+package subs_test
+
+import (
+	"context"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/subs"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testSubscription(addr string, pending bool, created time.Time) subs.Subscription {
+	return subs.Subscription{
+		Subscriber: subs.NewSubscriber(&mail.Address{Address: addr}),
+		Topic:      subs.NewTopic("BTC", "UAH"),
+		Pending:    pending,
+		CreatedAt:  created,
+	}
+}
+
+func TestServiceSubscribe(t *testing.T) {
+	tests := map[string]struct {
+		stored     []subs.Subscription
+		wantAdd    bool
+		wantUpdate bool
+		wantSent   bool
+		wantErr    error
+	}{
+		"new_pending": {
+			stored:   []subs.Subscription{testSubscription("other@example.com", false, time.Now())},
+			wantAdd:  true,
+			wantSent: true,
+		},
+		"pending_renewed": {
+			stored:     []subs.Subscription{testSubscription("user@example.com", true, time.Now().Add(-time.Hour))},
+			wantUpdate: true,
+			wantSent:   true,
+		},
+		"pending_resend_throttled": {
+			stored: []subs.Subscription{testSubscription("user@example.com", true, time.Now().Add(-time.Second))},
+		},
+		"confirmed_exists": {
+			stored:  []subs.Subscription{testSubscription("User@example.com", false, time.Now())},
+			wantErr: subs.ErrSubscriptionExists,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			check := func(ctx context.Context, sub subs.Subscription) error {
+				require.NotEmpty(t, sub.ID)
+				require.True(t, sub.Pending)
+				require.False(t, sub.CreatedAt.IsZero())
+
+				return nil
+			}
+
+			repo := &mock.SubscriberRepositoryMock{
+				AddFunc:     check,
+				ListAllFunc: func(ctx context.Context) ([]subs.Subscription, error) { return tc.stored, nil },
+				UpdateFunc:  check,
+			}
+
+			sent := make(chan event.Event, 1)
+
+			bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+			bus.Subscribe(event.New(subs.EventSource, subs.EventKindConfirmationRequested, nil), func(_ context.Context, e event.Event) error {
+				sent <- e
+				return nil
+			})
+
+			svc := subs.NewService(bus, repo, testRegistry(), subs.ConfirmConfig{Resend: time.Minute})
+
+			err := svc.Subscribe(context.Background(), testSubscription("user@example.com", false, time.Time{}))
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantAdd, len(repo.AddCalls()) == 1)
+			require.Equal(t, tc.wantUpdate, len(repo.UpdateCalls()) == 1)
+
+			if tc.wantSent {
+				require.Eventually(t, func() bool { return len(sent) == 1 }, time.Second, time.Millisecond)
+			} else {
+				require.Empty(t, sent)
+			}
+
+			if tc.wantUpdate {
+				require.Equal(t, tc.stored[0].Key(), repo.UpdateCalls()[0].Subscription.ID)
+			}
+		})
+	}
+}
+
+func TestServiceConfirm(t *testing.T) {
+	tests := map[string]struct {
+		stored     []subs.Subscription
+		wantUpdate bool
+		wantErr    error
+	}{
+		"pending_confirmed": {
+			stored:     []subs.Subscription{testSubscription("user@example.com", true, time.Now())},
+			wantUpdate: true,
+		},
+		"confirmed_again": {
+			stored: []subs.Subscription{testSubscription("user@example.com", false, time.Now())},
+		},
+		"missing": {
+			stored:  []subs.Subscription{testSubscription("other@example.com", true, time.Now())},
+			wantErr: subs.ErrNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			repo := &mock.SubscriberRepositoryMock{
+				ListAllFunc: func(ctx context.Context) ([]subs.Subscription, error) { return tc.stored, nil },
+				UpdateFunc: func(ctx context.Context, sub subs.Subscription) error {
+					require.False(t, sub.Pending)
+					return nil
+				},
+			}
+
+			svc := subs.NewService(event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug))), repo, testRegistry(), subs.ConfirmConfig{})
+
+			err := svc.Confirm(context.Background(), testSubscription("USER@example.com", false, time.Time{}))
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantUpdate, len(repo.UpdateCalls()) == 1)
+		})
+	}
+}
+
+func TestPurgerPurge(t *testing.T) {
+	expired := testSubscription("expired@example.com", true, time.Now().Add(-25*time.Hour))
+	fresh := testSubscription("fresh@example.com", true, time.Now().Add(-time.Hour))
+
+	repo := &mock.SubscriberRepositoryMock{
+		ListPendingFunc: func(ctx context.Context) ([]subs.Subscription, error) {
+			return []subs.Subscription{expired, fresh}, nil
+		},
+		RemoveFunc: func(ctx context.Context, sub subs.Subscription) error { return nil },
+	}
+
+	n, err := subs.NewPurger(repo, subs.ConfirmConfig{Window: 24 * time.Hour}).Purge(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, repo.RemoveCalls(), 1)
+	require.Equal(t, expired.Key(), repo.RemoveCalls()[0].Subscription.Key())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
 )
@@ -12,6 +13,9 @@ const (
 	EventSource        = "subs"
 	EventKindRequested = "requested"
 	EventKindResponded = "responded"
+	// EventKindTopicsRequested is the request for the topics having subscribers.
+	EventKindTopicsRequested = "topics_requested"
+	EventKindTopicsResponded = "topics_responded"
 )
 
 var ErrInvalidEvent = errors.New("invalid event")
@@ -31,7 +35,30 @@ type CurrencyPairEvent interface {
 	QuoteCurrency() string
 }
 
+// BroadcastEvent is a subscription event requesting the subscribers the broadcast is sent to.
+type BroadcastEvent interface {
+	CurrencyPairEvent
+	Broadcast() bool
+}
+
+// RespondTopics handles an event requesting the topics having subscribers.
+func (svc *Service) RespondTopics(ctx context.Context, e event.Event) error {
+	topics, err := svc.Topics(ctx)
+	if err != nil {
+		return fmt.Errorf("responding topics event: %w", err)
+	}
+
+	if e.Response == nil {
+		return fmt.Errorf("responding topics event: %w", ErrInvalidEvent)
+	}
+
+	e.Response <- event.New(EventSource, EventKindTopicsResponded, topics)
+
+	return nil
+}
+
 // RespondSubscription handles an event send subscriptions data.
+// The subscriptions held back from the broadcast by the quiet hours are queued only when the event requests it.
 func (svc *Service) RespondSubscription(ctx context.Context, e event.Event) error {
 	req, ok := e.Payload.(CurrencyPairEvent)
 	if !ok {
@@ -47,7 +74,17 @@ func (svc *Service) RespondSubscription(ctx context.Context, e event.Event) erro
 		return fmt.Errorf("responding exchange rate event: %w", ErrInvalidEvent)
 	}
 
-	e.Response <- event.New(EventSource, EventKindResponded, Subscriptions(subss))
+	now := time.Now()
+
+	e.Response <- event.New(EventSource, EventKindResponded, Deliverable(now, subss...))
+
+	if b, ok := req.(BroadcastEvent); !ok || !b.Broadcast() {
+		return nil
+	}
+
+	if err := svc.queue(ctx, now, subss...); err != nil {
+		return fmt.Errorf("queueing held back broadcast: %w", err)
+	}
 
 	return nil
 }
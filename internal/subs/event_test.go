@@ -89,7 +89,7 @@ func TestServiceRespondSubscription(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			svc := subs.NewService(bus, tc.repo)
+			svc := subs.NewService(bus, tc.repo, testRegistry(), subs.ConfirmConfig{})
 
 			err := svc.RespondSubscription(context.Background(), tc.event)
 			require.ErrorIs(t, err, tc.wantErr)
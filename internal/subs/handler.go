@@ -3,24 +3,55 @@ package subs
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/mail"
+	"strconv"
+	"time"
 
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
 )
 
-const StatusSubscribed = "subscribed"
+const (
+	StatusPending      = "confirmation pending"
+	StatusSubscribed   = "subscribed"
+	StatusUnsubscribed = "unsubscribed"
+)
+
+const (
+	stateActive  = "active"
+	statePending = "pending"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
 
 //go:generate moq -out=../../test/mock/subscriber.go -pkg=mock . SubscriptionService
 
 // SubscriptionService is an interface for subscription service.
 type SubscriptionService interface {
 	Subscribe(context.Context, Subscription) error
+	Confirm(context.Context, Subscription) error
+	Unsubscribe(context.Context, Subscription) error
+	Query(context.Context, Filter) ([]Subscription, int, error)
+	Get(ctx context.Context, id string) (Subscription, error)
+	Change(ctx context.Context, id string, chg Changes) (Subscription, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// TokenVerifier is an interface for verifying the tokens of the subscription links.
+type TokenVerifier interface {
+	Verify(purpose, token string) (Subscription, error)
 }
 
 // Handler handles subscription.
 type Handler struct {
 	SubscriptionService
+	tokens TokenVerifier
+	reg    *currency.Registry
 }
 
 // Request is a request for subscription.
@@ -28,6 +59,59 @@ type Request struct {
 	Email         string `json:"email"`
 	BaseCurrency  string `json:"base_currency"`
 	QuoteCurrency string `json:"quote_currency"`
+	// Alerts are optional, like {"kind": "above", "threshold": 1500000} or {"kind": "change", "threshold": 0.05, "window": "24h"}.
+	Alerts []AlertRequest `json:"alerts,omitempty"`
+	// Preferences are optional, like {"frequency": "daily", "at": "09:00", "timezone": "Europe/Kyiv"}.
+	Preferences *PreferencesRequest `json:"preferences,omitempty"`
+}
+
+// AlertRequest is a request for an alert rule of subscription.
+type AlertRequest struct {
+	Kind      string  `json:"kind"`
+	Threshold float64 `json:"threshold"`
+	Window    string  `json:"window,omitempty"`
+}
+
+// PreferencesRequest is a request for the delivery preferences of subscription.
+type PreferencesRequest struct {
+	Frequency string `json:"frequency,omitempty"`
+	At        string `json:"at,omitempty"`
+	Timezone  string `json:"timezone,omitempty"`
+	QuietFrom string `json:"quiet_from,omitempty"`
+	QuietTo   string `json:"quiet_to,omitempty"`
+	Channel   string `json:"channel,omitempty"`
+}
+
+// ChangeRequest is a request for changing subscription, the omitted fields are left as they are.
+type ChangeRequest struct {
+	BaseCurrency  string `json:"base_currency,omitempty"`
+	QuoteCurrency string `json:"quote_currency,omitempty"`
+	// Alerts replace the alert rules when present, an empty list drops them.
+	Alerts *[]AlertRequest `json:"alerts,omitempty"`
+	// Preferences replace the delivery preferences when present.
+	Preferences *PreferencesRequest `json:"preferences,omitempty"`
+}
+
+// SubscriptionResponse is a response of a subscription resource.
+type SubscriptionResponse struct {
+	ID            string             `json:"id"`
+	Email         string             `json:"email"`
+	BaseCurrency  string             `json:"base_currency"`
+	QuoteCurrency string             `json:"quote_currency"`
+	Alerts        []AlertRequest     `json:"alerts"`
+	Preferences   PreferencesRequest `json:"preferences"`
+	// Status is either "active" or "pending" until the subscriber confirms the subscription.
+	Status string `json:"status"`
+	// CreatedAt is omitted for the subscriptions stored before it was tracked.
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// ListResponse is a response of a page of subscription resources.
+type ListResponse struct {
+	Subscriptions []SubscriptionResponse `json:"subscriptions"`
+	Total         int                    `json:"total"`
+	Offset        int                    `json:"offset"`
+	Limit         int                    `json:"limit"`
 }
 
 // Response is a response for subscription.
@@ -36,15 +120,15 @@ type Response struct {
 }
 
 // NewHandler returns a new Handler instance.
-func NewHandler(ss SubscriptionService) *Handler {
-	return &Handler{SubscriptionService: ss}
+func NewHandler(ss SubscriptionService, tokens TokenVerifier, reg *currency.Registry) *Handler {
+	return &Handler{SubscriptionService: ss, tokens: tokens, reg: reg}
 }
 
 func NewResponse(msg string) *Response {
 	return &Response{Message: msg}
 }
 
-func toSubscription(req *Request) (Subscription, error) {
+func toSubscription(req *Request, reg *currency.Registry) (Subscription, error) {
 	email, err := mail.ParseAddress(req.Email)
 	if err != nil {
 		return Subscription{}, errors.Join(err, ErrMissingEmail)
@@ -55,10 +139,154 @@ func toSubscription(req *Request) (Subscription, error) {
 		Topic:      NewTopic(req.BaseCurrency, req.QuoteCurrency),
 	}
 
+	if err := subs.Topic.ValidateWith(reg); err != nil {
+		return Subscription{}, err
+	}
+
+	if len(req.Alerts) > 0 {
+		if subs.Alerts, err = toAlerts(req.Alerts); err != nil {
+			return Subscription{}, err
+		}
+	}
+
+	if req.Preferences != nil {
+		if subs.Subscriber.Preferences, err = toPreferences(*req.Preferences); err != nil {
+			return Subscription{}, err
+		}
+	}
+
 	return subs, nil
 }
 
-// Subscribe subscribes to e-mails.
+func toAlerts(reqs []AlertRequest) ([]Alert, error) {
+	alerts := make([]Alert, 0, len(reqs))
+
+	for _, ar := range reqs {
+		alert, err := toAlert(ar)
+		if err != nil {
+			return nil, err
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func toAlert(req AlertRequest) (Alert, error) {
+	alert := Alert{Kind: req.Kind, Threshold: req.Threshold}
+
+	if req.Window != "" {
+		window, err := time.ParseDuration(req.Window)
+		if err != nil {
+			return Alert{}, fmt.Errorf("%w: parsing window: %w", ErrInvalidAlert, err)
+		}
+
+		alert.Window = window
+	}
+
+	if err := alert.Validate(); err != nil {
+		return Alert{}, err
+	}
+
+	return alert, nil
+}
+
+func toPreferences(req PreferencesRequest) (Preferences, error) {
+	prefs := Preferences(req)
+
+	if err := prefs.Validate(); err != nil {
+		return Preferences{}, err
+	}
+
+	return prefs, nil
+}
+
+func toChanges(req *ChangeRequest) (Changes, error) {
+	chg := Changes{Base: req.BaseCurrency, Quote: req.QuoteCurrency}
+
+	if req.Alerts != nil {
+		alerts, err := toAlerts(*req.Alerts)
+		if err != nil {
+			return Changes{}, err
+		}
+
+		chg.Alerts = &alerts
+	}
+
+	if req.Preferences != nil {
+		prefs, err := toPreferences(*req.Preferences)
+		if err != nil {
+			return Changes{}, err
+		}
+
+		chg.Preferences = &prefs
+	}
+
+	return chg, nil
+}
+
+func toFilter(req *http.Request) (Filter, error) {
+	f := Filter{
+		Email: web.FromQuery(req, "email"),
+		Topic: NewTopic(web.FromQuery(req, "base"), web.FromQuery(req, "quote")),
+		Limit: defaultPageLimit,
+	}
+
+	var err error
+
+	if val := web.FromQuery(req, "offset"); val != "" {
+		if f.Offset, err = strconv.Atoi(val); err != nil || f.Offset < 0 {
+			return Filter{}, fmt.Errorf("%w: offset %q", ErrInvalidPage, val)
+		}
+	}
+
+	if val := web.FromQuery(req, "limit"); val != "" {
+		if f.Limit, err = strconv.Atoi(val); err != nil || f.Limit < 1 || f.Limit > maxPageLimit {
+			return Filter{}, fmt.Errorf("%w: limit %q must be from 1 to %d", ErrInvalidPage, val, maxPageLimit)
+		}
+	}
+
+	return f, nil
+}
+
+func toResponse(subs Subscription) SubscriptionResponse {
+	resp := SubscriptionResponse{
+		ID:            subs.ID,
+		BaseCurrency:  subs.Topic.Base,
+		QuoteCurrency: subs.Topic.Quote,
+		Alerts:        make([]AlertRequest, len(subs.Alerts)),
+		Status:        stateActive,
+	}
+
+	prefs := subs.Subscriber.Preferences
+	resp.Preferences = PreferencesRequest(prefs)
+	resp.Preferences.Frequency = defaultString(prefs.Frequency, FrequencyInstant)
+	resp.Preferences.Channel = defaultString(prefs.Channel, ChannelEmail)
+
+	if subs.Subscriber.Address != nil {
+		resp.Email = subs.Subscriber.Address.Address
+	}
+
+	for i, alert := range subs.Alerts {
+		resp.Alerts[i] = AlertRequest{Kind: alert.Kind, Threshold: alert.Threshold}
+		if alert.Window > 0 {
+			resp.Alerts[i].Window = alert.Window.String()
+		}
+	}
+
+	if subs.Pending {
+		resp.Status = statePending
+	}
+
+	if !subs.CreatedAt.IsZero() {
+		resp.CreatedAt = subs.CreatedAt.UTC().Format(time.RFC3339)
+	}
+
+	return resp
+}
+
+// Subscribe subscribes to e-mails once the subscriber confirms it by the link sent to them.
 func (h *Handler) Subscribe(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
@@ -68,7 +296,7 @@ func (h *Handler) Subscribe(ctx context.Context, rw http.ResponseWriter, req *ht
 		return err
 	}
 
-	subs, err := toSubscription(&request)
+	subs, err := toSubscription(&request, h.reg)
 	if err != nil {
 		return web.NewRequestError(err, http.StatusBadRequest)
 	}
@@ -84,5 +312,188 @@ func (h *Handler) Subscribe(ctx context.Context, rw http.ResponseWriter, req *ht
 		return err
 	}
 
-	return web.Respond(ctx, rw, NewResponse(StatusSubscribed), http.StatusCreated)
+	return web.Respond(ctx, rw, NewResponse(StatusPending), http.StatusCreated)
+}
+
+// Confirm activates the pending subscription by the signed token of the link sent to the subscriber.
+func (h *Handler) Confirm(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	subs, err := h.verify(TokenConfirm, req)
+	if err != nil {
+		return err
+	}
+
+	if err := h.SubscriptionService.Confirm(ctx, subs); err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, context.DeadlineExceeded):
+			return web.NewRequestError(err, http.StatusRequestTimeout)
+		}
+
+		return err
+	}
+
+	return web.Respond(ctx, rw, NewResponse(StatusSubscribed), http.StatusOK)
+}
+
+// Unsubscribe unsubscribes from e-mails.
+func (h *Handler) Unsubscribe(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var request Request
+	if err := web.DecodeBody(req.Body, &request); err != nil {
+		return err
+	}
+
+	subs, err := toSubscription(&request, h.reg)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	return h.unsubscribe(ctx, rw, subs)
+}
+
+// UnsubscribeLink unsubscribes from e-mails by the signed token of the link embedded in the notifications.
+// It serves both the link followed by the subscriber and the one-click unsubscribe POST of the mail clients.
+func (h *Handler) UnsubscribeLink(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	subs, err := h.verify(TokenUnsubscribe, req)
+	if err != nil {
+		return err
+	}
+
+	return h.unsubscribe(ctx, rw, subs)
+}
+
+// verify returns the subscription identified by the token of the request query.
+func (h *Handler) verify(purpose string, req *http.Request) (Subscription, error) {
+	subs, err := h.tokens.Verify(purpose, req.URL.Query().Get("token"))
+	if err != nil {
+		if errors.Is(err, ErrExpiredToken) {
+			return Subscription{}, web.NewRequestError(err, http.StatusGone)
+		}
+
+		return Subscription{}, web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	return subs, nil
+}
+
+func (h *Handler) unsubscribe(ctx context.Context, rw http.ResponseWriter, subs Subscription) error {
+	if err := h.SubscriptionService.Unsubscribe(ctx, subs); err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return web.NewRequestError(err, http.StatusNotFound)
+		case errors.Is(err, context.DeadlineExceeded):
+			return web.NewRequestError(err, http.StatusRequestTimeout)
+		}
+
+		return err
+	}
+
+	return web.Respond(ctx, rw, NewResponse(StatusUnsubscribed), http.StatusOK)
+}
+
+// ListSubscriptions responds with the page of the subscriptions filtered by the email, base and quote query parameters,
+// paginated by the offset and limit ones.
+func (h *Handler) ListSubscriptions(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	f, err := toFilter(req)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	subss, total, err := h.SubscriptionService.Query(ctx, f)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return web.NewRequestError(err, http.StatusRequestTimeout)
+		}
+
+		return err
+	}
+
+	resp := ListResponse{
+		Subscriptions: make([]SubscriptionResponse, len(subss)),
+		Total:         total,
+		Offset:        f.Offset,
+		Limit:         f.Limit,
+	}
+
+	for i := range subss {
+		resp.Subscriptions[i] = toResponse(subss[i])
+	}
+
+	return web.Respond(ctx, rw, resp, http.StatusOK)
+}
+
+// GetSubscription responds with the subscription of the ID path parameter.
+func (h *Handler) GetSubscription(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	subs, err := h.SubscriptionService.Get(ctx, web.FromPath(req, "id"))
+	if err != nil {
+		return resourceError(err)
+	}
+
+	return web.Respond(ctx, rw, toResponse(subs), http.StatusOK)
+}
+
+// ChangeSubscription changes the topic, the alert rules or the delivery preferences of the subscription of the ID path parameter.
+func (h *Handler) ChangeSubscription(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	var request ChangeRequest
+	if err := web.DecodeBody(req.Body, &request); err != nil {
+		return err
+	}
+
+	chg, err := toChanges(&request)
+	if err != nil {
+		return web.NewRequestError(err, http.StatusBadRequest)
+	}
+
+	subs, err := h.SubscriptionService.Change(ctx, web.FromPath(req, "id"), chg)
+	if err != nil {
+		return resourceError(err)
+	}
+
+	return web.Respond(ctx, rw, toResponse(subs), http.StatusOK)
+}
+
+// DeleteSubscription removes the subscription of the ID path parameter.
+func (h *Handler) DeleteSubscription(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	if err := h.SubscriptionService.Delete(ctx, web.FromPath(req, "id")); err != nil {
+		return resourceError(err)
+	}
+
+	return web.Respond(ctx, rw, NewResponse(StatusUnsubscribed), http.StatusOK)
+}
+
+// resourceError maps the error of the subscription resource to the request error of its status code.
+func resourceError(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return web.NewRequestError(err, http.StatusNotFound)
+	case errors.Is(err, ErrSubscriptionExists):
+		return web.NewRequestError(err, http.StatusConflict)
+	case errors.Is(err, ErrInvalidTopic):
+		return web.NewRequestError(err, http.StatusBadRequest)
+	case errors.Is(err, context.DeadlineExceeded):
+		return web.NewRequestError(err, http.StatusRequestTimeout)
+	}
+
+	return err
 }
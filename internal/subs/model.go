@@ -1,21 +1,108 @@
 package subs
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
 )
 
 type Subscriptions []Subscription
 
 // Subscription represents aggregate subscription.
 type Subscription struct {
+	// ID is assigned once the subscription is requested and stays the same when its topic is changed.
+	ID         string
 	Subscriber Subscriber
 	Topic      Topic
+	// Alerts lists the rules the subscriber is notified on besides the broadcasts, empty for none.
+	Alerts []Alert
+	// Pending is set until the subscriber confirms the subscription by the link sent to them.
+	Pending bool
+	// CreatedAt is the time the subscription was requested, zero for the ones stored before it was tracked.
+	CreatedAt time.Time
+	// Delivered is the time the last digest was delivered at, zero until the first one.
+	Delivered time.Time
+	// Queued is set when a broadcast was held back by the quiet hours, it is delivered once they end.
+	Queued bool
+}
+
+// NewID returns a new random subscription ID.
+func NewID() (string, error) {
+	const idSize = 16
+
+	id := make([]byte, idSize)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("generating id: %w", err)
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+// Key implements filestore.Keyer, subscriptions are stored by their ID.
+// The subscriptions stored before they had IDs are identified by the ID derived from their subscriber and topic.
+func (s Subscription) Key() string {
+	if s.ID != "" {
+		return s.ID
+	}
+
+	const idSize = 16
+
+	sum := sha256.Sum256([]byte(s.address() + "|" + s.Topic.Base + "/" + s.Topic.Quote))
+
+	return hex.EncodeToString(sum[:idSize])
+}
+
+// Matches reports whether the subscriptions are of the same subscriber and topic, a subscriber has a single one per topic.
+func (s Subscription) Matches(other Subscription) bool {
+	return s.address() == other.address() && s.Topic == other.Topic
+}
+
+func (s Subscription) address() string {
+	if s.Subscriber.Address == nil {
+		return ""
+	}
+
+	return strings.ToLower(s.Subscriber.Address.Address)
+}
+
+// Filter represents the criteria the subscriptions are queried by, the zero fields match any.
+type Filter struct {
+	Email string
+	// Topic matches by either currency when the other one is empty.
+	Topic  Topic
+	Offset int
+	// Limit is the most subscriptions returned, zero for all.
+	Limit int
+}
+
+func (f Filter) match(s Subscription) bool {
+	return (f.Email == "" || strings.EqualFold(f.Email, s.address())) &&
+		(f.Topic.Base == "" || f.Topic.Base == s.Topic.Base) &&
+		(f.Topic.Quote == "" || f.Topic.Quote == s.Topic.Quote)
+}
+
+// Changes represents the changes of a subscription, the zero fields are left as they are.
+type Changes struct {
+	Base  string
+	Quote string
+	// Alerts replaces the alert rules when set, an empty list drops them.
+	Alerts *[]Alert
+	// Preferences replaces the delivery preferences when set.
+	Preferences *Preferences
 }
 
 // Subscriber represents an entity that subscribes to emails.
 type Subscriber struct {
-	//TODO: extend entity.
 	Address *mail.Address
+	// Preferences define how and when the subscriber receives the notifications of the subscription.
+	Preferences Preferences
 }
 
 // Topic represents a value object of a topic for subscription.
@@ -27,6 +114,19 @@ type CurrencyPair struct {
 	Quote string
 }
 
+// Topics is a list of topics.
+type Topics []Topic
+
+// CurrencyPairs implements CurrencyPairsEvent returning the topics like "BTC/UAH".
+func (ts Topics) CurrencyPairs() []string {
+	list := make([]string, len(ts))
+	for i := range ts {
+		list[i] = ts[i].Base + "/" + ts[i].Quote
+	}
+
+	return list
+}
+
 // Subscribers implements SubscribersEvent.
 func (subss Subscriptions) Subscribers() []string {
 	list := make([]string, len(subss))
@@ -43,7 +143,16 @@ func NewSubscriber(address *mail.Address) Subscriber {
 
 func NewTopic(base, quote string) Topic {
 	return Topic{
-		Base:  base,
-		Quote: quote,
+		Base:  strings.ToUpper(base),
+		Quote: strings.ToUpper(quote),
 	}
 }
+
+// ValidateWith validates the currencies of CurrencyPair against the currency registry.
+func (cp CurrencyPair) ValidateWith(reg *currency.Registry) error {
+	if err := errors.Join(reg.Validate(cp.Base), reg.Validate(cp.Quote)); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTopic, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,343 @@
+package subs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+)
+
+// EventKindDigestDue is the event of the digest due to the subscribers of a topic.
+const EventKindDigestDue = "digest_due"
+
+const (
+	// FrequencyInstant delivers every broadcast as it is sent.
+	FrequencyInstant = "instant"
+	// FrequencyHourly delivers the digest at the top of every hour.
+	FrequencyHourly = "hourly"
+	// FrequencyDaily delivers the digest once a day at the time of Preferences.At.
+	FrequencyDaily = "daily"
+)
+
+// ChannelEmail is the only channel the notifications are delivered by so far.
+const ChannelEmail = "email"
+
+const minutesInHour = 60
+
+// clockLayout is the layout of the local times of the preferences like "09:00".
+const clockLayout = "15:04"
+
+// ErrInvalidPreferences is an error indicating that the delivery preferences are malformed.
+var ErrInvalidPreferences = errors.New("invalid preferences")
+
+// Preferences represents a value object of how and when the subscriber receives the notifications,
+// the zero value delivers every broadcast instantly by email at any time.
+type Preferences struct {
+	// Frequency is one of FrequencyInstant, FrequencyHourly or FrequencyDaily, empty for FrequencyInstant.
+	Frequency string
+	// At is the local time like "09:00" the daily digest is delivered at.
+	At string
+	// Timezone is the IANA name like "Europe/Kyiv" the local times are in, empty for UTC.
+	Timezone string
+	// QuietFrom and QuietTo are the local times like "22:00" and "07:00" nothing is delivered between, empty for none.
+	QuietFrom string
+	QuietTo   string
+	// Channel is the way the notifications are delivered, empty for ChannelEmail.
+	Channel string
+}
+
+// Validate checks the preferences are complete and refer to the known frequency, timezone and channel.
+func (p Preferences) Validate() error {
+	switch p.Frequency {
+	case "", FrequencyInstant, FrequencyHourly:
+	case FrequencyDaily:
+		if p.At == "" {
+			return fmt.Errorf("%w: daily frequency requires the time", ErrInvalidPreferences)
+		}
+	default:
+		return fmt.Errorf("%w: unknown frequency %q", ErrInvalidPreferences, p.Frequency)
+	}
+
+	if p.At != "" {
+		if _, err := time.Parse(clockLayout, p.At); err != nil {
+			return fmt.Errorf("%w: time %q must be like 09:00", ErrInvalidPreferences, p.At)
+		}
+	}
+
+	if _, err := time.LoadLocation(p.Timezone); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPreferences, err)
+	}
+
+	if (p.QuietFrom == "") != (p.QuietTo == "") {
+		return fmt.Errorf("%w: quiet hours require both ends", ErrInvalidPreferences)
+	}
+
+	for _, val := range []string{p.QuietFrom, p.QuietTo} {
+		if _, err := time.Parse(clockLayout, val); val != "" && err != nil {
+			return fmt.Errorf("%w: quiet hours %q must be like 22:00", ErrInvalidPreferences, val)
+		}
+	}
+
+	if p.Channel != "" && p.Channel != ChannelEmail {
+		return fmt.Errorf("%w: unsupported channel %q", ErrInvalidPreferences, p.Channel)
+	}
+
+	return nil
+}
+
+// Instant reports whether every broadcast is delivered as it is sent rather than in a digest.
+func (p Preferences) Instant() bool {
+	return p.Frequency == "" || p.Frequency == FrequencyInstant
+}
+
+// Quiet reports whether the time is within the quiet hours, which wrap past midnight when QuietFrom is later than QuietTo.
+func (p Preferences) Quiet(at time.Time) bool {
+	if p.QuietFrom == "" || p.QuietTo == "" {
+		return false
+	}
+
+	from, to := clock(p.QuietFrom), clock(p.QuietTo)
+	now := at.In(p.location())
+	mins := now.Hour()*minutesInHour + now.Minute()
+
+	if from <= to {
+		return from <= mins && mins < to
+	}
+
+	return mins >= from || mins < to
+}
+
+// Next returns the first time the digest is due after the time, zero for the instant delivery.
+func (p Preferences) Next(after time.Time) time.Time {
+	t := after.In(p.location())
+
+	switch p.Frequency {
+	case FrequencyHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(time.Hour)
+
+	case FrequencyDaily:
+		mins := clock(p.At)
+
+		next := time.Date(t.Year(), t.Month(), t.Day(), mins/minutesInHour, mins%minutesInHour, 0, 0, t.Location())
+		if !next.After(t) {
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, mins/minutesInHour, mins%minutesInHour, 0, 0, t.Location())
+		}
+
+		return next
+	}
+
+	return time.Time{}
+}
+
+func (p Preferences) location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// clock returns the minutes since midnight of the local time like "09:00", zero if it is malformed.
+func clock(val string) int {
+	t, err := time.Parse(clockLayout, val)
+	if err != nil {
+		return 0
+	}
+
+	return t.Hour()*minutesInHour + t.Minute()
+}
+
+// Deliverable returns the subscriptions the broadcast sent at the time is delivered to instantly,
+// the ones preferring the digest or within the quiet hours are left out.
+func Deliverable(at time.Time, subss ...Subscription) Subscriptions {
+	list := make(Subscriptions, 0, len(subss))
+
+	for _, subs := range subss {
+		if prefs := subs.Subscriber.Preferences; prefs.Instant() && !prefs.Quiet(at) {
+			list = append(list, subs)
+		}
+	}
+
+	return list
+}
+
+// queue marks the instant subscriptions within the quiet hours at the time as having the broadcast held back,
+// so the Digester delivers it once the quiet hours end.
+func (svc *Service) queue(ctx context.Context, at time.Time, subss ...Subscription) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	var errs []error
+
+	for _, subs := range subss {
+		if prefs := subs.Subscriber.Preferences; subs.Queued || !prefs.Instant() || !prefs.Quiet(at) {
+			continue
+		}
+
+		subs.Queued = true
+
+		if err := storeDelivery(ctx, svc.repo, subs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// storeDelivery stores the delivery state of the subscription on top of its latest stored version,
+// so the changes made since it was listed are kept. The ones turned pending or moved to another topic are left as they are.
+func storeDelivery(ctx context.Context, repo SubscriberRepository, subs Subscription) error {
+	stored, err := repo.Get(ctx, subs.Key())
+	if err != nil {
+		return err
+	}
+
+	if stored.Pending || stored.Topic != subs.Topic {
+		return nil
+	}
+
+	stored.Delivered, stored.Queued = subs.Delivered, subs.Queued
+
+	return repo.Update(ctx, stored)
+}
+
+// DigestConfig defines how often the due digests are looked for.
+type DigestConfig struct {
+	// Interval is the period the digests are checked at, a minute keeps the daily ones on time.
+	Interval time.Duration
+}
+
+// Digest represents the data of a due digest event.
+type Digest struct {
+	Topic         Topic
+	Subscriptions Subscriptions
+}
+
+// Subscribers implements SubscribersEvent.
+func (d Digest) Subscribers() []string {
+	return d.Subscriptions.Subscribers()
+}
+
+// BaseCurrency implements CurrencyPairEvent.
+func (d Digest) BaseCurrency() string {
+	return d.Topic.Base
+}
+
+// QuoteCurrency implements CurrencyPairEvent.
+func (d Digest) QuoteCurrency() string {
+	return d.Topic.Quote
+}
+
+// Digester publishes the digest event for the subscribers preferring the hourly or daily delivery once it is due,
+// and for the ones whose broadcast was held back by the quiet hours.
+// A digest due within the quiet hours of the subscriber is delivered once they end.
+type Digester struct {
+	bus  *event.Bus
+	repo SubscriberRepository
+	cfg  DigestConfig
+}
+
+// NewDigester creates a new Digester instance.
+func NewDigester(bus *event.Bus, repo SubscriberRepository, cfg DigestConfig) *Digester {
+	return &Digester{
+		bus:  bus,
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// Run publishes the due digests at the interval until the context is cancelled.
+func (d *Digester) Run(ctx context.Context) {
+	if d.cfg.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Failures are retried on the next tick, so they are not reported anywhere else.
+		_ = d.Send(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Send publishes the digest event for every topic having the subscribers the digest is due to at the time
+// and stores the time it was delivered at with their subscriptions.
+func (d *Digester) Send(ctx context.Context, at time.Time) error {
+	subss, err := d.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing subscriptions: %w", err)
+	}
+
+	var errs []error
+
+	for _, digest := range d.Due(at, subss...) {
+		if err := d.bus.Publish(ctx, event.New(EventSource, EventKindDigestDue, digest)); err != nil {
+			return fmt.Errorf("publishing digest event: %w", err)
+		}
+
+		for _, subs := range digest.Subscriptions {
+			if err := storeDelivery(ctx, d.repo, subs); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("storing digest delivery: %w", err)
+	}
+
+	return nil
+}
+
+// Due returns the digests due at the time grouped by topic, the subscriptions in them carry the delivery state to be stored.
+// A digest is due on the first slot after the last one delivered or, until then, after the subscription was requested.
+func (d *Digester) Due(at time.Time, subss ...Subscription) []Digest {
+	var (
+		list  []Digest
+		index = make(map[Topic]int)
+	)
+
+	for _, subs := range subss {
+		if subs.Subscriber.Preferences.Quiet(at) || (!subs.Queued && !digestDue(at, subs)) {
+			continue
+		}
+
+		subs.Delivered, subs.Queued = at, false
+
+		i, ok := index[subs.Topic]
+		if !ok {
+			i = len(list)
+			index[subs.Topic] = i
+			list = append(list, Digest{Topic: subs.Topic})
+		}
+
+		list[i].Subscriptions = append(list[i].Subscriptions, subs)
+	}
+
+	return list
+}
+
+// digestDue reports whether the digest of the subscription preferring it is due at the time.
+func digestDue(at time.Time, subs Subscription) bool {
+	prefs := subs.Subscriber.Preferences
+	if prefs.Instant() {
+		return false
+	}
+
+	last := subs.Delivered
+	if last.IsZero() {
+		last = subs.CreatedAt
+	}
+
+	return !prefs.Next(last).After(at)
+}
@@ -0,0 +1,286 @@
+package subs_test
+
+import (
+	"context"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/subs"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferencesValidate(t *testing.T) {
+	tests := map[string]struct {
+		prefs   subs.Preferences
+		wantErr error
+	}{
+		"zero":          {prefs: subs.Preferences{}},
+		"valid_daily":   {prefs: subs.Preferences{Frequency: subs.FrequencyDaily, At: "09:00", Timezone: "Europe/Kyiv"}},
+		"valid_quiet":   {prefs: subs.Preferences{Frequency: subs.FrequencyHourly, QuietFrom: "22:00", QuietTo: "07:00"}},
+		"unknown_freq":  {prefs: subs.Preferences{Frequency: "weekly"}, wantErr: subs.ErrInvalidPreferences},
+		"daily_no_time": {prefs: subs.Preferences{Frequency: subs.FrequencyDaily}, wantErr: subs.ErrInvalidPreferences},
+		"invalid_time":  {prefs: subs.Preferences{Frequency: subs.FrequencyDaily, At: "9am"}, wantErr: subs.ErrInvalidPreferences},
+		"unknown_zone":  {prefs: subs.Preferences{Timezone: "Mars/Olympus"}, wantErr: subs.ErrInvalidPreferences},
+		"half_quiet":    {prefs: subs.Preferences{QuietFrom: "22:00"}, wantErr: subs.ErrInvalidPreferences},
+		"unknown_chan":  {prefs: subs.Preferences{Channel: "pigeon"}, wantErr: subs.ErrInvalidPreferences},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, tc.prefs.Validate(), tc.wantErr)
+		})
+	}
+}
+
+func TestPreferencesSchedule(t *testing.T) {
+	kyiv := subs.Preferences{Timezone: "Europe/Kyiv", QuietFrom: "22:00", QuietTo: "07:00"}
+	at := time.Date(2023, 7, 1, 20, 30, 0, 0, time.UTC) // 23:30 in Kyiv.
+
+	tests := map[string]struct {
+		prefs     subs.Preferences
+		at        time.Time
+		wantQuiet bool
+		wantNext  time.Time
+	}{
+		"instant": {
+			prefs: subs.Preferences{},
+			at:    at,
+		},
+		"hourly_quiet_past_midnight": {
+			prefs:     subs.Preferences{Frequency: subs.FrequencyHourly, Timezone: kyiv.Timezone, QuietFrom: "22:00", QuietTo: "07:00"},
+			at:        at,
+			wantQuiet: true,
+			wantNext:  time.Date(2023, 7, 1, 21, 0, 0, 0, time.UTC),
+		},
+		"daily_tomorrow": {
+			prefs:    subs.Preferences{Frequency: subs.FrequencyDaily, At: "09:00", Timezone: kyiv.Timezone},
+			at:       at,
+			wantNext: time.Date(2023, 7, 2, 6, 0, 0, 0, time.UTC),
+		},
+		"daily_today": {
+			prefs:    subs.Preferences{Frequency: subs.FrequencyDaily, At: "09:00"},
+			at:       at.Add(-14 * time.Hour),
+			wantNext: time.Date(2023, 7, 1, 9, 0, 0, 0, time.UTC),
+		},
+		"quiet_ended": {
+			prefs: kyiv,
+			at:    at.Add(8 * time.Hour),
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.wantQuiet, tc.prefs.Quiet(tc.at))
+			require.True(t, tc.wantNext.Equal(tc.prefs.Next(tc.at)), tc.prefs.Next(tc.at))
+		})
+	}
+}
+
+func TestDigesterDue(t *testing.T) {
+	start := time.Date(2023, 7, 1, 9, 30, 0, 0, time.UTC)
+
+	subscription := func(addr string, prefs subs.Preferences, created time.Time, queued bool) subs.Subscription {
+		return subs.Subscription{
+			Subscriber: subs.Subscriber{Address: &mail.Address{Address: addr}, Preferences: prefs},
+			Topic:      subs.NewTopic("BTC", "UAH"),
+			CreatedAt:  created,
+			Queued:     queued,
+		}
+	}
+
+	subss := []subs.Subscription{
+		subscription("instant@example.com", subs.Preferences{}, start, false),
+		subscription("hourly@example.com", subs.Preferences{Frequency: subs.FrequencyHourly}, start, false),
+		subscription("daily@example.com", subs.Preferences{Frequency: subs.FrequencyDaily, At: "11:00"}, start, false),
+		subscription("quiet@example.com", subs.Preferences{Frequency: subs.FrequencyHourly, QuietFrom: "10:00", QuietTo: "10:30"}, start, false),
+		subscription("queued@example.com", subs.Preferences{QuietFrom: "09:00", QuietTo: "10:00"}, start, true),
+		subscription("missed@example.com", subs.Preferences{Frequency: subs.FrequencyDaily, At: "09:00"}, start.Add(-24*time.Hour), false),
+	}
+
+	tests := []struct {
+		after time.Duration
+		want  []string
+	}{
+		{after: 0, want: []string{"missed@example.com"}},
+		{after: 20 * time.Minute, want: nil},
+		{after: 30 * time.Minute, want: []string{"hourly@example.com", "queued@example.com"}},
+		{after: time.Hour, want: []string{"quiet@example.com"}},
+		{after: 90 * time.Minute, want: []string{"hourly@example.com", "daily@example.com", "quiet@example.com"}},
+		{after: 100 * time.Minute, want: nil},
+	}
+
+	bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+	d := subs.NewDigester(bus, &mock.SubscriberRepositoryMock{}, subs.DigestConfig{})
+
+	for _, tc := range tests {
+		var got []string
+
+		at := start.Add(tc.after)
+
+		for _, digest := range d.Due(at, subss...) {
+			require.Equal(t, subs.NewTopic("BTC", "UAH"), digest.Topic)
+
+			for _, sub := range digest.Subscriptions {
+				require.True(t, sub.Delivered.Equal(at))
+				require.False(t, sub.Queued)
+
+				got = append(got, sub.Subscriber.Address.Address)
+
+				// The delivery state is what the Digester stores, so the next round sees it.
+				for i := range subss {
+					if subss[i].Key() == sub.Key() {
+						subss[i] = sub
+					}
+				}
+			}
+		}
+
+		require.Equal(t, tc.want, got, tc.after)
+	}
+}
+
+func TestDigesterSend(t *testing.T) {
+	at := time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	hourly := subs.Preferences{Frequency: subs.FrequencyHourly}
+
+	listed := subs.Subscription{
+		ID:         "hourly",
+		Subscriber: subs.Subscriber{Address: &mail.Address{Address: "hourly@example.com"}, Preferences: hourly},
+		Topic:      subs.NewTopic("BTC", "UAH"),
+		CreatedAt:  at.Add(-time.Hour),
+	}
+
+	tests := map[string]struct {
+		stored     subs.Subscription
+		wantUpdate bool
+	}{
+		"delivery_stored": {
+			stored:     listed,
+			wantUpdate: true,
+		},
+		"changed_meanwhile_kept": {
+			stored: func() subs.Subscription {
+				s := listed
+				s.Topic, s.Pending = subs.NewTopic("ETH", "UAH"), true
+
+				return s
+			}(),
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			repo := &mock.SubscriberRepositoryMock{
+				ListFunc:   func(ctx context.Context) ([]subs.Subscription, error) { return []subs.Subscription{listed}, nil },
+				GetFunc:    func(ctx context.Context, id string) (subs.Subscription, error) { return tc.stored, nil },
+				UpdateFunc: func(ctx context.Context, sub subs.Subscription) error { return nil },
+			}
+
+			sent := make(chan event.Event, 1)
+
+			bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+			bus.Subscribe(event.New(subs.EventSource, subs.EventKindDigestDue, nil), func(_ context.Context, e event.Event) error {
+				sent <- e
+				return nil
+			})
+
+			require.NoError(t, subs.NewDigester(bus, repo, subs.DigestConfig{}).Send(context.Background(), at))
+			require.Eventually(t, func() bool { return len(sent) == 1 }, time.Second, time.Millisecond)
+			require.Equal(t, tc.wantUpdate, len(repo.UpdateCalls()) == 1)
+
+			if tc.wantUpdate {
+				require.True(t, repo.UpdateCalls()[0].Subscription.Delivered.Equal(at))
+			}
+		})
+	}
+}
+
+type broadcastEvent struct {
+	*mock.CurrencyPairEventMock
+}
+
+func (broadcastEvent) Broadcast() bool { return true }
+
+func TestServiceRespondSubscriptionQueued(t *testing.T) {
+	now := time.Now().UTC()
+	quiet := subs.Preferences{QuietFrom: now.Add(-time.Hour).Format("15:04"), QuietTo: now.Add(time.Hour).Format("15:04")}
+
+	subscription := func(addr string, prefs subs.Preferences) subs.Subscription {
+		return subs.Subscription{
+			ID:         addr,
+			Subscriber: subs.Subscriber{Address: &mail.Address{Address: addr}, Preferences: prefs},
+			Topic:      subs.NewTopic("BTC", "UAH"),
+		}
+	}
+
+	stored := []subs.Subscription{
+		subscription("instant@example.com", subs.Preferences{}),
+		subscription("quiet@example.com", quiet),
+	}
+
+	pair := &mock.CurrencyPairEventMock{
+		BaseCurrencyFunc:  func() string { return "BTC" },
+		QuoteCurrencyFunc: func() string { return "UAH" },
+	}
+
+	tests := map[string]struct {
+		payload    subs.CurrencyPairEvent
+		wantQueued []string
+	}{
+		"broadcast_queued": {
+			payload:    broadcastEvent{pair},
+			wantQueued: []string{"quiet@example.com"},
+		},
+		"other_request_not_queued": {
+			payload: pair,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			repo := &mock.SubscriberRepositoryMock{
+				ListFunc: func(ctx context.Context) ([]subs.Subscription, error) { return stored, nil },
+				GetFunc: func(ctx context.Context, id string) (subs.Subscription, error) {
+					for _, s := range stored {
+						if s.Key() == id {
+							return s, nil
+						}
+					}
+
+					return subs.Subscription{}, subs.ErrNotFound
+				},
+				UpdateFunc: func(ctx context.Context, sub subs.Subscription) error { return nil },
+			}
+
+			bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+			svc := subs.NewService(bus, repo, testRegistry(), subs.ConfirmConfig{})
+
+			e := event.New(subs.EventSource, subs.EventKindRequested, tc.payload)
+			require.NoError(t, svc.RespondSubscription(context.Background(), e))
+
+			resp := <-e.Response
+			require.Equal(t, subs.Subscriptions{stored[0]}, resp.Payload)
+
+			calls := repo.UpdateCalls()
+			require.Len(t, calls, len(tc.wantQueued))
+
+			for i, call := range calls {
+				require.Equal(t, tc.wantQueued[i], call.Subscription.ID)
+				require.True(t, call.Subscription.Queued)
+			}
+		})
+	}
+}
@@ -10,7 +10,10 @@ import (
 // Storer defines the interface for storing and retrieving subscribers.
 type Storer interface {
 	Store(Subscription) error
+	Fetch(Subscription) (Subscription, error)
 	FetchAll() ([]Subscription, error)
+	Update(Subscription) error
+	Delete(Subscription) error
 }
 
 // Repo is a repository that implements the Storer interface.
@@ -34,12 +37,75 @@ func (r *Repo) Add(ctx context.Context, subs Subscription) error {
 	return nil
 }
 
-// List retrieves all email subscriptions from the repository.
+// Get retrieves the email subscription by its ID.
+func (r *Repo) Get(ctx context.Context, id string) (Subscription, error) {
+	subs, err := r.Storer.Fetch(Subscription{ID: id})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Subscription{}, ErrNotFound
+		}
+
+		return Subscription{}, fmt.Errorf("getting subscription: %w", err)
+	}
+
+	return subs, nil
+}
+
+// List retrieves all confirmed email subscriptions from the repository.
 func (r *Repo) List(ctx context.Context) ([]Subscription, error) {
+	return r.list(func(subs Subscription) bool { return !subs.Pending })
+}
+
+// ListPending retrieves all email subscriptions awaiting the confirmation from the repository.
+func (r *Repo) ListPending(ctx context.Context) ([]Subscription, error) {
+	return r.list(func(subs Subscription) bool { return subs.Pending })
+}
+
+// ListAll retrieves all email subscriptions from the repository, both confirmed and pending.
+func (r *Repo) ListAll(ctx context.Context) ([]Subscription, error) {
+	return r.list(func(Subscription) bool { return true })
+}
+
+func (r *Repo) list(keep func(Subscription) bool) ([]Subscription, error) {
 	subss, err := r.Storer.FetchAll()
 	if err != nil {
 		return nil, fmt.Errorf("getting all subscriptions: %w", err)
 	}
 
-	return subss, nil
+	var n int
+
+	for _, subs := range subss {
+		if keep(subs) {
+			subss[n] = subs
+			n++
+		}
+	}
+
+	return subss[:n], nil
+}
+
+// Update replaces the email subscription with the one of the same ID.
+func (r *Repo) Update(ctx context.Context, subs Subscription) error {
+	if err := r.Storer.Update(subs); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("updating subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Remove deletes the email subscription.
+func (r *Repo) Remove(ctx context.Context, subs Subscription) error {
+	if err := r.Storer.Delete(subs); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+
+		return fmt.Errorf("removing subscription: %w", err)
+	}
+
+	return nil
 }
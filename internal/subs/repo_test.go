@@ -26,9 +26,9 @@ func TestRepoIntegration(t *testing.T) {
 		defer teardown()
 
 		emails := []Subscription{
-			{Subscriber{Address: &mail.Address{Name: "Sam Johns", Address: "samjohns@example.com"}}, Topic{"BTC", "UAH"}},
-			{Subscriber{Address: &mail.Address{Name: "Jon Doe", Address: "johndoe@example.com"}}, Topic{"BTC", "UAH"}},
-			{Subscriber{Address: &mail.Address{Name: "Jane Smith", Address: "anesmith@example.com"}}, Topic{"BTC", "UAH"}},
+			{Subscriber: Subscriber{Address: &mail.Address{Name: "Sam Johns", Address: "samjohns@example.com"}}, Topic: Topic{"BTC", "UAH"}},
+			{Subscriber: Subscriber{Address: &mail.Address{Name: "Jon Doe", Address: "johndoe@example.com"}}, Topic: Topic{"BTC", "UAH"}},
+			{Subscriber: Subscriber{Address: &mail.Address{Name: "Jane Smith", Address: "anesmith@example.com"}}, Topic: Topic{"BTC", "UAH"}},
 		}
 
 		testAdd(t, repo, nil, emails...)
@@ -40,9 +40,9 @@ func TestRepoIntegration(t *testing.T) {
 		defer teardown()
 
 		emails := []Subscription{
-			{Subscriber{Address: &mail.Address{Name: "Sam Johns", Address: "samjohns@example.com"}}, Topic{"BTC", "UAH"}},
-			{Subscriber{Address: &mail.Address{Name: "Jon Doe", Address: "johndoe@example.com"}}, Topic{"BTC", "UAH"}},
-			{Subscriber{Address: &mail.Address{Name: "Jane Smith", Address: "anesmith@example.com"}}, Topic{"BTC", "UAH"}},
+			{Subscriber: Subscriber{Address: &mail.Address{Name: "Sam Johns", Address: "samjohns@example.com"}}, Topic: Topic{"BTC", "UAH"}},
+			{Subscriber: Subscriber{Address: &mail.Address{Name: "Jon Doe", Address: "johndoe@example.com"}}, Topic: Topic{"BTC", "UAH"}},
+			{Subscriber: Subscriber{Address: &mail.Address{Name: "Jane Smith", Address: "anesmith@example.com"}}, Topic: Topic{"BTC", "UAH"}},
 		}
 
 		testAdd(t, repo, nil, emails...)
@@ -58,7 +58,10 @@ func TestRepoIntegration(t *testing.T) {
 		repo, teardown := testSetupRepo(t)
 		defer teardown()
 
-		subs := Subscription{Subscriber{Address: &mail.Address{Name: "Sam Johns", Address: "samjohns@example.com"}}, Topic{"BTC", "UAH"}}
+		subs := Subscription{
+			Subscriber: Subscriber{Address: &mail.Address{Name: "Sam Johns", Address: "samjohns@example.com"}},
+			Topic:      Topic{"BTC", "UAH"},
+		}
 
 		testAdd(t, repo, nil, subs)
 		testGetAll(t, repo, nil, subs)
@@ -76,8 +79,8 @@ func TestRepoIntegration(t *testing.T) {
 		subss := make([]Subscription, wholeLot)
 		for i := range subss {
 			subss[i] = Subscription{
-				Subscriber{Address: &mail.Address{Name: fmt.Sprintf("User%d", i), Address: fmt.Sprintf("user%d@example.com", i)}},
-				Topic{"BTC", "UAH"}}
+				Subscriber: Subscriber{Address: &mail.Address{Name: fmt.Sprintf("User%d", i), Address: fmt.Sprintf("user%d@example.com", i)}},
+				Topic:      Topic{"BTC", "UAH"}}
 		}
 
 		testAdd(t, repo, nil, subss...)
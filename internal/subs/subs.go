@@ -7,8 +7,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
 	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
 )
 
@@ -21,8 +25,14 @@ var (
 	// ErrMissingEmail is an error indicating that the email address is missing.
 	ErrMissingEmail = errors.New("missing email")
 
+	// ErrInvalidTopic is an error indicating that the topic currencies are not supported.
+	ErrInvalidTopic = errors.New("invalid topic")
+
 	// ErrNotFound is an error indicating that the subscription was not found in the database.
 	ErrNotFound = errors.New("subscription not found")
+
+	// ErrInvalidPage is an error indicating that the pagination of the subscriptions is out of range.
+	ErrInvalidPage = errors.New("invalid page")
 )
 
 // SubscriberRepository is an interface for managing email subscriptions.
@@ -30,36 +40,291 @@ var (
 //go:generate moq -out=../../test/mock/email_repository.go -pkg=mock . SubscriberRepository
 type SubscriberRepository interface {
 	Add(context.Context, Subscription) error
+	Get(ctx context.Context, id string) (Subscription, error)
 	List(context.Context) ([]Subscription, error)
+	ListPending(context.Context) ([]Subscription, error)
+	ListAll(context.Context) ([]Subscription, error)
+	Update(context.Context, Subscription) error
+	Remove(context.Context, Subscription) error
 }
 
 // Service represents a service that manages email subscriptions and sends emails.
 type Service struct {
 	bus  *event.Bus
 	repo SubscriberRepository
+	reg  *currency.Registry
+	cfg  ConfirmConfig
+	// mu serializes the changes, so a subscriber does not end up subscribed to the same topic twice.
+	mu sync.Mutex
 }
 
 // NewService creates a new Service instance with the provided dependencies.
-func NewService(bus *event.Bus, repo SubscriberRepository) *Service {
-	svc := Service{
+func NewService(bus *event.Bus, repo SubscriberRepository, reg *currency.Registry, cfg ConfirmConfig) *Service {
+	svc := &Service{
 		bus:  bus,
 		repo: repo,
+		reg:  reg,
+		cfg:  cfg,
 	}
 
 	svc.bus.Subscribe(event.New(EventSource, EventKindRequested, nil), svc.RespondSubscription)
+	svc.bus.Subscribe(event.New(EventSource, EventKindTopicsRequested, nil), svc.RespondTopics)
 
-	return &svc
+	return svc
 }
 
-// Subscribe adds a new email subscription to the repository.
+// Subscribe adds a new email subscription pending the confirmation to the repository
+// and requests the confirmation message to be sent to the subscriber.
+// Subscribing again while the subscription is pending renews it and sends the confirmation message once more,
+// unless the previous one was sent within the resend interval.
 func (svc *Service) Subscribe(ctx context.Context, subs Subscription) error {
-	if err := svc.repo.Add(ctx, subs); err != nil {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	subs.Pending, subs.CreatedAt = true, time.Now()
+
+	found, err := svc.find(ctx, subs)
+
+	switch {
+	case err == nil && !found.Pending:
+		return ErrSubscriptionExists
+
+	case err == nil && subs.CreatedAt.Sub(found.CreatedAt) < svc.cfg.Resend:
+		return nil
+
+	case err == nil:
+		subs.ID = found.ID
+		err = svc.repo.Update(ctx, subs)
+
+	case errors.Is(err, ErrNotFound):
+		if subs.ID, err = NewID(); err == nil {
+			err = svc.repo.Add(ctx, subs)
+		}
+	}
+
+	if err != nil {
 		return fmt.Errorf("adding subscription: %w", err)
 	}
-	// TODO: Add event for handling new subscription.
+
+	if err := svc.bus.Publish(ctx, event.New(EventSource, EventKindConfirmationRequested, Confirmation{Subscription: subs})); err != nil {
+		return fmt.Errorf("publishing confirmation request: %w", err)
+	}
+
+	return nil
+}
+
+// Confirm activates the pending email subscription, confirming the active one again has no effect.
+func (svc *Service) Confirm(ctx context.Context, subs Subscription) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	found, err := svc.find(ctx, subs)
+	if err != nil {
+		return err
+	}
+
+	if !found.Pending {
+		return nil
+	}
+
+	found.Pending = false
+
+	if err := svc.repo.Update(ctx, found); err != nil {
+		return fmt.Errorf("confirming subscription: %w", err)
+	}
+
 	return nil
 }
 
+// Unsubscribe removes the email subscription of the subscriber to the topic from the repository.
+func (svc *Service) Unsubscribe(ctx context.Context, subs Subscription) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	found, err := svc.find(ctx, subs)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.repo.Remove(ctx, found); err != nil {
+		return fmt.Errorf("removing subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the email subscription by its ID.
+func (svc *Service) Get(ctx context.Context, id string) (Subscription, error) {
+	subs, err := svc.repo.Get(ctx, id)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("getting subscription: %w", err)
+	}
+
+	subs.ID = subs.Key()
+
+	return subs, nil
+}
+
+// Query returns the page of the email subscriptions matching the filter, both confirmed and pending,
+// ordered by the time they were requested, along with the total number of the matching ones.
+func (svc *Service) Query(ctx context.Context, f Filter) ([]Subscription, int, error) {
+	subss, err := svc.repo.ListAll(ctx)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, 0, fmt.Errorf("listing subscriptions: %w", err)
+	}
+
+	var n int
+
+	for _, subs := range subss {
+		if f.match(subs) {
+			subs.ID = subs.Key()
+			subss[n] = subs
+			n++
+		}
+	}
+
+	subss = subss[:n]
+
+	sort.Slice(subss, func(i, j int) bool {
+		if !subss[i].CreatedAt.Equal(subss[j].CreatedAt) {
+			return subss[i].CreatedAt.Before(subss[j].CreatedAt)
+		}
+
+		return subss[i].ID < subss[j].ID
+	})
+
+	if f.Offset >= n {
+		return []Subscription{}, n, nil
+	}
+
+	end := n
+	if f.Limit > 0 && f.Offset+f.Limit < n {
+		end = f.Offset + f.Limit
+	}
+
+	return subss[f.Offset:end], n, nil
+}
+
+// Change applies the changes to the email subscription of the ID and returns the changed one.
+// Changing the topic makes the subscription pending until the subscriber confirms the new one,
+// ErrSubscriptionExists is returned if the subscriber is subscribed to the new topic already.
+func (svc *Service) Change(ctx context.Context, id string, chg Changes) (Subscription, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	subs, err := svc.Get(ctx, id)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	topic := subs.Topic
+	if chg.Base != "" || chg.Quote != "" {
+		topic = NewTopic(defaultString(chg.Base, topic.Base), defaultString(chg.Quote, topic.Quote))
+	}
+
+	retopic := topic != subs.Topic
+
+	if retopic {
+		if err := topic.ValidateWith(svc.reg); err != nil {
+			return Subscription{}, err
+		}
+
+		_, err := svc.find(ctx, Subscription{Subscriber: subs.Subscriber, Topic: topic})
+
+		switch {
+		case err == nil:
+			return Subscription{}, ErrSubscriptionExists
+		case !errors.Is(err, ErrNotFound):
+			return Subscription{}, err
+		}
+
+		subs.Topic = topic
+		subs.Pending, subs.CreatedAt = true, time.Now()
+	}
+
+	if chg.Alerts != nil {
+		subs.Alerts = *chg.Alerts
+	}
+
+	if chg.Preferences != nil {
+		subs.Subscriber.Preferences = *chg.Preferences
+	}
+
+	if err := svc.repo.Update(ctx, subs); err != nil {
+		return Subscription{}, fmt.Errorf("changing subscription: %w", err)
+	}
+
+	if retopic {
+		if err := svc.bus.Publish(ctx, event.New(EventSource, EventKindConfirmationRequested, Confirmation{Subscription: subs})); err != nil {
+			return Subscription{}, fmt.Errorf("publishing confirmation request: %w", err)
+		}
+	}
+
+	return subs, nil
+}
+
+// Delete removes the email subscription of the ID from the repository.
+func (svc *Service) Delete(ctx context.Context, id string) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	subs, err := svc.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.repo.Remove(ctx, subs); err != nil {
+		return fmt.Errorf("removing subscription: %w", err)
+	}
+
+	return nil
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+
+	return s
+}
+
+// find returns the stored email subscription of the same subscriber and topic, pending or not.
+func (svc *Service) find(ctx context.Context, subs Subscription) (Subscription, error) {
+	subss, err := svc.repo.ListAll(ctx)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Subscription{}, fmt.Errorf("listing subscriptions: %w", err)
+	}
+
+	for _, s := range subss {
+		if s.Matches(subs) {
+			s.ID = s.Key()
+			return s, nil
+		}
+	}
+
+	return Subscription{}, ErrNotFound
+}
+
+// Topics returns the distinct topics having subscribers in the order they were first subscribed to.
+func (svc *Service) Topics(ctx context.Context) (Topics, error) {
+	subscriptions, err := svc.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing subscriptions: %w", err)
+	}
+
+	seen := make(map[Topic]struct{})
+	topics := make(Topics, 0)
+
+	for _, subs := range subscriptions {
+		if _, ok := seen[subs.Topic]; !ok {
+			seen[subs.Topic] = struct{}{}
+			topics = append(topics, subs.Topic)
+		}
+	}
+
+	return topics, nil
+}
+
 // List returns all subscriptions from the repository specified by topic.
 func (svc *Service) List(ctx context.Context, topic Topic) ([]Subscription, error) {
 	subscriptions, err := svc.repo.List(ctx)
@@ -0,0 +1,254 @@
+package subs_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/currency"
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/subs"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/event"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/logger"
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+	"github.com/GenesisEducationKyiv/main-project-delveper/test/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testRegistry() *currency.Registry {
+	return currency.NewRegistry(append(currency.ISO4217(), currency.Crypto()...)...)
+}
+
+func testStored() []subs.Subscription {
+	start := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	a := testSubscription("a@example.com", false, start.Add(2*time.Hour))
+	b := testSubscription("b@example.com", true, start.Add(time.Hour))
+	c := testSubscription("a@example.com", false, start)
+	c.Topic = subs.NewTopic("ETH", "USD")
+
+	return []subs.Subscription{a, b, c}
+}
+
+func TestServiceQuery(t *testing.T) {
+	tests := map[string]struct {
+		filter    subs.Filter
+		wantEmail []string
+		wantTotal int
+	}{
+		"all_ordered": {
+			wantEmail: []string{"a@example.com", "b@example.com", "a@example.com"},
+			wantTotal: 3,
+		},
+		"by_email": {
+			filter:    subs.Filter{Email: "A@example.com"},
+			wantEmail: []string{"a@example.com", "a@example.com"},
+			wantTotal: 2,
+		},
+		"by_base": {
+			filter:    subs.Filter{Topic: subs.NewTopic("btc", "")},
+			wantEmail: []string{"b@example.com", "a@example.com"},
+			wantTotal: 2,
+		},
+		"paginated": {
+			filter:    subs.Filter{Offset: 1, Limit: 1},
+			wantEmail: []string{"b@example.com"},
+			wantTotal: 3,
+		},
+		"past_end": {
+			filter:    subs.Filter{Offset: 5, Limit: 1},
+			wantEmail: []string{},
+			wantTotal: 3,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			repo := &mock.SubscriberRepositoryMock{
+				ListAllFunc: func(ctx context.Context) ([]subs.Subscription, error) { return testStored(), nil },
+			}
+
+			svc := subs.NewService(event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug))), repo, testRegistry(), subs.ConfirmConfig{})
+
+			got, total, err := svc.Query(context.Background(), tc.filter)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantTotal, total)
+
+			emails := make([]string, len(got))
+			for i := range got {
+				require.NotEmpty(t, got[i].ID)
+				emails[i] = got[i].Subscriber.Address.Address
+			}
+
+			require.Equal(t, tc.wantEmail, emails)
+		})
+	}
+}
+
+func TestServiceChange(t *testing.T) {
+	alerts := []subs.Alert{{Kind: subs.AlertAbove, Threshold: 1_500_000}}
+
+	tests := map[string]struct {
+		chg         subs.Changes
+		wantTopic   subs.Topic
+		wantPending bool
+		wantErr     error
+	}{
+		"topic_changed_pending_confirmation": {
+			chg:         subs.Changes{Quote: "usd"},
+			wantTopic:   subs.NewTopic("BTC", "USD"),
+			wantPending: true,
+		},
+		"alerts_replaced": {
+			chg:       subs.Changes{Alerts: &alerts},
+			wantTopic: subs.NewTopic("BTC", "UAH"),
+		},
+		"topic_taken": {
+			chg:     subs.Changes{Base: "ETH", Quote: "USD"},
+			wantErr: subs.ErrSubscriptionExists,
+		},
+		"topic_invalid": {
+			chg:     subs.Changes{Base: "XXX"},
+			wantErr: subs.ErrInvalidTopic,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			stored := testStored()
+			id := stored[0].Key()
+
+			repo := &mock.SubscriberRepositoryMock{
+				GetFunc: func(ctx context.Context, id string) (subs.Subscription, error) {
+					for _, s := range stored {
+						if s.Key() == id {
+							return s, nil
+						}
+					}
+
+					return subs.Subscription{}, subs.ErrNotFound
+				},
+				ListAllFunc: func(ctx context.Context) ([]subs.Subscription, error) { return stored, nil },
+				UpdateFunc:  func(ctx context.Context, sub subs.Subscription) error { return nil },
+			}
+
+			sent := make(chan event.Event, 1)
+
+			bus := event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug)))
+			bus.Subscribe(event.New(subs.EventSource, subs.EventKindConfirmationRequested, nil), func(_ context.Context, e event.Event) error {
+				sent <- e
+				return nil
+			})
+
+			svc := subs.NewService(bus, repo, testRegistry(), subs.ConfirmConfig{})
+
+			got, err := svc.Change(context.Background(), id, tc.chg)
+			require.ErrorIs(t, err, tc.wantErr)
+
+			if tc.wantErr != nil {
+				require.Empty(t, repo.UpdateCalls())
+				return
+			}
+
+			require.Equal(t, id, got.ID)
+			require.Equal(t, tc.wantTopic, got.Topic)
+			require.Equal(t, got, repo.UpdateCalls()[0].Subscription)
+			require.Equal(t, tc.wantPending, got.Pending)
+
+			if tc.wantPending {
+				require.Eventually(t, func() bool { return len(sent) == 1 }, time.Second, time.Millisecond)
+			} else {
+				require.Empty(t, sent)
+			}
+
+			if tc.chg.Alerts != nil {
+				require.Equal(t, alerts, got.Alerts)
+			}
+		})
+	}
+}
+
+func TestHandlerSubscriptions(t *testing.T) {
+	stored := testStored()
+
+	repo := &mock.SubscriberRepositoryMock{
+		GetFunc: func(ctx context.Context, id string) (subs.Subscription, error) {
+			for _, s := range stored {
+				if s.Key() == id {
+					return s, nil
+				}
+			}
+
+			return subs.Subscription{}, subs.ErrNotFound
+		},
+		ListAllFunc: func(ctx context.Context) ([]subs.Subscription, error) { return testStored(), nil },
+		RemoveFunc:  func(ctx context.Context, sub subs.Subscription) error { return nil },
+	}
+
+	reg := testRegistry()
+	svc := subs.NewService(event.NewBus(logger.New(logger.WithConsoleCore(logger.LevelDebug))), repo, reg, subs.ConfirmConfig{})
+	h := subs.NewHandler(svc, nil, reg)
+
+	w := web.New(make(chan os.Signal, 1), web.WithErrors(logger.New(logger.WithConsoleCore(logger.LevelDebug))))
+	w.Handle(http.MethodGet, "/", "/subscriptions", h.ListSubscriptions)
+	w.Handle(http.MethodGet, "/", "/subscriptions/:id", h.GetSubscription)
+	w.Handle(http.MethodDelete, "/", "/subscriptions/:id", h.DeleteSubscription)
+
+	tests := map[string]struct {
+		method   string
+		target   string
+		wantCode int
+		wantBody string
+	}{
+		"list_page": {
+			method:   http.MethodGet,
+			target:   "/subscriptions?email=a@example.com&limit=1",
+			wantCode: http.StatusOK,
+			wantBody: `"total":2,"offset":0,"limit":1`,
+		},
+		"list_invalid_limit": {
+			method:   http.MethodGet,
+			target:   "/subscriptions?limit=1000",
+			wantCode: http.StatusBadRequest,
+		},
+		"get": {
+			method:   http.MethodGet,
+			target:   "/subscriptions/" + stored[1].Key(),
+			wantCode: http.StatusOK,
+			wantBody: `"email":"b@example.com","base_currency":"BTC","quote_currency":"UAH","alerts":[],` +
+				`"preferences":{"frequency":"instant","channel":"email"},"status":"pending"`,
+		},
+		"get_missing": {
+			method:   http.MethodGet,
+			target:   "/subscriptions/missing",
+			wantCode: http.StatusNotFound,
+		},
+		"delete": {
+			method:   http.MethodDelete,
+			target:   "/subscriptions/" + stored[2].Key(),
+			wantCode: http.StatusOK,
+			wantBody: `"message":"unsubscribed"`,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			w.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.target, nil))
+
+			require.Equal(t, tc.wantCode, rec.Code)
+			require.True(t, json.Valid(rec.Body.Bytes()))
+			require.True(t, strings.Contains(rec.Body.String(), tc.wantBody), rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package subs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// TokenUnsubscribe is the purpose of the tokens embedded in the unsubscribe links.
+	TokenUnsubscribe = "unsubscribe"
+	// TokenConfirm is the purpose of the tokens embedded in the subscription confirmation links.
+	TokenConfirm = "confirm"
+)
+
+var (
+	// ErrInvalidToken is an error indicating that the token is malformed or its signature does not match.
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrExpiredToken is an error indicating that the token is no longer valid.
+	ErrExpiredToken = errors.New("token expired")
+
+	// ErrMissingSecret is an error indicating that no secret is configured for signing the tokens.
+	ErrMissingSecret = errors.New("missing token secret")
+)
+
+// TokenConfig defines how the tokens of the subscription links are signed.
+type TokenConfig struct {
+	// Secret is the HMAC key, it is required so the links stay valid across restarts.
+	Secret string
+	// TTL is the duration the token is valid for.
+	TTL time.Duration
+}
+
+// Signer issues and verifies the HMAC-signed tokens identifying a subscription for a purpose.
+type Signer struct {
+	key []byte
+	ttl time.Duration
+}
+
+// claims represents the signed content of a token.
+type claims struct {
+	Purpose string `json:"p"`
+	Email   string `json:"e"`
+	Base    string `json:"b"`
+	Quote   string `json:"q"`
+	Expires int64  `json:"x"`
+}
+
+// NewSigner creates a new Signer instance.
+func NewSigner(cfg TokenConfig) (*Signer, error) {
+	if cfg.Secret == "" || cfg.Secret == "-" {
+		return nil, ErrMissingSecret
+	}
+
+	return &Signer{key: []byte(cfg.Secret), ttl: cfg.TTL}, nil
+}
+
+// Sign returns the token identifying the subscription for the purpose valid for the configured TTL.
+func (s *Signer) Sign(purpose string, subs Subscription) (string, error) {
+	return s.SignWithin(purpose, subs, s.ttl)
+}
+
+// SignWithin returns the token identifying the subscription for the purpose valid for the ttl.
+func (s *Signer) SignWithin(purpose string, subs Subscription, ttl time.Duration) (string, error) {
+	if subs.Subscriber.Address == nil {
+		return "", ErrMissingEmail
+	}
+
+	data, err := json.Marshal(claims{
+		Purpose: purpose,
+		Email:   subs.Subscriber.Address.Address,
+		Base:    subs.Topic.Base,
+		Quote:   subs.Topic.Quote,
+		Expires: time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("encoding token: %w", err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+
+	return payload + "." + s.sign(payload), nil
+}
+
+// Verify checks the token was signed for the purpose and is not expired, it returns the subscription it identifies.
+func (s *Signer) Verify(purpose, token string) (Subscription, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.sign(payload))) {
+		return Subscription{}, ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	var c claims
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Subscription{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if c.Purpose != purpose {
+		return Subscription{}, fmt.Errorf("%w: issued for %s", ErrInvalidToken, c.Purpose)
+	}
+
+	if time.Now().Unix() > c.Expires {
+		return Subscription{}, ErrExpiredToken
+	}
+
+	return Subscription{
+		Subscriber: NewSubscriber(&mail.Address{Address: c.Email}),
+		Topic:      NewTopic(c.Base, c.Quote),
+	}, nil
+}
+
+func (s *Signer) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// LinksConfig defines the absolute URLs of the endpoints the links point at.
+type LinksConfig struct {
+	UnsubscribeURL string
+	ConfirmURL     string
+	// ConfirmWithin is the duration the confirmation link is valid for.
+	ConfirmWithin time.Duration
+}
+
+// Links creates the signed links embedded in the notifications.
+type Links struct {
+	signer *Signer
+	cfg    LinksConfig
+}
+
+// NewLinks creates a new Links instance.
+func NewLinks(signer *Signer, cfg LinksConfig) *Links {
+	return &Links{signer: signer, cfg: cfg}
+}
+
+// UnsubscribeLink returns the link the subscriber of the address like "Jon Doe <jon@example.com>"
+// unsubscribes from the topic with.
+func (l *Links) UnsubscribeLink(address, base, quote string) (string, error) {
+	return l.link(l.cfg.UnsubscribeURL, TokenUnsubscribe, l.signer.ttl, address, base, quote)
+}
+
+// ConfirmLink returns the link the subscriber of the address confirms the subscription to the topic with.
+func (l *Links) ConfirmLink(address, base, quote string) (string, error) {
+	return l.link(l.cfg.ConfirmURL, TokenConfirm, l.cfg.ConfirmWithin, address, base, quote)
+}
+
+func (l *Links) link(endpoint, purpose string, ttl time.Duration, address, base, quote string) (string, error) {
+	addr, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", fmt.Errorf("parsing address: %w", err)
+	}
+
+	token, err := l.signer.SignWithin(purpose, Subscription{Subscriber: NewSubscriber(addr), Topic: NewTopic(base, quote)}, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	return endpoint + "?" + url.Values{"token": {token}}.Encode(), nil
+}
@@ -0,0 +1,121 @@
+package subs_test
+
+import (
+	"net/mail"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/internal/subs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignerVerify(t *testing.T) {
+	sub := subs.Subscription{
+		Subscriber: subs.NewSubscriber(&mail.Address{Address: "user@example.com"}),
+		Topic:      subs.NewTopic("BTC", "UAH"),
+	}
+
+	tests := map[string]struct {
+		ttl     time.Duration
+		purpose string
+		tamper  func(string) string
+		wantErr error
+	}{
+		"valid": {
+			ttl:     time.Hour,
+			purpose: subs.TokenUnsubscribe,
+		},
+		"expired": {
+			ttl:     -time.Minute,
+			purpose: subs.TokenUnsubscribe,
+			wantErr: subs.ErrExpiredToken,
+		},
+		"other_purpose": {
+			ttl:     time.Hour,
+			purpose: "other",
+			wantErr: subs.ErrInvalidToken,
+		},
+		"tampered_payload": {
+			ttl:     time.Hour,
+			purpose: subs.TokenUnsubscribe,
+			tamper: func(token string) string {
+				return "e30" + token[strings.Index(token, "."):]
+			},
+			wantErr: subs.ErrInvalidToken,
+		},
+		"missing_signature": {
+			ttl:     time.Hour,
+			purpose: subs.TokenUnsubscribe,
+			tamper: func(token string) string {
+				return token[:strings.Index(token, ".")]
+			},
+			wantErr: subs.ErrInvalidToken,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			signer, err := subs.NewSigner(subs.TokenConfig{Secret: "secret", TTL: tc.ttl})
+			require.NoError(t, err)
+
+			token, err := signer.Sign(tc.purpose, sub)
+			require.NoError(t, err)
+
+			if tc.tamper != nil {
+				token = tc.tamper(token)
+			}
+
+			got, err := signer.Verify(subs.TokenUnsubscribe, token)
+			require.ErrorIs(t, err, tc.wantErr)
+
+			if tc.wantErr == nil {
+				require.Equal(t, sub.Key(), got.Key())
+			}
+		})
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	tests := map[string]struct {
+		secret  string
+		wantErr error
+	}{
+		"configured":  {secret: "secret"},
+		"empty":       {secret: "", wantErr: subs.ErrMissingSecret},
+		"placeholder": {secret: "-", wantErr: subs.ErrMissingSecret},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			_, err := subs.NewSigner(subs.TokenConfig{Secret: tc.secret, TTL: time.Hour})
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestLinksUnsubscribeLink(t *testing.T) {
+	signer, err := subs.NewSigner(subs.TokenConfig{Secret: "secret", TTL: time.Hour})
+	require.NoError(t, err)
+
+	links := subs.NewLinks(signer, subs.LinksConfig{UnsubscribeURL: "http://localhost/api/v1/unsubscribe"})
+
+	link, err := links.UnsubscribeLink(`"Jon Doe" <Jon@example.com>`, "btc", "uah")
+	require.NoError(t, err)
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	require.Equal(t, "/api/v1/unsubscribe", u.Path)
+
+	got, err := signer.Verify(subs.TokenUnsubscribe, u.Query().Get("token"))
+	require.NoError(t, err)
+	require.True(t, got.Matches(subs.Subscription{
+		Subscriber: subs.NewSubscriber(&mail.Address{Address: "jon@example.com"}),
+		Topic:      subs.NewTopic("BTC", "UAH"),
+	}))
+}
@@ -0,0 +1,196 @@
+package filestore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"reflect"
+	"sync"
+)
+
+// AppendLog is a concurrency-safe append-only store for any item type.
+// Items are stored as JSON lines in a single file named after the item type.
+type AppendLog[T any] struct {
+	mu  sync.Mutex
+	dir string
+	pth string
+}
+
+// NewAppendLog returns a new AppendLog storing items in the given directory.
+func NewAppendLog[T any](dir string) *AppendLog[T] {
+	name := reflect.TypeOf(*new(T)).Name()
+
+	return &AppendLog[T]{
+		dir: dir,
+		pth: path.Join(dir, name+".jsonl"),
+	}
+}
+
+// Append method appends the items of type T to the end of the log.
+// The last line torn by an interrupted write is dropped first, so the items are not appended to it.
+func (l *AppendLog[T]) Append(items ...T) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(l.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating path: %w", err)
+	}
+
+	const perm = 0644
+
+	file, err := os.OpenFile(l.pth, os.O_APPEND|os.O_CREATE|os.O_RDWR, perm)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+
+	defer file.Close()
+
+	if err := trimTorn(file); err != nil {
+		return fmt.Errorf("trimming log file: %w", err)
+	}
+
+	buf := bufio.NewWriter(file)
+	enc := json.NewEncoder(buf)
+
+	for i := range items {
+		if err := enc.Encode(items[i]); err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+	}
+
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("writing log file: %w", err)
+	}
+
+	return nil
+}
+
+// Replace method atomically replaces the whole log with the items,
+// so it can be compacted or keep the latest snapshot only.
+func (l *AppendLog[T]) Replace(items ...T) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(l.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating path: %w", err)
+	}
+
+	file, err := os.CreateTemp(l.dir, path.Base(l.pth)+".*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+
+	defer os.Remove(file.Name())
+
+	buf := bufio.NewWriter(file)
+	enc := json.NewEncoder(buf)
+
+	for i := range items {
+		if err := enc.Encode(items[i]); err != nil {
+			file.Close()
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+	}
+
+	if err := errors.Join(buf.Flush(), file.Close()); err != nil {
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), l.pth); err != nil {
+		return fmt.Errorf("replacing log file: %w", err)
+	}
+
+	return nil
+}
+
+// FetchAll method fetches all items of type T stored in the AppendLog in the order they were appended.
+// The last line lacking the newline is torn by an interrupted write, so it is skipped.
+func (l *AppendLog[T]) FetchAll() ([]T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.pth)
+	if err != nil {
+		return nil, fmt.Errorf("opening log file: %w", err)
+	}
+
+	defer file.Close()
+
+	var coll []T
+
+	rd := bufio.NewReader(file)
+
+	for {
+		line, err := rd.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("reading log file: %w", err)
+		}
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var item T
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, fmt.Errorf("decoding JSON: %w", err)
+		}
+
+		coll = append(coll, item)
+	}
+
+	return coll, nil
+}
+
+// trimTorn truncates the file after its last complete line, dropping the tail torn by an interrupted write.
+func trimTorn(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	end := info.Size()
+	last := make([]byte, 1)
+
+	if end == 0 {
+		return nil
+	}
+
+	if _, err := file.ReadAt(last, end-1); err != nil {
+		return err
+	}
+
+	if last[0] == '\n' {
+		return nil
+	}
+
+	const chunk = 4096
+
+	for end > 0 {
+		start := end - chunk
+		if start < 0 {
+			start = 0
+		}
+
+		buf := make([]byte, end-start)
+		if _, err := file.ReadAt(buf, start); err != nil {
+			return err
+		}
+
+		if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+			return file.Truncate(start + int64(i) + 1)
+		}
+
+		end = start
+	}
+
+	return file.Truncate(0)
+}
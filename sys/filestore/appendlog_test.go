@@ -0,0 +1,120 @@
+package filestore
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendLog(t *testing.T) {
+	type item struct {
+		Name  string
+		Value int
+	}
+
+	tests := map[string]struct {
+		batches [][]item
+		want    []item
+		wantErr error
+	}{
+		"Append single item": {
+			batches: [][]item{{{Name: "item1", Value: 1}}},
+			want:    []item{{Name: "item1", Value: 1}},
+		},
+		"Append duplicates in order": {
+			batches: [][]item{{{Name: "item1", Value: 1}, {Name: "item2", Value: 2}}, {{Name: "item1", Value: 1}}},
+			want:    []item{{Name: "item1", Value: 1}, {Name: "item2", Value: 2}, {Name: "item1", Value: 1}},
+		},
+		"Fetch from empty log": {
+			wantErr: os.ErrNotExist,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			log := NewAppendLog[item](t.TempDir())
+
+			for _, batch := range tc.batches {
+				require.NoError(t, log.Append(batch...))
+			}
+
+			got, err := log.FetchAll()
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestAppendLogReplace(t *testing.T) {
+	type item struct {
+		Name  string
+		Value int
+	}
+
+	log := NewAppendLog[item](t.TempDir())
+
+	require.NoError(t, log.Append(item{Name: "item1", Value: 1}, item{Name: "item2", Value: 2}))
+	require.NoError(t, log.Replace(item{Name: "item2", Value: 3}))
+
+	got, err := log.FetchAll()
+	require.NoError(t, err)
+	require.Equal(t, []item{{Name: "item2", Value: 3}}, got)
+
+	require.NoError(t, log.Replace())
+
+	got, err = log.FetchAll()
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestAppendLogTorn(t *testing.T) {
+	type item struct {
+		Name  string
+		Value int
+	}
+
+	tests := map[string]struct {
+		content string
+		want    []item
+		wantErr bool
+	}{
+		"Skip torn last line": {
+			content: "{\"Name\":\"item1\",\"Value\":1}\n{\"Name\":\"ite",
+			want:    []item{{Name: "item1", Value: 1}},
+		},
+		"Skip last line lacking newline": {
+			content: "{\"Name\":\"item1\",\"Value\":1}\n{\"Name\":\"item2\",\"Value\":2}",
+			want:    []item{{Name: "item1", Value: 1}},
+		},
+		"Torn only line": {
+			content: "{\"Name\":",
+		},
+		"Fail on malformed line in the middle": {
+			content: "{\"Name\":\n{\"Name\":\"item1\",\"Value\":1}\n",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			log := NewAppendLog[item](t.TempDir())
+			require.NoError(t, os.WriteFile(log.pth, []byte(tc.content), 0600))
+
+			got, err := log.FetchAll()
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+
+			require.NoError(t, log.Append(item{Name: "item3", Value: 3}))
+
+			got, err = log.FetchAll()
+			require.NoError(t, err)
+			require.Equal(t, append(tc.want, item{Name: "item3", Value: 3}), got)
+		})
+	}
+}
@@ -3,13 +3,18 @@ Package filestore offers a concurrency-safe generic store for any item type.
 Items are stored as JSON files in a
 specified directory, with each item type being stored in its own subdirectory.
 The package supports basic operations
-like storing a new item and fetching all stored items.
+like storing a new item and fetching all stored items,
+items implementing Keyer can also be fetched, updated and deleted by their key.
 
 Instances of FileStore are safe for concurrent use, achieved by using a mutex lock whenever
 accessing the file system.
-The name of the JSON file is hashed with the name of the item.
+The name of the JSON file is hashed with the name of the item,
+or with its key if the item implements Keyer, so items are told apart by the identity of their choice.
 If a file with the same name already exists, an error is returned.
 
+AppendLog is an append-only counterpart of FileStore keeping all items
+of a type as JSON lines in a single file, duplicates included.
+
 Example usage:
 
 	type Person struct {
@@ -33,6 +38,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -43,6 +49,11 @@ import (
 	"testing"
 )
 
+// Keyer is implemented by the items identified by a key rather than by all their fields.
+type Keyer interface {
+	Key() string
+}
+
 type FileStore[T any] struct {
 	mu  sync.Mutex
 	dir string
@@ -70,7 +81,7 @@ func (f *FileStore[T]) Store(item T) error {
 		return fmt.Errorf("creating path: %w", err)
 	}
 
-	pth := path.Join(f.dir, hash(item))
+	pth := path.Join(f.dir, name(item))
 	if _, err := os.Stat(pth); !os.IsNotExist(err) {
 		return os.ErrExist
 	}
@@ -133,6 +144,145 @@ func (f *FileStore[T]) FetchAll() ([]T, error) {
 	return coll, nil
 }
 
+// Fetch method returns the stored item of the same key as the given item implementing Keyer,
+// os.ErrNotExist is returned if there is none.
+func (f *FileStore[T]) Fetch(item T) (T, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var stored T
+
+	if _, ok := any(item).(Keyer); !ok {
+		return stored, os.ErrInvalid
+	}
+
+	pth, err := f.path(item)
+	if err != nil {
+		return stored, fmt.Errorf("fetching JSON file: %w", err)
+	}
+
+	data, err := os.ReadFile(pth)
+	if err != nil {
+		return stored, fmt.Errorf("reading JSON file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return stored, fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	return stored, nil
+}
+
+// Delete method removes the stored item, os.ErrNotExist is returned if there is none.
+// An item implementing Keyer is removed by its key, so the rest of its fields may differ from the stored ones.
+func (f *FileStore[T]) Delete(item T) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pth, err := f.path(item)
+	if err == nil {
+		err = os.Remove(pth)
+	}
+
+	if err != nil {
+		return fmt.Errorf("removing JSON file: %w", err)
+	}
+
+	return nil
+}
+
+// Update method overwrites the stored item implementing Keyer with the one of the same key,
+// os.ErrNotExist is returned if there is none.
+func (f *FileStore[T]) Update(item T) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := any(item).(Keyer); !ok {
+		return os.ErrInvalid
+	}
+
+	pth, err := f.path(item)
+	if err != nil {
+		return fmt.Errorf("updating JSON file: %w", err)
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+
+	// The item is written aside and renamed over the stored one, so it is never left half-written.
+	// The temp file is kept out of the item directory, so a leftover one is never fetched as an item.
+	file, err := os.CreateTemp(path.Dir(f.dir), "."+path.Base(f.dir)+".*")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(append(data, '\n'))
+	if err := errors.Join(err, file.Close()); err != nil {
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), pth); err != nil {
+		return fmt.Errorf("replacing JSON file: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the path of the file the item is stored in, os.ErrNotExist is returned if there is none.
+func (f *FileStore[T]) path(item T) (string, error) {
+	pth := path.Join(f.dir, name(item))
+
+	_, err := os.Stat(pth)
+	if errors.Is(err, fs.ErrNotExist) {
+		if k, ok := any(item).(Keyer); ok {
+			// Items stored before they implemented Keyer are named after all their fields.
+			return f.find(k.Key())
+		}
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return pth, nil
+}
+
+// find returns the path of the file holding the item with the key.
+func (f *FileStore[T]) find(key string) (string, error) {
+	ents, err := os.ReadDir(f.dir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, ent := range ents {
+		if ent.IsDir() {
+			continue
+		}
+
+		pth := path.Join(f.dir, ent.Name())
+
+		data, err := os.ReadFile(pth)
+		if err != nil {
+			return "", err
+		}
+
+		var item T
+		if err := json.Unmarshal(data, &item); err != nil {
+			continue
+		}
+
+		if k, ok := any(item).(Keyer); ok && k.Key() == key {
+			return pth, nil
+		}
+	}
+
+	return "", fs.ErrNotExist
+}
+
 func (f *FileStore[T]) StoreAll(items ...T) error {
 	for _, item := range items {
 		if err := f.Store(item); err != nil {
@@ -143,6 +293,15 @@ func (f *FileStore[T]) StoreAll(items ...T) error {
 	return nil
 }
 
+// name returns the name of the file the item is stored in.
+func name(item any) string {
+	if k, ok := item.(Keyer); ok {
+		return hash(k.Key())
+	}
+
+	return hash(item)
+}
+
 func hash(item any) string {
 	h := hmac.New(sha256.New, []byte("my_secret"))
 	h.Write(fmt.Append(nil, item))
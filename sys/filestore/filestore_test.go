@@ -2,6 +2,7 @@ package filestore
 
 import (
 	"os"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -99,3 +100,78 @@ func TestFetchAll(t *testing.T) {
 		})
 	}
 }
+
+type keyedItem struct {
+	Name  string
+	Value int
+}
+
+func (i keyedItem) Key() string { return i.Name }
+
+func TestDelete(t *testing.T) {
+	tests := map[string]struct {
+		stored  []keyedItem
+		delete  keyedItem
+		want    []keyedItem
+		wantErr error
+	}{
+		"Delete by key": {
+			stored: []keyedItem{{Name: "item1", Value: 1}, {Name: "item2", Value: 2}},
+			delete: keyedItem{Name: "item1"},
+			want:   []keyedItem{{Name: "item2", Value: 2}},
+		},
+		"Delete missing item": {
+			stored:  []keyedItem{{Name: "item1", Value: 1}},
+			delete:  keyedItem{Name: "item2"},
+			want:    []keyedItem{{Name: "item1", Value: 1}},
+			wantErr: os.ErrNotExist,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			store, teardown := TestSetup[keyedItem](t)
+			defer teardown()
+
+			require.NoError(t, store.StoreAll(tc.stored...))
+			require.ErrorIs(t, store.Store(keyedItem{Name: "item1", Value: 3}), os.ErrExist)
+
+			require.ErrorIs(t, store.Delete(tc.delete), tc.wantErr)
+
+			got, err := store.FetchAll()
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.want, got)
+		})
+	}
+}
+
+func TestUpdateFetch(t *testing.T) {
+	store, teardown := TestSetup[keyedItem](t)
+	defer teardown()
+
+	require.ErrorIs(t, store.Update(keyedItem{Name: "item1", Value: 2}), os.ErrNotExist)
+
+	require.NoError(t, store.Store(keyedItem{Name: "item1", Value: 1}))
+	require.NoError(t, store.Update(keyedItem{Name: "item1", Value: 2}))
+
+	item, err := store.Fetch(keyedItem{Name: "item1"})
+	require.NoError(t, err)
+	require.Equal(t, keyedItem{Name: "item1", Value: 2}, item)
+
+	_, err = store.Fetch(keyedItem{Name: "item2"})
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	got, err := store.FetchAll()
+	require.NoError(t, err)
+	require.Equal(t, []keyedItem{{Name: "item1", Value: 2}}, got)
+
+	info, err := os.Stat(path.Join(store.dir, name(keyedItem{Name: "item1"})))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	ents, err := os.ReadDir(path.Dir(store.dir))
+	require.NoError(t, err)
+	require.Len(t, ents, 1, "temp file left behind")
+}
@@ -9,6 +9,9 @@ var (
 	ErrClientError  = errors.New("client error")
 	ErrServerError  = errors.New("server error")
 	ErrUnknownError = errors.New("unknown error")
+
+	// ErrUnauthorized is an error indicating that the request does not carry the valid credentials.
+	ErrUnauthorized = errors.New("unauthorized")
 )
 
 // RequestError type is an error that is used to indicate that a request failed.
@@ -26,6 +29,11 @@ func (e *RequestError) Error() string {
 	return e.Value.Error()
 }
 
+// Unwrap returns the underlying error, so it can be matched with errors.Is.
+func (e *RequestError) Unwrap() error {
+	return e.Value
+}
+
 // ErrorResponse type is an error that is used to indicate that a response failed.
 type ErrorResponse struct {
 	Error   string         `json:"error"`
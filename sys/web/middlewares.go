@@ -2,6 +2,7 @@ package web
 
 import (
 	"context"
+	"crypto/subtle"
 	"net/http"
 	"runtime/debug"
 	"strings"
@@ -82,7 +83,7 @@ func WithRecover(log *logger.Logger) Middleware {
 // WithCORS is a middleware that ensures that the HTTP
 // method of the request matches the provided method.
 func WithCORS(origins ...string) Middleware {
-	methods := []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+	methods := []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
 	headers := []string{"Accept", "Authorization", "Accept-Encoding", "Content-Type", "Content-Length", "X-CSRF-Token", "X-Request-ID"}
 
 	return func(h Handler) Handler {
@@ -96,6 +97,22 @@ func WithCORS(origins ...string) Middleware {
 	}
 }
 
+// WithBearerAuth is a middleware that lets through only the requests authorized with the bearer token,
+// no request is let through if the token is empty.
+func WithBearerAuth(token string) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			got, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				rw.Header().Set("WWW-Authenticate", "Bearer")
+				return NewRequestError(ErrUnauthorized, http.StatusUnauthorized)
+			}
+
+			return h(ctx, rw, req)
+		}
+	}
+}
+
 // WithErrors is a middleware that wraps an HTTP h to provide centralized error handling.
 func WithErrors(log *logger.Logger) Middleware {
 	return func(h Handler) Handler {
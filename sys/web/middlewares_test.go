@@ -125,6 +125,7 @@ func TestMiddlewares(t *testing.T) {
 
 		require.NoError(t, err)
 		require.Equal(t, `{"test":"test"}`, rw.Body.String())
+		require.Contains(t, rw.Header().Get("Access-Control-Allow-Methods"), http.MethodPatch)
 	})
 
 	t.Run("WithErrors", func(t *testing.T) {
@@ -141,3 +142,43 @@ func TestMiddlewares(t *testing.T) {
 		require.JSONEq(t, `{"error":"unknown error"}`, rw.Body.String())
 	})
 }
+
+func TestWithBearerAuth(t *testing.T) {
+	tests := map[string]struct {
+		token    string
+		header   string
+		wantCode int
+	}{
+		"authorized":        {token: "secret", header: "Bearer secret", wantCode: http.StatusOK},
+		"missing_header":    {token: "secret", wantCode: http.StatusUnauthorized},
+		"wrong_token":       {token: "secret", header: "Bearer other", wantCode: http.StatusUnauthorized},
+		"wrong_scheme":      {token: "secret", header: "Basic secret", wantCode: http.StatusUnauthorized},
+		"empty_token_never": {header: "Bearer ", wantCode: http.StatusUnauthorized},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				rw.WriteHeader(http.StatusOK)
+				return nil
+			}
+
+			rw := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", tc.header)
+
+			err := web.WithBearerAuth(tc.token)(h)(context.Background(), rw, req)
+			if tc.wantCode == http.StatusOK {
+				require.NoError(t, err)
+				return
+			}
+
+			reqErr, ok := web.IsError[*web.RequestError](err)
+			require.True(t, ok)
+			require.Equal(t, tc.wantCode, reqErr.StatusCode)
+			require.ErrorIs(t, err, web.ErrUnauthorized)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package web
 import (
 	"net/http"
 	"strings"
+
+	"github.com/julienschmidt/httprouter"
 )
 
 // FromQuery retrieves the value of a specified
@@ -16,6 +18,12 @@ func FromQuery(req *http.Request, key string) string {
 	return val
 }
 
+// FromPath retrieves the value of a specified
+// route path parameter like ":id" or empty string if no value is found.
+func FromPath(req *http.Request, key string) string {
+	return httprouter.ParamsFromContext(req.Context()).ByName(key)
+}
+
 // FromContext retrieves a pointer of a specific type
 // from the request's context or nil if no value is found.
 func FromContext[T, K any](req *http.Request, key K) *T {
@@ -0,0 +1,117 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrStreamUnsupported = errors.New("streaming unsupported")
+
+// EventStream writes server-sent events to the client, flushing every event immediately.
+type EventStream struct {
+	rw   http.ResponseWriter
+	rc   *http.ResponseController
+	done chan struct{}
+}
+
+// NewEventStream starts the stream of server-sent events responding with the headers,
+// overriding the content type set by WithJSON.
+// The write deadline of the server is lifted, so the stream lives until the client or the server ends it.
+func NewEventStream(ctx context.Context, rw http.ResponseWriter) (*EventStream, error) {
+	if !canFlush(rw) {
+		return nil, fmt.Errorf("%w: %T", ErrStreamUnsupported, rw)
+	}
+
+	rc := http.NewResponseController(rw)
+
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return nil, fmt.Errorf("lifting write deadline: %w", err)
+	}
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.Header().Set("X-Accel-Buffering", "no")
+	rw.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		return nil, fmt.Errorf("flushing headers: %w", err)
+	}
+
+	s := EventStream{rw: rw, rc: rc, done: make(chan struct{})}
+
+	// The request context is cancelled once the handler returns, so the goroutine never outlives the stream.
+	go func() {
+		defer close(s.done)
+
+		select {
+		case <-ctx.Done():
+		case <-Closing(ctx):
+		}
+	}()
+
+	return &s, nil
+}
+
+// Done returns the channel closed when the client has gone or the web application ends the streams.
+func (s *EventStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Send sends the event with data encoded to JSON, id and name are omitted when empty.
+func (s *EventStream) Send(id, name string, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	var msg strings.Builder
+
+	if id != "" {
+		fmt.Fprintf(&msg, "id: %s\n", id)
+	}
+
+	if name != "" {
+		fmt.Fprintf(&msg, "event: %s\n", name)
+	}
+
+	fmt.Fprintf(&msg, "data: %s\n\n", body)
+
+	return s.write(msg.String())
+}
+
+// Comment sends the comment ignored by clients, it keeps the idle connection open.
+func (s *EventStream) Comment(text string) error {
+	return s.write(": " + text + "\n\n")
+}
+
+func (s *EventStream) write(msg string) error {
+	if _, err := s.rw.Write([]byte(msg)); err != nil {
+		return fmt.Errorf("writing event: %w", err)
+	}
+
+	if err := s.rc.Flush(); err != nil {
+		return fmt.Errorf("flushing event: %w", err)
+	}
+
+	return nil
+}
+
+// canFlush reports whether the writer or any writer it wraps supports flushing.
+func canFlush(rw http.ResponseWriter) bool {
+	for {
+		switch w := rw.(type) {
+		case http.Flusher:
+			return true
+		case interface{ Unwrap() http.ResponseWriter }:
+			rw = w.Unwrap()
+		default:
+			return false
+		}
+	}
+}
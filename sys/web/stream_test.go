@@ -0,0 +1,102 @@
+package web_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream(t *testing.T) {
+	tests := map[string]struct {
+		send func(*web.EventStream) error
+		want string
+	}{
+		"event": {
+			send: func(s *web.EventStream) error { return s.Send("1", "rate", map[string]int{"rate": 2}) },
+			want: "id: 1\nevent: rate\ndata: {\"rate\":2}\n\n",
+		},
+		"data_only": {
+			send: func(s *web.EventStream) error { return s.Send("", "", "hello") },
+			want: "data: \"hello\"\n\n",
+		},
+		"comment": {
+			send: func(s *web.EventStream) error { return s.Comment("heartbeat") },
+			want: ": heartbeat\n\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			rw := httptest.NewRecorder()
+			rw.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+			stream, err := web.NewEventStream(ctx, rw)
+			require.NoError(t, err)
+			require.NoError(t, tc.send(stream))
+
+			require.Equal(t, "text/event-stream", rw.Header().Get("Content-Type"))
+			require.True(t, rw.Flushed)
+			require.Equal(t, tc.want, rw.Body.String())
+		})
+	}
+}
+
+func TestWebCloseEndsStreams(t *testing.T) {
+	w := web.New(nil)
+
+	w.Handle(http.MethodGet, "/", "stream", func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		stream, err := web.NewEventStream(ctx, rw)
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Comment("open"); err != nil {
+			return err
+		}
+
+		<-stream.Done()
+
+		return stream.Comment("closed")
+	})
+
+	srv := httptest.NewServer(w)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/stream")
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	sc := bufio.NewScanner(resp.Body)
+	require.True(t, sc.Scan())
+	require.Equal(t, ": open", sc.Text())
+
+	w.Close()
+	w.Close()
+
+	done := make(chan []string)
+
+	go func() {
+		var lines []string
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		done <- lines
+	}()
+
+	select {
+	case lines := <-done:
+		require.Equal(t, []string{"", ": closed", ""}, lines)
+	case <-time.After(time.Second):
+		t.Fatal("stream is not ended")
+	}
+}
@@ -6,6 +6,7 @@ The Web struct is the main type of the package and consists of:
 	mux: The httprouter router that is used to route HTTP requests to handlers.
 	mws: A slice of middlewares that are applied to all HTTP requests before they are handled by the h function.
 	sig: A channel that is used to receive shutdown signals.
+	done: A channel that is closed when long-lived streams have to end.
 */
 package web
 
@@ -14,6 +15,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"sync"
 	"syscall"
 
 	"github.com/julienschmidt/httprouter"
@@ -24,17 +26,23 @@ type Handler func(ctx context.Context, rw http.ResponseWriter, req *http.Request
 
 // Web is a web framework.
 type Web struct {
-	mux *httprouter.Router
-	mws []Middleware
-	sig chan os.Signal
+	mux  *httprouter.Router
+	mws  []Middleware
+	sig  chan os.Signal
+	done chan struct{}
+	once sync.Once
 }
 
+// closingKey is the context key the channel closed on Close is passed to the handlers with.
+type closingKey struct{}
+
 // New creates a new Web struct.
 func New(sig chan os.Signal, mds ...Middleware) *Web {
 	return &Web{
-		mux: httprouter.New(),
-		mws: mds,
-		sig: sig,
+		mux:  httprouter.New(),
+		mws:  mds,
+		sig:  sig,
+		done: make(chan struct{}),
 	}
 }
 
@@ -49,7 +57,9 @@ func (w *Web) Handle(meth string, grp string, pth string, h Handler, mws ...Midd
 
 	w.mux.HandlerFunc(meth, path.Join(grp, pth),
 		func(rw http.ResponseWriter, req *http.Request) {
-			if err := h(req.Context(), rw, req); err != nil {
+			ctx := context.WithValue(req.Context(), closingKey{}, (<-chan struct{})(w.done))
+
+			if err := h(ctx, rw, req); err != nil {
 				if _, ok := IsError[*ShutdownError](err); ok {
 					w.Shutdown()
 					return
@@ -62,3 +72,16 @@ func (w *Web) Handle(meth string, grp string, pth string, h Handler, mws ...Midd
 func (w *Web) Shutdown() {
 	w.sig <- syscall.SIGTERM
 }
+
+// Close tells the long-lived streams to end, so the server shutdown does not wait for them.
+// It is safe to call Close more than once.
+func (w *Web) Close() {
+	w.once.Do(func() { close(w.done) })
+}
+
+// Closing returns the channel closed when the web application ends the long-lived streams,
+// or nil channel blocking forever when the handler is called outside of Web.
+func Closing(ctx context.Context) <-chan struct{} {
+	done, _ := ctx.Value(closingKey{}).(<-chan struct{})
+	return done
+}
@@ -0,0 +1,487 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // SHA-1 is mandated by RFC 6455 for the handshake.
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes and close codes defined by RFC 6455.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+
+	CloseNormal        = 1000
+	CloseGoingAway     = 1001
+	CloseProtocolError = 1002
+	CloseTooLarge      = 1009
+)
+
+const (
+	wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// wsMaxMessage is the largest message accepted from the client.
+	wsMaxMessage = 1 << 16
+	// wsWriteTimeout limits writing a single frame, so a stalled client does not hold the writer forever.
+	wsWriteTimeout = 10 * time.Second
+	// wsMaxControl is the largest payload of a control frame.
+	wsMaxControl = 125
+	// wsLen16 and wsLen64 mark the payload length given in the following 2 or 8 bytes.
+	wsLen16 = 126
+	wsLen64 = 127
+	// wsCodeSize is the size of the close code leading the close frame payload.
+	wsCodeSize = 2
+)
+
+var (
+	ErrNotWebSocket   = errors.New("not a websocket handshake")
+	ErrOrigin         = errors.New("websocket origin not allowed")
+	ErrWebSocketClose = errors.New("websocket closed")
+	ErrProtocol       = errors.New("websocket protocol error")
+	ErrMessageSize    = errors.New("websocket message too large")
+)
+
+// CloseError is returned by ReadMessage when the peer closes the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("%s: %d %s", ErrWebSocketClose, e.Code, e.Reason)
+}
+
+// Unwrap returns ErrWebSocketClose, so it can be matched with errors.Is.
+func (e *CloseError) Unwrap() error {
+	return ErrWebSocketClose
+}
+
+// WebSocket is a WebSocket connection, either the server side made by Upgrade or the client side made by DialWebSocket.
+// Messages are read from a single goroutine, writes are safe for concurrent use.
+type WebSocket struct {
+	conn net.Conn
+	br   *bufio.Reader
+	// client masks the frames it sends, as required from the client side.
+	client bool
+
+	readTimeout time.Duration
+
+	wmu    sync.Mutex
+	closed bool
+}
+
+// Upgrade performs the WebSocket handshake taking over the connection from the HTTP server.
+// Browsers are let in from the same host or the origins given, "*" allowing any,
+// while the clients sending no Origin header are not restricted.
+// The server write deadline is lifted, so the connection lives until either side closes it.
+func Upgrade(rw http.ResponseWriter, req *http.Request, origins ...string) (*WebSocket, error) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+
+	if req.Method != http.MethodGet ||
+		!headerContains(req.Header, "Connection", "upgrade") ||
+		!headerContains(req.Header, "Upgrade", "websocket") ||
+		req.Header.Get("Sec-WebSocket-Version") != "13" ||
+		key == "" {
+		return nil, NewRequestError(ErrNotWebSocket, http.StatusBadRequest)
+	}
+
+	if !originAllowed(req, origins) {
+		return nil, NewRequestError(fmt.Errorf("%w: %s", ErrOrigin, req.Header.Get("Origin")), http.StatusForbidden)
+	}
+
+	conn, brw, err := http.NewResponseController(rw).Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijacking connection: %w", err)
+	}
+
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("lifting deadline: %w", err)
+	}
+
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+
+	if _, err := conn.Write([]byte(resp)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("writing handshake: %w", err)
+	}
+
+	return &WebSocket{conn: conn, br: brw.Reader}, nil
+}
+
+// DialWebSocket opens the client side of the WebSocket connection to the ws:// URL.
+func DialWebSocket(ctx context.Context, rawURL string) (*WebSocket, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing url: %w", err)
+	}
+
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return nil, fmt.Errorf("dialing: %w", err)
+	}
+
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("generating key: %w", err)
+	}
+
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+
+	req := "GET " + u.RequestURI() + " HTTP/1.1\r\n" +
+		"Host: " + u.Host + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+
+	if _, err := conn.Write([]byte(req)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("writing handshake: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("reading handshake: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %s", ErrNotWebSocket, resp.Status)
+	}
+
+	return &WebSocket{conn: conn, br: br, client: true}, nil
+}
+
+// SetReadTimeout sets how long ReadMessage waits for any frame from the peer, pongs included, zero means no limit.
+func (ws *WebSocket) SetReadTimeout(d time.Duration) {
+	ws.readTimeout = d
+}
+
+// ReadMessage reads the next text or binary message.
+// Pings are answered and pongs are consumed on the way, the close frame is answered and returned as CloseError.
+func (ws *WebSocket) ReadMessage() ([]byte, error) {
+	var msg []byte
+
+	for {
+		if ws.readTimeout > 0 {
+			if err := ws.conn.SetReadDeadline(time.Now().Add(ws.readTimeout)); err != nil {
+				return nil, fmt.Errorf("setting read deadline: %w", err)
+			}
+		}
+
+		fin, op, payload, err := ws.readFrame()
+		if err != nil {
+			if errors.Is(err, ErrProtocol) || errors.Is(err, ErrMessageSize) {
+				code := CloseProtocolError
+				if errors.Is(err, ErrMessageSize) {
+					code = CloseTooLarge
+				}
+
+				_ = ws.CloseWith(code, err.Error())
+			}
+
+			return nil, err
+		}
+
+		switch op {
+		case opPing:
+			if err := ws.write(opPong, payload); err != nil {
+				return nil, err
+			}
+
+			continue
+
+		case opPong:
+			continue
+
+		case opClose:
+			cerr := CloseError{Code: CloseNormal}
+			if len(payload) >= wsCodeSize {
+				cerr.Code = int(binary.BigEndian.Uint16(payload))
+				cerr.Reason = string(payload[wsCodeSize:])
+			}
+
+			_ = ws.CloseWith(cerr.Code, "")
+
+			return nil, &cerr
+
+		case opText, opBinary:
+			if msg != nil {
+				return nil, fmt.Errorf("%w: new message within fragmented one", ErrProtocol)
+			}
+
+			msg = append([]byte{}, payload...)
+
+		case opContinuation:
+			if msg == nil {
+				return nil, fmt.Errorf("%w: continuation without message", ErrProtocol)
+			}
+
+			if len(msg)+len(payload) > wsMaxMessage {
+				_ = ws.CloseWith(CloseTooLarge, ErrMessageSize.Error())
+				return nil, ErrMessageSize
+			}
+
+			msg = append(msg, payload...)
+
+		default:
+			_ = ws.CloseWith(CloseProtocolError, "unknown opcode")
+			return nil, fmt.Errorf("%w: unknown opcode %#x", ErrProtocol, op)
+		}
+
+		if fin {
+			return msg, nil
+		}
+	}
+}
+
+// WriteMessage writes the text message.
+func (ws *WebSocket) WriteMessage(data []byte) error {
+	return ws.write(opText, data)
+}
+
+// WriteJSON writes the value encoded to JSON as the text message.
+func (ws *WebSocket) WriteJSON(data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+
+	return ws.write(opText, body)
+}
+
+// Ping sends the ping the peer answers with pong, it keeps the connection alive and detects dead peers.
+func (ws *WebSocket) Ping() error {
+	return ws.write(opPing, nil)
+}
+
+// Close closes the connection normally.
+func (ws *WebSocket) Close() error {
+	return ws.CloseWith(CloseNormal, "")
+}
+
+// CloseWith sends the close frame with the code and reason and closes the connection.
+// It is safe to call it more than once.
+func (ws *WebSocket) CloseWith(code int, reason string) error {
+	payload := make([]byte, wsCodeSize, wsCodeSize+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+
+	ws.wmu.Lock()
+	defer ws.wmu.Unlock()
+
+	if ws.closed {
+		return nil
+	}
+
+	ws.closed = true
+
+	return errors.Join(ws.writeFrame(opClose, payload), ws.conn.Close())
+}
+
+func (ws *WebSocket) write(op byte, payload []byte) error {
+	ws.wmu.Lock()
+	defer ws.wmu.Unlock()
+
+	if ws.closed {
+		return ErrWebSocketClose
+	}
+
+	return ws.writeFrame(op, payload)
+}
+
+// writeFrame writes a single frame masked on the client side, it must be called with the write lock held.
+func (ws *WebSocket) writeFrame(op byte, payload []byte) error {
+	if err := ws.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return fmt.Errorf("setting write deadline: %w", err)
+	}
+
+	frame, err := newFrame(op, payload, ws.client)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ws.conn.Write(frame); err != nil {
+		return fmt.Errorf("writing frame: %w", err)
+	}
+
+	return nil
+}
+
+// readFrame reads a single frame, only the frames from the client are masked.
+func (ws *WebSocket) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(ws.br, head[:]); err != nil {
+		return false, 0, nil, fmt.Errorf("reading frame: %w", err)
+	}
+
+	fin, op = head[0]&0x80 != 0, head[0]&0x0F
+
+	if head[0]&0x70 != 0 {
+		return false, 0, nil, fmt.Errorf("%w: reserved bits set", ErrProtocol)
+	}
+
+	masked := head[1]&0x80 != 0
+	if masked == ws.client {
+		return false, 0, nil, fmt.Errorf("%w: invalid masking", ErrProtocol)
+	}
+
+	size := uint64(head[1] & 0x7F)
+
+	switch size {
+	case wsLen16:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.br, ext[:]); err != nil {
+			return false, 0, nil, fmt.Errorf("reading frame: %w", err)
+		}
+
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+
+	case wsLen64:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.br, ext[:]); err != nil {
+			return false, 0, nil, fmt.Errorf("reading frame: %w", err)
+		}
+
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if op >= opClose && (size > wsMaxControl || !fin) {
+		return false, 0, nil, fmt.Errorf("%w: invalid control frame", ErrProtocol)
+	}
+
+	if size > wsMaxMessage {
+		return false, 0, nil, ErrMessageSize
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(ws.br, mask[:]); err != nil {
+			return false, 0, nil, fmt.Errorf("reading frame: %w", err)
+		}
+	}
+
+	payload = make([]byte, size)
+	if _, err := io.ReadFull(ws.br, payload); err != nil {
+		return false, 0, nil, fmt.Errorf("reading frame: %w", err)
+	}
+
+	if masked {
+		maskPayload(payload, mask)
+	}
+
+	return fin, op, payload, nil
+}
+
+// newFrame builds the final frame, masking the payload when asked to.
+func newFrame(op byte, payload []byte, masked bool) ([]byte, error) {
+	const maxHeader = 14
+
+	var maskBit byte
+	if masked {
+		maskBit = 0x80
+	}
+
+	buf := make([]byte, 0, len(payload)+maxHeader)
+	buf = append(buf, 0x80|op)
+
+	switch size := len(payload); {
+	case size <= wsMaxControl:
+		buf = append(buf, maskBit|byte(size))
+	case size <= 0xFFFF:
+		buf = append(buf, maskBit|wsLen16)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(size))
+	default:
+		buf = append(buf, maskBit|wsLen64)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(size))
+	}
+
+	if !masked {
+		return append(buf, payload...), nil
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return nil, fmt.Errorf("generating mask: %w", err)
+	}
+
+	buf = append(buf, mask[:]...)
+	start := len(buf)
+	buf = append(buf, payload...)
+	maskPayload(buf[start:], mask)
+
+	return buf, nil
+}
+
+// maskPayload masks or unmasks the payload in place.
+func maskPayload(payload []byte, mask [4]byte) {
+	for i := range payload {
+		payload[i] ^= mask[i%len(mask)]
+	}
+}
+
+// acceptKey computes the Sec-WebSocket-Accept value for the Sec-WebSocket-Key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID)) //nolint:gosec
+
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// originAllowed reports whether the Origin header is absent, of the same host or one of the origins.
+func originAllowed(req *http.Request, origins []string) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, req.Host) {
+		return true
+	}
+
+	for i := range origins {
+		if origins[i] == "*" || strings.EqualFold(strings.TrimSuffix(origins[i], "/"), origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// headerContains reports whether the comma-separated header contains the token, case-insensitively.
+func headerContains(h http.Header, name, token string) bool {
+	for _, val := range h.Values(name) {
+		for _, item := range strings.Split(val, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GenesisEducationKyiv/main-project-delveper/sys/web"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocket(t *testing.T) {
+	serverErr := make(chan error, 1)
+
+	w := web.New(nil)
+	w.Handle(http.MethodGet, "/", "ws", func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		ws, err := web.Upgrade(rw, req)
+		if err != nil {
+			return err
+		}
+
+		defer ws.Close()
+
+		for {
+			msg, err := ws.ReadMessage()
+			if err != nil {
+				serverErr <- err
+				return nil
+			}
+
+			if err := ws.WriteMessage(append([]byte("echo: "), msg...)); err != nil {
+				serverErr <- err
+				return nil
+			}
+		}
+	})
+
+	srv := httptest.NewServer(w)
+	defer srv.Close()
+
+	tests := map[string]struct {
+		send          []string
+		want          []string
+		wantServerErr error
+	}{
+		"echo": {
+			send:          []string{"hello", strings.Repeat("x", 300)},
+			want:          []string{"echo: hello", "echo: " + strings.Repeat("x", 300)},
+			wantServerErr: web.ErrWebSocketClose,
+		},
+		"too_large": {
+			send:          []string{strings.Repeat("x", 1<<17)},
+			wantServerErr: web.ErrMessageSize,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ws, err := web.DialWebSocket(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws")
+			require.NoError(t, err)
+
+			require.NoError(t, ws.Ping())
+
+			for i := range tc.send {
+				require.NoError(t, ws.WriteMessage([]byte(tc.send[i])))
+			}
+
+			for i := range tc.want {
+				msg, err := ws.ReadMessage()
+				require.NoError(t, err)
+				require.Equal(t, tc.want[i], string(msg))
+			}
+
+			if len(tc.want) > 0 {
+				require.NoError(t, ws.Close())
+			} else {
+				_, err := ws.ReadMessage()
+				var cerr *web.CloseError
+				require.ErrorAs(t, err, &cerr)
+				require.Equal(t, web.CloseTooLarge, cerr.Code)
+			}
+
+			select {
+			case err := <-serverErr:
+				require.ErrorIs(t, err, tc.wantServerErr)
+			case <-time.After(time.Second):
+				t.Fatal("server is not done")
+			}
+		})
+	}
+}
+
+func TestUpgradeRejected(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	rw := httptest.NewRecorder()
+
+	_, err := web.Upgrade(rw, req)
+	require.ErrorIs(t, err, web.ErrNotWebSocket)
+
+	rerr, ok := web.IsError[*web.RequestError](err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusBadRequest, rerr.StatusCode)
+}
+
+func TestUpgradeOrigin(t *testing.T) {
+	tests := map[string]struct {
+		origin  string
+		origins []string
+		wantErr bool
+	}{
+		"no_origin":      {origins: []string{"https://app.example.com"}},
+		"same_host":      {origin: "http://example.com"},
+		"allowed":        {origin: "https://app.example.com", origins: []string{"https://app.example.com/"}},
+		"any":            {origin: "https://evil.example.net", origins: []string{"*"}},
+		"not_allowed":    {origin: "https://evil.example.net", origins: []string{"https://app.example.com"}, wantErr: true},
+		"none_allowed":   {origin: "https://evil.example.net", wantErr: true},
+		"malformed_host": {origin: "://example.com", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/ws", nil)
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Sec-WebSocket-Version", "13")
+			req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+
+			// The recorder cannot be hijacked, so the allowed handshakes fail right after the origin check.
+			_, err := web.Upgrade(httptest.NewRecorder(), req, tc.origins...)
+			require.Error(t, err)
+
+			if !tc.wantErr {
+				require.NotErrorIs(t, err, web.ErrOrigin)
+				return
+			}
+
+			require.ErrorIs(t, err, web.ErrOrigin)
+
+			rerr, ok := web.IsError[*web.RequestError](err)
+			require.True(t, ok)
+			require.Equal(t, http.StatusForbidden, rerr.StatusCode)
+		})
+	}
+}
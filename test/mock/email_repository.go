@@ -22,9 +22,24 @@ var _ subs.SubscriberRepository = &SubscriberRepositoryMock{}
 //			AddFunc: func(contextMoqParam context.Context, subscription subs.Subscription) error {
 //				panic("mock out the Add method")
 //			},
+//			GetFunc: func(ctx context.Context, id string) (subs.Subscription, error) {
+//				panic("mock out the Get method")
+//			},
 //			ListFunc: func(contextMoqParam context.Context) ([]subs.Subscription, error) {
 //				panic("mock out the List method")
 //			},
+//			ListAllFunc: func(contextMoqParam context.Context) ([]subs.Subscription, error) {
+//				panic("mock out the ListAll method")
+//			},
+//			ListPendingFunc: func(contextMoqParam context.Context) ([]subs.Subscription, error) {
+//				panic("mock out the ListPending method")
+//			},
+//			RemoveFunc: func(contextMoqParam context.Context, subscription subs.Subscription) error {
+//				panic("mock out the Remove method")
+//			},
+//			UpdateFunc: func(contextMoqParam context.Context, subscription subs.Subscription) error {
+//				panic("mock out the Update method")
+//			},
 //		}
 //
 //		// use mockedSubscriberRepository in code that requires subs.SubscriberRepository
@@ -35,9 +50,24 @@ type SubscriberRepositoryMock struct {
 	// AddFunc mocks the Add method.
 	AddFunc func(contextMoqParam context.Context, subscription subs.Subscription) error
 
+	// GetFunc mocks the Get method.
+	GetFunc func(ctx context.Context, id string) (subs.Subscription, error)
+
 	// ListFunc mocks the List method.
 	ListFunc func(contextMoqParam context.Context) ([]subs.Subscription, error)
 
+	// ListAllFunc mocks the ListAll method.
+	ListAllFunc func(contextMoqParam context.Context) ([]subs.Subscription, error)
+
+	// ListPendingFunc mocks the ListPending method.
+	ListPendingFunc func(contextMoqParam context.Context) ([]subs.Subscription, error)
+
+	// RemoveFunc mocks the Remove method.
+	RemoveFunc func(contextMoqParam context.Context, subscription subs.Subscription) error
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(contextMoqParam context.Context, subscription subs.Subscription) error
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// Add holds details about calls to the Add method.
@@ -47,14 +77,50 @@ type SubscriberRepositoryMock struct {
 			// Subscription is the subscription argument value.
 			Subscription subs.Subscription
 		}
+		// Get holds details about calls to the Get method.
+		Get []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Id is the id argument value.
+			Id string
+		}
 		// List holds details about calls to the List method.
 		List []struct {
 			// ContextMoqParam is the contextMoqParam argument value.
 			ContextMoqParam context.Context
 		}
+		// ListAll holds details about calls to the ListAll method.
+		ListAll []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+		}
+		// ListPending holds details about calls to the ListPending method.
+		ListPending []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+		}
+		// Remove holds details about calls to the Remove method.
+		Remove []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Subscription is the subscription argument value.
+			Subscription subs.Subscription
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Subscription is the subscription argument value.
+			Subscription subs.Subscription
+		}
 	}
-	lockAdd  sync.RWMutex
-	lockList sync.RWMutex
+	lockAdd         sync.RWMutex
+	lockGet         sync.RWMutex
+	lockList        sync.RWMutex
+	lockListAll     sync.RWMutex
+	lockListPending sync.RWMutex
+	lockRemove      sync.RWMutex
+	lockUpdate      sync.RWMutex
 }
 
 // Add calls AddFunc.
@@ -93,6 +159,42 @@ func (mock *SubscriberRepositoryMock) AddCalls() []struct {
 	return calls
 }
 
+// Get calls GetFunc.
+func (mock *SubscriberRepositoryMock) Get(ctx context.Context, id string) (subs.Subscription, error) {
+	if mock.GetFunc == nil {
+		panic("SubscriberRepositoryMock.GetFunc: method is nil but SubscriberRepository.Get was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Id  string
+	}{
+		Ctx: ctx,
+		Id:  id,
+	}
+	mock.lockGet.Lock()
+	mock.calls.Get = append(mock.calls.Get, callInfo)
+	mock.lockGet.Unlock()
+	return mock.GetFunc(ctx, id)
+}
+
+// GetCalls gets all the calls that were made to Get.
+// Check the length with:
+//
+//	len(mockedSubscriberRepository.GetCalls())
+func (mock *SubscriberRepositoryMock) GetCalls() []struct {
+	Ctx context.Context
+	Id  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Id  string
+	}
+	mock.lockGet.RLock()
+	calls = mock.calls.Get
+	mock.lockGet.RUnlock()
+	return calls
+}
+
 // List calls ListFunc.
 func (mock *SubscriberRepositoryMock) List(contextMoqParam context.Context) ([]subs.Subscription, error) {
 	if mock.ListFunc == nil {
@@ -124,3 +226,139 @@ func (mock *SubscriberRepositoryMock) ListCalls() []struct {
 	mock.lockList.RUnlock()
 	return calls
 }
+
+// ListAll calls ListAllFunc.
+func (mock *SubscriberRepositoryMock) ListAll(contextMoqParam context.Context) ([]subs.Subscription, error) {
+	if mock.ListAllFunc == nil {
+		panic("SubscriberRepositoryMock.ListAllFunc: method is nil but SubscriberRepository.ListAll was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+	}{
+		ContextMoqParam: contextMoqParam,
+	}
+	mock.lockListAll.Lock()
+	mock.calls.ListAll = append(mock.calls.ListAll, callInfo)
+	mock.lockListAll.Unlock()
+	return mock.ListAllFunc(contextMoqParam)
+}
+
+// ListAllCalls gets all the calls that were made to ListAll.
+// Check the length with:
+//
+//	len(mockedSubscriberRepository.ListAllCalls())
+func (mock *SubscriberRepositoryMock) ListAllCalls() []struct {
+	ContextMoqParam context.Context
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+	}
+	mock.lockListAll.RLock()
+	calls = mock.calls.ListAll
+	mock.lockListAll.RUnlock()
+	return calls
+}
+
+// ListPending calls ListPendingFunc.
+func (mock *SubscriberRepositoryMock) ListPending(contextMoqParam context.Context) ([]subs.Subscription, error) {
+	if mock.ListPendingFunc == nil {
+		panic("SubscriberRepositoryMock.ListPendingFunc: method is nil but SubscriberRepository.ListPending was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+	}{
+		ContextMoqParam: contextMoqParam,
+	}
+	mock.lockListPending.Lock()
+	mock.calls.ListPending = append(mock.calls.ListPending, callInfo)
+	mock.lockListPending.Unlock()
+	return mock.ListPendingFunc(contextMoqParam)
+}
+
+// ListPendingCalls gets all the calls that were made to ListPending.
+// Check the length with:
+//
+//	len(mockedSubscriberRepository.ListPendingCalls())
+func (mock *SubscriberRepositoryMock) ListPendingCalls() []struct {
+	ContextMoqParam context.Context
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+	}
+	mock.lockListPending.RLock()
+	calls = mock.calls.ListPending
+	mock.lockListPending.RUnlock()
+	return calls
+}
+
+// Remove calls RemoveFunc.
+func (mock *SubscriberRepositoryMock) Remove(contextMoqParam context.Context, subscription subs.Subscription) error {
+	if mock.RemoveFunc == nil {
+		panic("SubscriberRepositoryMock.RemoveFunc: method is nil but SubscriberRepository.Remove was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Subscription    subs.Subscription
+	}{
+		ContextMoqParam: contextMoqParam,
+		Subscription:    subscription,
+	}
+	mock.lockRemove.Lock()
+	mock.calls.Remove = append(mock.calls.Remove, callInfo)
+	mock.lockRemove.Unlock()
+	return mock.RemoveFunc(contextMoqParam, subscription)
+}
+
+// RemoveCalls gets all the calls that were made to Remove.
+// Check the length with:
+//
+//	len(mockedSubscriberRepository.RemoveCalls())
+func (mock *SubscriberRepositoryMock) RemoveCalls() []struct {
+	ContextMoqParam context.Context
+	Subscription    subs.Subscription
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Subscription    subs.Subscription
+	}
+	mock.lockRemove.RLock()
+	calls = mock.calls.Remove
+	mock.lockRemove.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *SubscriberRepositoryMock) Update(contextMoqParam context.Context, subscription subs.Subscription) error {
+	if mock.UpdateFunc == nil {
+		panic("SubscriberRepositoryMock.UpdateFunc: method is nil but SubscriberRepository.Update was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Subscription    subs.Subscription
+	}{
+		ContextMoqParam: contextMoqParam,
+		Subscription:    subscription,
+	}
+	mock.lockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	mock.lockUpdate.Unlock()
+	return mock.UpdateFunc(contextMoqParam, subscription)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//
+//	len(mockedSubscriberRepository.UpdateCalls())
+func (mock *SubscriberRepositoryMock) UpdateCalls() []struct {
+	ContextMoqParam context.Context
+	Subscription    subs.Subscription
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Subscription    subs.Subscription
+	}
+	mock.lockUpdate.RLock()
+	calls = mock.calls.Update
+	mock.lockUpdate.RUnlock()
+	return calls
+}